@@ -0,0 +1,62 @@
+//go:build !bytez_minimal
+
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package bytez
+
+import (
+	"strconv"
+	"strings"
+)
+
+// AlignColumn formats sizes for a column of a table, like those of df and du, in the largest
+// units in which the largest size is at least one, with one decimal unless all sizes are a whole
+// number of the units, and padded on the left to the same width, so that the decimal points line
+// up:
+//
+//	   1.5GiB
+//	1023.0GiB
+//	   0.1GiB
+//
+// The units are decimal if all sizes are multiples of 500, as AsStr chooses, and binary
+// otherwise. Sizes are rounded to the nearest decimal, with halves rounded to even.
+func AlignColumn(sizes []Size) []string {
+	var largest uint64
+	values, units := valuesBase10, unitsBase10
+	for _, size := range sizes {
+		largest = max(largest, uint64(size))
+		if size%500 != 0 {
+			values, units = valuesBase2, unitsBase2
+		}
+	}
+	idx := len(values) - 1
+	for idx > 0 && largest < values[idx] {
+		idx--
+	}
+
+	prec := 0
+	for _, size := range sizes {
+		if uint64(size)%values[idx] != 0 {
+			prec = 1
+			break
+		}
+	}
+
+	strs := make([]string, len(sizes))
+	width := 0
+	var buf []byte
+	for i, size := range sizes {
+		val := roundUnits(uint64(size), values[idx], prec, RoundHalfEven)
+		buf = strconv.AppendFloat(buf[:0], val, 'f', prec, 64)
+		strs[i] = string(append(buf, units[idx]...))
+		width = max(width, len(strs[i]))
+	}
+	for i, str := range strs {
+		strs[i] = strings.Repeat(" ", width-len(str)) + str
+	}
+	return strs
+}
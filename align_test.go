@@ -0,0 +1,41 @@
+//go:build !bytez_minimal
+
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package bytez
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlignColumn(t *testing.T) {
+	var tests = []struct {
+		sizes []Size
+		out   []string
+	}{
+		{[]Size{}, []string{}},
+		{[]Size{0}, []string{"0"}},
+		{[]Size{Size(1536 * Mebibyte), Size(1023 * Gibibyte), Size(100 * Mebibyte)},
+			[]string{"   1.5GiB", "1023.0GiB", "   0.1GiB"}},
+		{[]Size{Size(4 * Gibibyte), Size(16 * Gibibyte), 0}, []string{" 4GiB", "16GiB", " 0GiB"}},
+		{[]Size{Size(1500 * Megabyte), Size(250 * Megabyte)}, []string{"1.5gb", "0.2gb"}},
+		{[]Size{Size(2 * Mebibyte), 1}, []string{"2.0MiB", "0.0MiB"}},
+		{[]Size{512, 1, 999}, []string{"512", "  1", "999"}},
+		{[]Size{1048575, 1024}, []string{"1024.0KiB", "   1.0KiB"}},
+	}
+
+	for _, test := range tests {
+		out := AlignColumn(test.sizes)
+		if testing.Verbose() {
+			fmt.Printf("%v --> %q\n", test.sizes, out)
+		}
+		require.Equal(t, test.out, out)
+	}
+}
@@ -0,0 +1,98 @@
+//go:build !bytez_minimal
+
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package bytez
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// maxBuckets is the largest number of boundaries returned by Buckets, far more than histograms
+// use, to reject factors so close to 1 that the ladder would exhaust memory.
+const maxBuckets = 10000
+
+// Buckets returns a ladder of histogram bucket boundaries, like [1KiB, 4KiB, 16KiB, ..., 1GiB],
+// for configuring Prometheus, OpenTelemetry, and similar histograms of payload sizes. The ladder
+// starts at start and each boundary is about factor times the previous one, up to and including
+// end.
+//
+// To keep the boundaries readable, each one is rounded to the nearest whole or half unit, so
+// that AsStr formats it exactly. Binary units are used if start is a multiple of 512 but not of
+// 500, and decimal units otherwise. With a factor like 2 or 4, boundaries that start at a power
+// of the base are exact. An error is returned if there would be more than 10000 boundaries.
+func Buckets(start, end Size, factor float64) ([]Size, error) {
+	if start == 0 {
+		return nil, errors.New("start must be greater than zero")
+	} else if end < start {
+		return nil, errors.New("end must not be less than start")
+	} else if !(factor > 1) || math.IsInf(factor, 0) {
+		return nil, errors.New("factor must be greater than 1")
+	} else if math.Log(float64(end)/float64(start))/math.Log(factor) >= maxBuckets {
+		return nil, fmt.Errorf("factor %v is too small: more than %d buckets", factor, maxBuckets)
+	}
+
+	base := 1000.0
+	if start%512 == 0 && start%500 != 0 {
+		base = 1024
+	}
+
+	buckets := []Size{start}
+	for i := 1; ; i++ {
+		val := roundReadable(float64(start)*math.Pow(factor, float64(i)), base)
+		if val > float64(end) {
+			break
+		}
+
+		// The conversion is clamped since float64(end) may round up to 1<<64.
+		next := end
+		if val < float64(end) {
+			next = Size(val)
+		}
+		prev := buckets[len(buckets)-1]
+		if next <= prev {
+			if prev == end {
+				break
+			}
+			next = prev + 1
+		}
+		buckets = append(buckets, next)
+	}
+	return buckets, nil
+}
+
+// BucketsFloat64 is like Buckets but returns the boundaries as float64 values, which is the type
+// used by most histogram APIs.
+func BucketsFloat64(start, end Size, factor float64) ([]float64, error) {
+	buckets, err := Buckets(start, end, factor)
+	if err != nil {
+		return nil, err
+	}
+
+	floats := make([]float64, len(buckets))
+	for i, b := range buckets {
+		floats[i] = float64(b)
+	}
+	return floats, nil
+}
+
+// roundReadable rounds val to the nearest multiple of half of the largest unit of the given base
+// not greater than val.
+func roundReadable(val float64, base float64) float64 {
+	unit := 1.0
+	for unit*base <= val {
+		unit *= base
+	}
+
+	step := unit / 2
+	if step < 1 {
+		step = 1
+	}
+	return math.Round(val/step) * step
+}
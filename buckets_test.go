@@ -0,0 +1,76 @@
+//go:build !bytez_minimal
+
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package bytez
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuckets(t *testing.T) {
+	var negative = []struct {
+		start, end Size
+		factor     float64
+	}{
+		{0, 1024, 2},
+		{2048, 1024, 2},
+		{1024, 2048, 1},
+		{1024, 2048, -2},
+		{1024, Size(Tebibyte), 1.0000001},
+		{1, 1<<64 - 1, 1.001},
+	}
+
+	for _, test := range negative {
+		_, err := Buckets(test.start, test.end, test.factor)
+		require.Error(t, err)
+	}
+
+	var tests = []struct {
+		start, end Size
+		factor     float64
+		out        []string
+	}{
+		{Size(Kibibyte), Size(Gibibyte), 4,
+			[]string{"1KiB", "4KiB", "16KiB", "64KiB", "256KiB", "1MiB", "4MiB", "16MiB", "64MiB",
+				"256MiB", "1GiB"}},
+		{Size(Kilobyte), Size(Megabyte), 10, []string{"1kb", "10kb", "100kb", "1mb"}},
+		{Size(Kibibyte), Size(64 * Kibibyte), 2.5, []string{"1KiB", "2.5KiB", "6.5KiB", "15.5KiB", "39KiB"}},
+		{100, 1000, 1.5, []string{"100", "150", "225", "338", "506", "759", "1kb"}},
+		{Size(Exbibyte), 1<<64 - 1, 2,
+			[]string{"1EiB", "2EiB", "4EiB", "8EiB", "18446744073709551615"}},
+	}
+
+	for _, test := range tests {
+		buckets, err := Buckets(test.start, test.end, test.factor)
+		require.NoError(t, err)
+
+		var out []string
+		for _, b := range buckets {
+			out = append(out, b.AsStr())
+		}
+		if testing.Verbose() {
+			fmt.Printf("%v..%v x%v --> %v\n", test.start, test.end, test.factor, out)
+		}
+		require.Equal(t, test.out, out)
+	}
+
+	// Factors close to 1 give consecutive sizes where rounding makes them equal.
+	buckets, err := Buckets(1, 1000, 1.001)
+	require.NoError(t, err)
+	require.Equal(t, 1000, len(buckets))
+	for i, b := range buckets {
+		require.Equal(t, Size(i+1), b)
+	}
+
+	floats, err := BucketsFloat64(Size(Kibibyte), Size(16*Kibibyte), 4)
+	require.NoError(t, err)
+	require.Equal(t, []float64{1024, 4096, 16384}, floats)
+}
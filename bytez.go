@@ -20,13 +20,19 @@
 //
 // When converting from numbers to strings, this package uses the two-letter lowercase units
 // (e.g. "mb") for powers of 10 and the three-letter mixed case (e.g. "MiB") for powers of 2.
+//
+// Building with the bytez_minimal tag produces a minimal version of the package, suitable for
+// TinyGo and WebAssembly targets where binary size matters. It provides the constants, the Size
+// type, AsInt, AsIntBytes, AsStr, and AppendStr, implemented without maps, Unicode tables, or
+// package fmt; the rest of the API is only available in the default build.
 package bytez
 
 import (
 	"errors"
+	"math/bits"
 	"strconv"
 	"strings"
-	"unicode"
+	"unsafe"
 )
 
 // Size can be used to automatically marshal and unmarshal byte size specifications to and from
@@ -53,36 +59,91 @@ const (
 	Exbibyte        = Pebibyte * 1024
 )
 
-var unitMap = map[string]uint64{
-	"k": Kilobyte, "kb": Kilobyte, "kB": Kilobyte,
-	"m": Megabyte, "mb": Megabyte, "mB": Megabyte,
-	"g": Gigabyte, "gb": Gigabyte, "gB": Gigabyte,
-	"t": Terabyte, "tb": Terabyte, "tB": Terabyte,
-	"p": Petabyte, "pb": Petabyte, "pB": Petabyte,
-	"e": Exabyte, "eb": Exabyte, "eB": Exabyte,
-
-	"K": Kibibyte, "KB": Kibibyte, "Kb": Kibibyte, "Ki": Kibibyte, "KiB": Kibibyte,
-	"M": Mebibyte, "MB": Mebibyte, "Mb": Mebibyte, "Mi": Mebibyte, "MiB": Mebibyte,
-	"G": Gibibyte, "GB": Gibibyte, "Gb": Gibibyte, "Gi": Gibibyte, "GiB": Gibibyte,
-	"T": Tebibyte, "TB": Tebibyte, "Tb": Tebibyte, "Ti": Tebibyte, "TiB": Tebibyte,
-	"P": Pebibyte, "PB": Pebibyte, "Pb": Pebibyte, "Pi": Pebibyte, "PiB": Pebibyte,
-	"E": Exbibyte, "EB": Exbibyte, "Eb": Exbibyte, "Ei": Exbibyte, "EiB": Exbibyte,
-}
-
 var unitsBase2 = []string{"", "KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}
 var unitsBase10 = []string{"", "kb", "mb", "gb", "tb", "pb", "eb"}
 var valuesBase2 = []uint64{1, Kibibyte, Mebibyte, Gibibyte, Tebibyte, Pebibyte, Exbibyte}
 var valuesBase10 = []uint64{1, Kilobyte, Megabyte, Gigabyte, Terabyte, Petabyte, Exabyte}
+var wordsBase2 = []string{"byte", "kibibyte", "mebibyte", "gibibyte", "tebibyte", "pebibyte", "exbibyte"}
+var wordsBase10 = []string{"byte", "kilobyte", "megabyte", "gigabyte", "terabyte", "petabyte", "exabyte"}
+
+// bitPrefixes are the SI prefixes of the decimal bit units, like "kbit" and "Mbit".
+const bitPrefixes = "kMGTPE"
+
+// Errors that describe why a size could not be parsed. They are the Err of the ParseErrors
+// returned by AsInt and Parser.Parse, so callers can check for them with errors.Is. They are
+// preallocated so that failing to parse allocates only the ParseError.
+var (
+	// ErrNoNumber is returned when the size does not start with a number.
+	ErrNoNumber = errors.New("no number in string")
+
+	// ErrUnderscore is returned for underscores that do not separate digits.
+	ErrUnderscore = errors.New("misplaced underscore: underscores must be between digits")
+
+	// ErrInvalidFraction is returned for a decimal point not followed by digits.
+	ErrInvalidFraction = errors.New("invalid fractional part")
+
+	// ErrMissingUnits is returned for numbers that are not a whole number of bytes without
+	// units, like "1.5".
+	ErrMissingUnits = errors.New("missing units")
+
+	// ErrInvalidDelimiter is returned for characters other than a space between the number and
+	// the units.
+	ErrInvalidDelimiter = errors.New("invalid delimiter")
+
+	// ErrInvalidUnits is returned for unknown units.
+	ErrInvalidUnits = errors.New("invalid units")
+
+	// ErrOverflow is returned for sizes that do not fit in 64 bits.
+	ErrOverflow = errors.New("size too large")
+
+	// ErrGrouping is returned for digit groups that do not have three digits.
+	ErrGrouping = errors.New("misplaced digit group separator")
+)
+
+// ParseError describes a size that could not be parsed. It is the type of the errors returned by
+// AsInt and Parser.Parse.
+type ParseError struct {
+	// Input is the text that was parsed, and Offset the byte offset in Input of the number or
+	// units that could not be parsed.
+	Input  string
+	Offset int
+
+	// Err is the reason parsing failed.
+	Err error
+
+	// Suggestion is the valid units most similar to invalid units, like "MiB" for "MIB", if any.
+	Suggestion string
+}
 
-// MarshalText implements the encoding.TextMarshaler interface. The size is formatted as a string
-// using the largest units possible. Returned error is always nil.
+// Error returns the reason parsing failed, followed by the suggestion, if any.
+func (e *ParseError) Error() string {
+	if e.Suggestion != "" {
+		return e.Err.Error() + ": did you mean \"" + e.Suggestion + "\"?"
+	}
+	return e.Err.Error()
+}
+
+// Unwrap returns Err.
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// MarshalText implements the encoding.TextMarshaler interface. The size is formatted exactly as
+// AsStr does by default, using the largest units possible, whatever the default format is, so
+// that UnmarshalText reads it back as the same size. Returned error is always nil.
 func (sz Size) MarshalText() ([]byte, error) {
-	return []byte(AsStr(uint64(sz))), nil
+	return sz.AppendText(nil)
+}
+
+// AppendText implements the encoding.TextAppender interface, appending the size formatted like
+// MarshalText to dst. Returned error is always nil.
+func (sz Size) AppendText(dst []byte) ([]byte, error) {
+	return appendStr(dst, uint64(sz)), nil
 }
 
 // UnmarshalText implements the encoding.TextUnmarshaler interface.
 func (sz *Size) UnmarshalText(bytes []byte) error {
-	val, err := AsInt(string(bytes))
+	val, err := AsIntBytes(bytes)
 	if err != nil {
 		return err
 	}
@@ -96,98 +157,449 @@ func (sz Size) AsStr() string {
 	return AsStr(uint64(sz))
 }
 
+// String implements the fmt.Stringer interface, formatting the size like AsStr.
+func (sz Size) String() string {
+	return AsStr(uint64(sz))
+}
+
 // AsStr accepts a number of bytes, like 4194304, and returns the byte size as a string,
-// like "4MiB". The function tries to return a value that uses one of the supported units but it
-// is not guaranteed to do so.
+// like "4MiB". The size is formatted exactly, using the largest units in which it is a whole or
+// half number, like "1.5MiB" or "1250kb". Multiples of 500 use decimal units and other multiples
+// of 512 use binary units; other sizes are formatted as plain numbers.
+//
+// In the default build, the format can be changed for the whole program with SetDefaultFormat,
+// or from the BYTEZ_FORMAT environment variable with ConfigureFromEnv. Use AsCanonicalStr for
+// output that must not depend on them.
 func AsStr(size uint64) string {
-	if size < 1000 {
-		return strconv.FormatUint(size, 10)
-	}
+	var buf [24]byte
+	return string(appendDefault(buf[:0], size))
+}
+
+// AppendStr is like AsStr but appends the formatted size to dst and returns the extended buffer,
+// so that sizes can be formatted without allocating memory when dst has enough capacity.
+func AppendStr(dst []byte, size uint64) []byte {
+	return appendDefault(dst, size)
+}
+
+// appendStr appends size to dst in the default format of AsStr and returns the extended buffer.
+func appendStr(dst []byte, size uint64) []byte {
+	return appendExact(dst, size, 0, "", nil)
+}
 
-	var base uint64
-	var values []uint64
-	var units []string
+// appendExact appends size to dst in the largest units in which it is a whole or half number,
+// with sep between the number and the units. base is 2 or 10, or 0 to use base 10 for multiples
+// of 500 and base 2 for other multiples of 512, appending other sizes as plain numbers. If names
+// is not nil, it replaces the names of the units of the base, and may omit the largest ones.
+func appendExact(dst []byte, size uint64, base int, sep string, names []string) []byte {
+	if base == 0 {
+		if size%500 == 0 {
+			base = 10
+		} else if size%512 == 0 {
+			base = 2
+		} else {
+			return strconv.AppendUint(dst, size, 10)
+		}
+	}
 
-	if size%500 == 0 {
-		base = 1000
-		values = valuesBase10
-		units = unitsBase10
-	} else if size%512 == 0 {
-		base = 1024
-		values = valuesBase2
-		units = unitsBase2
-	} else {
-		return strconv.FormatUint(size, 10)
+	values, units := valuesBase10, unitsBase10
+	if base == 2 {
+		values, units = valuesBase2, unitsBase2
+	}
+	if names != nil {
+		values, units = values[:len(names)], names
 	}
 
-	var idx int
-	for sz := size; sz >= base; sz /= base {
-		idx++
+	idx := len(values) - 1
+	for ; idx > 0; idx-- {
+		if size >= values[idx] && size%(values[idx]/2) == 0 {
+			break
+		}
+	}
+	if idx == 0 {
+		return strconv.AppendUint(dst, size, 10)
 	}
 
-	str := strconv.FormatUint(size/values[idx], 10)
+	dst = strconv.AppendUint(dst, size/values[idx], 10)
 	if size%values[idx] != 0 {
-		str += ".5"
+		dst = append(dst, ".5"...)
 	}
-	str += units[idx]
-
-	return str
+	dst = append(dst, sep...)
+	return append(dst, units[idx]...)
 }
 
 // AsInt accepts a byte size, like "4MiB", and returns the exact number of bytes, like 4194304.
-// The leading number should be a whole number, but as a special case the fractions ".0" and ".5"
-// are allowed, like "1.5mb" to indicate 1,500,000 bytes. A single space is allowed between
-// the number and the units.
+// Sizes that do not fit in 64 bits are rejected. Errors are of type *ParseError, which gives the
+// offset of the problem in str and suggests valid units for misspelled ones.
+//
+// The number may have a decimal fraction, like "1.5mb" to indicate 1,500,000 bytes or "2.75GiB",
+// and an exponent, like "1e9" or "1.5e6 KiB". Fractions of a byte are rounded to the nearest whole
+// number of bytes, with halves rounded to even, like "0.0015kb" to 2, but numbers without units
+// must be whole numbers of bytes. Whole numbers may also be given in hexadecimal, octal, or binary
+// with the prefixes of Go literals, like "0x100000" or "0x40 KiB"; since hexadecimal digits include
+// letters, units starting with A to F must follow a space.
+//
+// Underscores may be used to separate digits, as in Go literals, like "1_048_576", and commas to
+// separate groups of three digits in the whole number, like "1,048,576" or "12,288 KiB".
+//
+// A single space is allowed between the number and the units, which may also be lowercase full
+// words, like "4 megabytes", "2 gibibytes", or "512 bytes". "B" is bytes, like "4096B". Bit units,
+// like "100Mbit" or "64 Kibit", are converted to bytes by dividing by 8.
+//
+// Whitespace around the size is ignored, but any other text is rejected, including a sign, like
+// "+4GiB", and punctuation, like "4GiB.". In the default build, parsers with WithLenient skip such
+// text, and parsers with WithStrict also reject the variations of syntax described above.
 func AsInt(str string) (uint64, error) {
-	var num uint64
-	var idx int
+	val, offset, err := asInt(str)
+	if err != nil {
+		return 0, newParseError(str, offset, err)
+	}
+	return val, nil
+}
 
-	str = strings.Trim(str, " \t\r\n")
-	for idx = 0; idx < len(str); idx++ {
-		if str[idx] < '0' || str[idx] > '9' {
-			break
-		} else {
-			num = num*10 + uint64(str[idx]-'0')
-		}
+// AsIntBytes is like AsInt but parses a byte slice, without converting it to a string, so that
+// parsing does not allocate memory unless it fails. The slice is not retained.
+func AsIntBytes(b []byte) (uint64, error) {
+	val, offset, err := asInt(unsafe.String(unsafe.SliceData(b), len(b)))
+	if err != nil {
+		return 0, newParseError(string(b), offset, err)
 	}
+	return val, nil
+}
 
-	if idx == 0 {
-		return 0, errors.New("no number in string")
+// newParseError returns the ParseError of AsInt for err at the given offset in str.
+func newParseError(str string, offset int, err error) error {
+	e := &ParseError{Input: str, Offset: offset, Err: err}
+	if err == ErrInvalidUnits {
+		e.Suggestion = suggestUnits(strings.TrimRight(str[offset:], " \t\r\n"), suggestedUnits)
+	}
+	return e
+}
+
+// asInt returns the size specified by str, or the error and its offset in str.
+func asInt(str string) (uint64, int, error) {
+	trimmed := strings.TrimLeft(str, " \t\r\n")
+	lead := len(str) - len(trimmed)
+	str = strings.TrimRight(trimmed, " \t\r\n")
+	num, idx, err := scanNumber(str, ',', '.')
+	if err != nil {
+		return 0, lead + idx, err
 	}
 
 	// If the number has no units label, it is an exact number of bytes.
 	if idx == len(str) {
-		return num, nil
+		val, err := num.integer()
+		if err == ErrMissingUnits {
+			return 0, lead + idx, err
+		}
+		return val, lead, err
+	}
+
+	// A single space, not a tab or two spaces, is allowed.
+	if str[idx] == ' ' {
+		idx++
 	}
 
-	// Special case: allow ".5" to specify half units like 2.5GiB, and ".0" for parity.
-	var addHalf uint64
-	if str[idx] == '.' {
-		if idx < len(str)-1 && str[idx:idx+2] == ".5" {
-			addHalf = 1
-			idx += 2
-		} else if idx < len(str)-1 && str[idx:idx+2] == ".0" {
-			idx += 2
+	if str[idx:] == "" {
+		return 0, lead + idx, ErrMissingUnits
+	} else if !isLetter(str[idx]) {
+		return 0, lead + idx, ErrInvalidDelimiter
+	} else if val, ok := lookupUnit(str[idx:]); ok {
+		val, err := num.bytes(val)
+		return val, lead, err
+	}
+	return 0, lead + idx, ErrInvalidUnits
+}
+
+// number is a decimal number scanned by scanNumber. Its value is given by the digits in text,
+// which may be separated by underscores, group separators, and a decimal point, with the decimal
+// point placed after the first point digits. Numbers with many digits and large exponents are
+// represented exactly, so they are rounded only once, when converted to bytes.
+type number struct {
+	text  string
+	point int
+
+	// Numbers with a base prefix, like "0x40", are whole numbers stored in value.
+	value    uint64
+	prefixed bool
+}
+
+// maxExponent limits exponents so that they cannot overflow the point of a number. Any exponent
+// this large results in a size that is too large or rounds to zero.
+const maxExponent = 1000
+
+// scanNumber scans the number at the start of str, a whole number optionally followed by a
+// decimal point and a fraction, and by an exponent, with underscores allowed between digits.
+// Unless group is 0, the whole number may also be split into groups of three digits by the group
+// separator, like "1,048,576". The decimal point is given by point, which is normally a period.
+// It returns the number and the index of the first byte following it.
+func scanNumber(str string, group, point byte) (number, int, error) {
+	if len(str) > 2 && str[0] == '0' {
+		if base := prefixBase(str[1]); base != 0 {
+			if _, ok := digitValue(str[2], base); ok {
+				return scanPrefixed(str, base)
+			}
+		}
+	}
+
+	var num number
+	var idx, run, groups int
+	var underscores bool
+
+	for ; idx < len(str); idx++ {
+		if str[idx] == '_' {
+			// As in Go literals, an underscore may separate digits for readability.
+			if idx == 0 || idx == len(str)-1 || !isDigit(str[idx-1]) || !isDigit(str[idx+1]) {
+				return num, idx, ErrUnderscore
+			}
+			underscores = true
+		} else if group != 0 && str[idx] == group && idx > 0 && idx < len(str)-1 &&
+			isDigit(str[idx-1]) && isDigit(str[idx+1]) {
+			// All groups but the first must have exactly three digits.
+			if run > 3 || (groups > 0 && run != 3) {
+				return num, idx, ErrGrouping
+			}
+			groups++
+			run = 0
+		} else if !isDigit(str[idx]) {
+			break
 		} else {
-			return 0, errors.New("invalid fractional part")
+			num.point++
+			run++
 		}
 	}
 
-	// A single space, not a tab or two spaces, is allowed.
-	if idx < len(str) && str[idx] == ' ' {
+	if idx == 0 {
+		return num, idx, ErrNoNumber
+	} else if groups > 0 && (run != 3 || underscores) {
+		return num, idx, ErrGrouping
+	}
+
+	if idx < len(str) && str[idx] == point {
 		idx++
+		start := idx
+		for ; idx < len(str); idx++ {
+			if str[idx] == '_' {
+				if idx == len(str)-1 || !isDigit(str[idx-1]) || !isDigit(str[idx+1]) {
+					return num, idx, ErrUnderscore
+				}
+			} else if !isDigit(str[idx]) {
+				break
+			}
+		}
+		if idx == start {
+			return num, idx, ErrInvalidFraction
+		}
 	}
+	num.text = str[:idx]
 
-	if str[idx:] == "" {
-		return 0, errors.New("missing units")
-	} else if !unicode.IsLetter(rune(str[idx])) {
-		return 0, errors.New("invalid delimiter")
-	} else if val, ok := unitMap[str[idx:]]; ok {
-		num *= val
-		num += val / 2 * addHalf
-	} else {
-		return 0, errors.New("invalid units")
+	// An exponent must have digits, so that "1e" and "1eb" still mean exabytes.
+	if idx < len(str) && (str[idx] == 'e' || str[idx] == 'E') {
+		end := idx + 1
+		neg := false
+		if end < len(str) && (str[end] == '+' || str[end] == '-') {
+			neg = str[end] == '-'
+			end++
+		}
+		if end < len(str) && isDigit(str[end]) {
+			exp := 0
+			for ; end < len(str) && isDigit(str[end]); end++ {
+				if exp < maxExponent {
+					exp = exp*10 + int(str[end]-'0')
+				}
+			}
+			if exp > maxExponent {
+				exp = maxExponent
+			}
+			if neg {
+				exp = -exp
+			}
+			num.point += exp
+			idx = end
+		}
 	}
+	return num, idx, nil
+}
+
+// prefixBase returns the base indicated by the letter of a base prefix, like 16 for "0x", or 0 if
+// b is not such a letter.
+func prefixBase(b byte) uint64 {
+	switch b {
+	case 'x', 'X':
+		return 16
+	case 'o', 'O':
+		return 8
+	case 'b', 'B':
+		return 2
+	}
+	return 0
+}
+
+// digitValue returns the value of b as a digit in the given base.
+func digitValue(b byte, base uint64) (uint64, bool) {
+	var val uint64
+	switch {
+	case isDigit(b):
+		val = uint64(b - '0')
+	case b >= 'a' && b <= 'f':
+		val = uint64(b-'a') + 10
+	case b >= 'A' && b <= 'F':
+		val = uint64(b-'A') + 10
+	default:
+		return 0, false
+	}
+	return val, val < base
+}
+
+// scanPrefixed scans a whole number with a base prefix, like "0x40", at the start of str.
+func scanPrefixed(str string, base uint64) (number, int, error) {
+	num := number{prefixed: true}
+	idx := 2
+	for ; idx < len(str); idx++ {
+		if str[idx] == '_' {
+			if idx == len(str)-1 {
+				return num, idx, ErrUnderscore
+			}
+			_, before := digitValue(str[idx-1], base)
+			_, after := digitValue(str[idx+1], base)
+			if !before || !after {
+				return num, idx, ErrUnderscore
+			}
+			continue
+		}
+
+		digit, ok := digitValue(str[idx], base)
+		if !ok {
+			break
+		}
+		hi, lo := bits.Mul64(num.value, base)
+		lo, carry := bits.Add64(lo, digit, 0)
+		if hi != 0 || carry != 0 {
+			return num, idx, ErrOverflow
+		}
+		num.value = lo
+	}
+	num.text = str[:idx]
+	return num, idx, nil
+}
+
+// Fractions of a byte left over by number.mul, relative to one half.
+const (
+	fracZero = iota
+	fracBelowHalf
+	fracHalf
+	fracAboveHalf
+)
+
+// mul returns the whole number of bytes in num units of the given size, and the class of the
+// fraction of a byte left over.
+func (num number) mul(unit uint64) (uint64, int, error) {
+	if num.prefixed {
+		hi, val := bits.Mul64(num.value, unit)
+		if hi != 0 {
+			return 0, 0, ErrOverflow
+		}
+		return val, fracZero, nil
+	}
+
+	// The whole part is accumulated from the digits before the point.
+	var whole uint64
+	var digits int
+	for i := 0; i < len(num.text); i++ {
+		if !isDigit(num.text[i]) {
+			continue
+		}
+		if digits < num.point {
+			hi, lo := bits.Mul64(whole, 10)
+			lo, carry := bits.Add64(lo, uint64(num.text[i]-'0'), 0)
+			if hi != 0 || carry != 0 {
+				return 0, 0, ErrOverflow
+			}
+			whole = lo
+		}
+		digits++
+	}
+	for i := digits; i < num.point && whole != 0; i++ {
+		hi, lo := bits.Mul64(whole, 10)
+		if hi != 0 {
+			return 0, 0, ErrOverflow
+		}
+		whole = lo
+	}
+
+	hi, val := bits.Mul64(whole, unit)
+	if hi != 0 {
+		return 0, 0, ErrOverflow
+	}
+
+	// The fraction is multiplied by the unit from its last digit to its first, dividing by 10
+	// after each one. The quotient is less than unit, and rem and sticky keep track of the
+	// remainder: the last digit divided off, and whether any earlier one was not zero. Products
+	// are 128 bits, since a digit times a custom unit may not fit in 64.
+	var quo uint64
+	var rem uint64
+	var sticky bool
+	div := func(hi, lo uint64) {
+		sticky = sticky || rem != 0
+		quo, rem = bits.Div64(hi, lo, 10)
+	}
+
+	idx := digits
+	for i := len(num.text) - 1; i >= 0 && idx > num.point && idx > 0; i-- {
+		if isDigit(num.text[i]) {
+			idx--
+			if idx >= num.point {
+				hi, lo := bits.Mul64(uint64(num.text[i]-'0'), unit)
+				lo, carry := bits.Add64(lo, quo, 0)
+				div(hi+carry, lo)
+			}
+		}
+	}
+	for i := num.point; i < 0 && (quo != 0 || rem != 0); i++ {
+		div(0, quo)
+	}
+
+	val, carry := bits.Add64(val, quo, 0)
+	if carry != 0 {
+		return 0, 0, ErrOverflow
+	}
+
+	switch {
+	case rem == 0 && !sticky:
+		return val, fracZero, nil
+	case rem < 5:
+		return val, fracBelowHalf, nil
+	case rem == 5 && !sticky:
+		return val, fracHalf, nil
+	}
+	return val, fracAboveHalf, nil
+}
+
+// bytes returns the number of bytes in num units of the given size, rounding fractions of a byte
+// to the nearest whole number with halves rounded to even.
+func (num number) bytes(unit uint64) (uint64, error) {
+	val, frac, err := num.mul(unit)
+	if err != nil {
+		return 0, err
+	}
+	if frac == fracAboveHalf || (frac == fracHalf && val%2 == 1) {
+		if val++; val == 0 {
+			return 0, ErrOverflow
+		}
+	}
+	return val, nil
+}
+
+// integer returns num as a number of bytes, which must be a whole number.
+func (num number) integer() (uint64, error) {
+	val, frac, err := num.mul(1)
+	if err != nil {
+		return 0, err
+	} else if frac != fracZero {
+		return 0, ErrMissingUnits
+	}
+	return val, nil
+}
 
-	return num, nil
+func isDigit(b byte) bool {
+	return b >= '0' && b <= '9'
 }
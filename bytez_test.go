@@ -23,9 +23,53 @@ func TestAsInt(t *testing.T) {
 		{"2."},
 		{"2.5"},
 		{"2.mb"},
-		{"2.9mb"},
 		{"2\tmb"},
 		{"2  mb"},
+		{"_1"},
+		{"1_"},
+		{"1__0"},
+		{"1_kb"},
+		{"1_.5kb"},
+		{"1_048_576_"},
+		{"1._048"},
+		{"4 Megabytes"},
+		{"1e-3"},
+		{"1.5e0"},
+		{"1e20"},
+		{"1e1000 kb"},
+		{"1e3.5"},
+		{"1e_3"},
+		{"1e+kb"},
+		{"0x"},
+		{"0xg"},
+		{"0x10EiB"},
+		{"0x1_0000_0000_0000_0000"},
+		{"0x_10"},
+		{"0x10_"},
+		{"0o8"},
+		{"0b102"},
+		{"0x1.8 KiB"},
+		{"0x4000 PiB"},
+		{"0x10 EiB"},
+		{"1,5kb"},
+		{"1,50"},
+		{"1,5000"},
+		{"1000,000"},
+		{"1,000,00"},
+		{",000"},
+		{"1,000,"},
+		{"1,_000"},
+		{"1_000,000"},
+		{"1,000.5"},
+		{"4 megabytess"},
+		{"4096b"},
+		{"4096BB"},
+		{"1._5kb"},
+		{"1.5_kb"},
+		{"18446744073709551616"},
+		{"16EiB"},
+		{"17179869184 GiB"},
+		{"15.9999999999999999999EiB"},
 	}
 
 	for _, test := range negative {
@@ -47,6 +91,58 @@ func TestAsInt(t *testing.T) {
 		{"4 GiB", 4 * Gibibyte},
 		{"4.0 GiB", 4 * Gibibyte},
 		{"4.5 GiB", 4*Gibibyte + Gibibyte/2},
+		{"1_048_576", uint64(1048576)},
+		{"4_096 KiB", 4096 * Kibibyte},
+		{"2.9mb", 2900 * Kilobyte},
+		{"2.75GiB", 11 * Gibibyte / 4},
+		{"1.25 mb", 1250 * Kilobyte},
+		{"1.2_5kb", 1250},
+		{"0.0015kb", 2},
+		{"0.0025kb", 2},
+		{"0.0035kb", 4},
+		{"0.00048828125KiB", 0},
+		{"0.000488281250000000000001KiB", 1},
+		{"1.0000000000000000000000001KiB", Kibibyte},
+		{"18446744073709551615", 1<<64 - 1},
+		{"15.5EiB", 15*Exbibyte + Exbibyte/2},
+		{"4 megabytes", 4 * Megabyte},
+		{"2 gibibytes", 2 * Gibibyte},
+		{"512 bytes", 512},
+		{"1 byte", 1},
+		{"4096B", 4096},
+		{"4096 B", 4096},
+		{"1.5kilobyte", 1500},
+		{"16_384KiB", 16384 * Kibibyte},
+		{"1.000_5 kb", 1000},
+		{"1,500,000", 1500000},
+		{"12,288 KiB", 12288 * Kibibyte},
+		{"999,999.5kb", 999999500},
+		{"18,446,744,073,709,551,615", 1<<64 - 1},
+		{"1.0", 1},
+		{"1e9", 1000000000},
+		{"1.5e6", 1500000},
+		{"1E3", 1000},
+		{"1e+3", 1000},
+		{"1.5e3 KiB", 1536000},
+		{"1e3kb", Megabyte},
+		{"25e-1kb", 2500},
+		{"2e-3kb", 2},
+		{"1e19", 10000000000000000000},
+		{"1.8446744073709551615e19", 1<<64 - 1},
+		{"0e1000", 0},
+		{"1e-1000kb", 0},
+		{"0.000000000000000000000000000000000000001e39", 1},
+		{"123456789012345678901234567890e-20 kb", 1234567890123},
+		{"1e", Exabyte},
+		{"1Eb", Exbibyte},
+		{"0x100000", Mebibyte},
+		{"0x40 KiB", 64 * Kibibyte},
+		{"0x40KiB", 64 * Kibibyte},
+		{"0X1e3", 0x1e3},
+		{"0xF EiB", 15 * Exbibyte},
+		{"0xffff_ffff_ffff_ffff", 1<<64 - 1},
+		{"0o755", 0755},
+		{"0b1010 MiB", 10 * Mebibyte},
 	}
 
 	for _, test := range positive {
@@ -59,6 +155,69 @@ func TestAsInt(t *testing.T) {
 	}
 }
 
+func TestAsIntUnderscore(t *testing.T) {
+	for _, in := range []string{"_1", "1_", "1__0", "1_kb", "1_.5kb", "1._5kb", "1.5_kb"} {
+		_, err := AsInt(in)
+		require.EqualError(t, err, "misplaced underscore: underscores must be between digits", in)
+	}
+}
+
+func TestAsIntBytes(t *testing.T) {
+	for _, in := range []string{"4321", " 1_048_576 ", "4.5 GiB", "1,000kb", "4.5 GiBs", "", "1.5e-3 KiB"} {
+		val, err := AsInt(in)
+		out, errBytes := AsIntBytes([]byte(in))
+		require.Equal(t, val, out, in)
+		require.Equal(t, err, errBytes, in)
+	}
+
+	// Parsing a byte slice allocates nothing when it succeeds.
+	in := []byte(" 4.5 GiB ")
+	require.Zero(t, testing.AllocsPerRun(100, func() { AsIntBytes(in) }))
+
+	var size Size
+	require.NoError(t, size.UnmarshalText(in))
+	require.Equal(t, Size(4*Gibibyte+Gibibyte/2), size)
+	require.Zero(t, testing.AllocsPerRun(100, func() { size.UnmarshalText(in) }))
+}
+
+func TestLookupUnit(t *testing.T) {
+	// Both the default and the minimal builds must accept exactly these units.
+	var tests = []struct {
+		units []string
+		value uint64
+	}{
+		{[]string{"k", "kb", "kB"}, Kilobyte},
+		{[]string{"m", "mb", "mB"}, Megabyte},
+		{[]string{"e", "eb", "eB"}, Exabyte},
+		{[]string{"K", "KB", "Kb", "Ki", "KiB"}, Kibibyte},
+		{[]string{"G", "GB", "Gb", "Gi", "GiB"}, Gibibyte},
+		{[]string{"E", "EB", "Eb", "Ei", "EiB"}, Exbibyte},
+		{[]string{"B", "byte", "bytes"}, 1},
+		{[]string{"kilobyte", "kilobytes"}, Kilobyte},
+		{[]string{"kibibyte", "kibibytes"}, Kibibyte},
+		{[]string{"exabyte", "exabytes"}, Exabyte},
+		{[]string{"exbibyte", "exbibytes"}, Exbibyte},
+		{[]string{"kbit"}, Kilobyte / 8},
+		{[]string{"Mbit"}, Megabyte / 8},
+		{[]string{"Ebit"}, Exabyte / 8},
+		{[]string{"Kibit"}, Kibibyte / 8},
+		{[]string{"Eibit"}, Exbibyte / 8},
+		{[]string{"bit", "bits", "Kbit", "mbit", "kibit", "KiBit", "Mbits", "Mibits"}, 0},
+		{[]string{"", "b", "x", "ki", "kiB", "Kib", "KIB", "KiBB", "KiBs", "Bytes", "kilo",
+			"kilobytess", "megabites"}, 0},
+	}
+
+	for _, test := range tests {
+		for _, units := range test.units {
+			val, ok := lookupUnit(units)
+			require.Equal(t, test.value != 0, ok, units)
+			if ok {
+				require.Equal(t, test.value, val, units)
+			}
+		}
+	}
+}
+
 func TestAsStr(t *testing.T) {
 	var tests = []struct {
 		in  uint64
@@ -82,6 +241,10 @@ func TestAsStr(t *testing.T) {
 		{5368709120, "5GiB"},
 		{5905580032, "5.5GiB"},
 		//
+		{1250000, "1250kb"},
+		{1049600, "1025KiB"},
+		{1049088, "1024.5KiB"},
+		{1<<64 - 512, "18014398509481983.5KiB"},
 		{314159265359, "314159265359"},
 	}
 
@@ -91,9 +254,26 @@ func TestAsStr(t *testing.T) {
 			fmt.Printf("%v --> %v\n", test.in, out)
 		}
 		require.Equal(t, test.out, out)
+
+		var stringer fmt.Stringer = Size(test.in)
+		require.Equal(t, test.out, stringer.String())
+
+		require.Equal(t, "x:"+test.out, string(AppendStr([]byte("x:"), test.in)))
+		text, err := Size(test.in).AppendText([]byte("x:"))
+		require.NoError(t, err)
+		require.Equal(t, "x:"+test.out, string(text))
 	}
 }
 
+func TestAppendStrAllocs(t *testing.T) {
+	buf := make([]byte, 0, 32)
+	allocs := testing.AllocsPerRun(100, func() {
+		buf = AppendStr(buf[:0], 3670016)
+		buf, _ = Size(5905580032).AppendText(buf[:0])
+	})
+	require.Zero(t, allocs)
+}
+
 func TestMarshal(t *testing.T) {
 	type conf struct {
 		CacheSize Size `json:"cache_size"`
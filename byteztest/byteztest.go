@@ -0,0 +1,225 @@
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+// Package byteztest provides a curated corpus of byte size strings and a differential harness
+// for testing parsers of sizes, whether package bytez itself, a wrapper around it, or another
+// library. It can be used to seed fuzz tests, to check that a parser agrees with bytez, and to
+// report where different interpretations of the same strings drift apart.
+//
+// Interpretations of third-party libraries, like go-humanize and bytefmt, are provided by the
+// separate module github.com/nexvium/bytez/byteztest/differential, so that using this package
+// does not pull in their dependencies.
+package byteztest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/nexvium/bytez"
+)
+
+// A Case is a valid size string and the number of bytes bytez parses it as.
+type Case struct {
+	Input string
+	Bytes uint64
+}
+
+// Valid lists size strings accepted by bytez.AsInt, covering bare numbers, every unit in every
+// accepted spelling, fractions, digit separators, and surrounding whitespace.
+var Valid = []Case{
+	{"0", 0},
+	{"1", 1},
+	{"999", 999},
+	{"4096", 4096},
+	{"18446744073709551615", 1<<64 - 1},
+	{"1_048_576", 1 << 20},
+	{"  64  ", 64},
+	{"\t2kb\n", 2000},
+
+	{"1k", 1000},
+	{"1kb", 1000},
+	{"1kB", 1000},
+	{"1K", 1 << 10},
+	{"1KB", 1 << 10},
+	{"1Kb", 1 << 10},
+	{"1KiB", 1 << 10},
+	{"1 KiB", 1 << 10},
+	{"4_096 KiB", 4 << 20},
+
+	{"1m", bytez.Megabyte},
+	{"1mb", bytez.Megabyte},
+	{"1MB", bytez.Mebibyte},
+	{"1MiB", bytez.Mebibyte},
+	{"1g", bytez.Gigabyte},
+	{"1gb", bytez.Gigabyte},
+	{"1GB", bytez.Gibibyte},
+	{"1GiB", bytez.Gibibyte},
+	{"1t", bytez.Terabyte},
+	{"1tb", bytez.Terabyte},
+	{"1TB", bytez.Tebibyte},
+	{"1TiB", bytez.Tebibyte},
+	{"1p", bytez.Petabyte},
+	{"1pb", bytez.Petabyte},
+	{"1PB", bytez.Pebibyte},
+	{"1PiB", bytez.Pebibyte},
+	{"1e", bytez.Exabyte},
+	{"1eb", bytez.Exabyte},
+	{"1EB", bytez.Exbibyte},
+	{"1EiB", bytez.Exbibyte},
+	{"15EiB", 15 * bytez.Exbibyte},
+
+	{"1.5kb", 1500},
+	{"1.5KiB", 1536},
+	{"2.5 GiB", 5 << 29},
+	{"1.0mb", bytez.Megabyte},
+	{"0.5MiB", 1 << 19},
+	{"1.25kb", 1250},
+	{"2.75GiB", 11 << 28},
+	{"4 megabytes", 4 * bytez.Megabyte},
+	{"512 bytes", 512},
+	{"1,000", 1000},
+	{"12,288 KiB", 12288 << 10},
+	{"1e3", 1000},
+	{"1.5e6", 1500000},
+	{"0x10", 16},
+	{"0x40 KiB", 64 << 10},
+}
+
+// Invalid lists strings rejected by bytez.AsInt, including common mistakes and near misses of
+// valid sizes.
+var Invalid = []string{
+	"",
+	" ",
+	"-1",
+	"+1",
+	"kb",
+	"KiB",
+	"1.",
+	".5kb",
+	"1.5",
+	"1._5kb",
+	"16EiB",
+	"18446744073709551616",
+	"1,00",
+	"1_",
+	"_1",
+	"1__0",
+	"1_kb",
+	"1  kb",
+	"1\tkb",
+	"1-kb",
+	"1kib",
+	"1KIB",
+	"1kbs",
+	"1 Bytes",
+	"1x",
+	"1 MiB/s",
+	"0x10EiB",
+	"1e-3",
+	"one kb",
+}
+
+// Inputs returns the inputs of Valid followed by Invalid.
+func Inputs() []string {
+	inputs := make([]string, 0, len(Valid)+len(Invalid))
+	for _, c := range Valid {
+		inputs = append(inputs, c.Input)
+	}
+	return append(inputs, Invalid...)
+}
+
+// An Interpretation is a named function that parses size strings, such as bytez.AsInt or the
+// parser of another library.
+type Interpretation struct {
+	Name  string
+	Parse func(str string) (uint64, error)
+}
+
+// Bytez is the interpretation of package bytez.
+var Bytez = Interpretation{Name: "bytez", Parse: bytez.AsInt}
+
+// A Result is the outcome of parsing a string with an Interpretation.
+type Result struct {
+	Bytes uint64
+	Err   error
+}
+
+func (r Result) String() string {
+	if r.Err != nil {
+		return "error: " + r.Err.Error()
+	}
+	return fmt.Sprint(r.Bytes)
+}
+
+// A Difference is an input on which interpretations disagree, either because some accept it and
+// others do not or because they accept it as different numbers of bytes.
+type Difference struct {
+	Input string
+
+	// Results holds the result of each interpretation, in the order they were given to Compare.
+	Results []Result
+
+	names []string
+}
+
+func (d Difference) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%q:", d.Input)
+	for i, res := range d.Results {
+		fmt.Fprintf(&b, " %s=%s", d.names[i], res)
+	}
+	return b.String()
+}
+
+// Compare parses each input with every interpretation and returns the inputs on which they
+// disagree. Error messages are not compared, only whether there was an error.
+func Compare(inputs []string, interps ...Interpretation) []Difference {
+	var diffs []Difference
+	names := make([]string, len(interps))
+	for i, interp := range interps {
+		names[i] = interp.Name
+	}
+
+	for _, input := range inputs {
+		results := make([]Result, len(interps))
+		same := true
+		for i, interp := range interps {
+			results[i].Bytes, results[i].Err = interp.Parse(input)
+			if results[i].Err != nil {
+				results[i].Bytes = 0
+			}
+			if i > 0 && ((results[i].Err == nil) != (results[0].Err == nil) ||
+				results[i].Bytes != results[0].Bytes) {
+				same = false
+			}
+		}
+		if !same {
+			diffs = append(diffs, Difference{Input: input, Results: results, names: names})
+		}
+	}
+	return diffs
+}
+
+// Check reports a test failure for each string in the corpus that parse interprets differently
+// than bytez: each of Valid must be parsed as the same number of bytes, and each of Invalid must
+// be rejected. It lets wrappers and alternative implementations verify that they keep the
+// semantics of bytez.
+func Check(t testing.TB, parse func(str string) (uint64, error)) {
+	t.Helper()
+	for _, c := range Valid {
+		if n, err := parse(c.Input); err != nil {
+			t.Errorf("parsing %q: unexpected error: %v", c.Input, err)
+		} else if n != c.Bytes {
+			t.Errorf("parsing %q: got %d bytes, want %d", c.Input, n, c.Bytes)
+		}
+	}
+	for _, input := range Invalid {
+		if n, err := parse(input); err == nil {
+			t.Errorf("parsing %q: got %d bytes, want error", input, n)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package byteztest
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/nexvium/bytez"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCorpus(t *testing.T) {
+	Check(t, bytez.AsInt)
+	require.Len(t, Inputs(), len(Valid)+len(Invalid))
+}
+
+func TestCheck(t *testing.T) {
+	// A parser that only understands plain numbers fails every case with units.
+	var mock mockTB
+	Check(&mock, func(str string) (uint64, error) {
+		return strconv.ParseUint(str, 10, 64)
+	})
+	require.NotEmpty(t, mock.errors)
+	require.Contains(t, mock.errors, `parsing "1KiB": unexpected error: strconv.ParseUint: parsing "1KiB": invalid syntax`)
+}
+
+func TestCompare(t *testing.T) {
+	plain := Interpretation{Name: "plain", Parse: func(str string) (uint64, error) {
+		return strconv.ParseUint(str, 10, 64)
+	}}
+	decimal := Interpretation{Name: "decimal", Parse: func(str string) (uint64, error) {
+		if str == "1KB" {
+			return 1000, nil
+		}
+		return bytez.AsInt(str)
+	}}
+
+	require.Empty(t, Compare(Inputs(), Bytez, Bytez))
+
+	diffs := Compare([]string{"1", "1KB", "1x"}, Bytez, decimal)
+	if testing.Verbose() {
+		fmt.Println(diffs)
+	}
+	require.Len(t, diffs, 1)
+	require.Equal(t, "1KB", diffs[0].Input)
+	require.Equal(t, []Result{{Bytes: 1024}, {Bytes: 1000}}, diffs[0].Results)
+	require.Equal(t, `"1KB": bytez=1024 decimal=1000`, diffs[0].String())
+
+	diffs = Compare([]string{"1", "1KB", "-1"}, Bytez, plain)
+	require.Len(t, diffs, 1)
+	require.Equal(t, "1KB", diffs[0].Input)
+	require.Error(t, diffs[0].Results[1].Err)
+	require.Equal(t, "error: nope", Result{Err: errors.New("nope")}.String())
+}
+
+type mockTB struct {
+	testing.TB
+	errors []string
+}
+
+func (m *mockTB) Helper() {}
+
+func (m *mockTB) Errorf(format string, args ...interface{}) {
+	m.errors = append(m.errors, fmt.Sprintf(format, args...))
+}
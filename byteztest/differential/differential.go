@@ -0,0 +1,35 @@
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+// Package differential provides byteztest interpretations of the size parsers of other popular
+// libraries, so that their differences from bytez, and from each other, can be reported with
+// byteztest.Compare:
+//
+//	for _, diff := range byteztest.Compare(byteztest.Inputs(), byteztest.Bytez, differential.GoHumanize) {
+//		fmt.Println(diff)
+//	}
+//
+// This package is a separate module so that package byteztest itself does not depend on the
+// libraries.
+package differential
+
+import (
+	"code.cloudfoundry.org/bytefmt"
+	"github.com/dustin/go-humanize"
+	"github.com/nexvium/bytez/byteztest"
+)
+
+// GoHumanize is the interpretation of humanize.ParseBytes from github.com/dustin/go-humanize,
+// which treats "KB" as 1000 bytes and "KiB" as 1024 regardless of letter case, and accepts
+// fractions and commas.
+var GoHumanize = byteztest.Interpretation{Name: "go-humanize", Parse: humanize.ParseBytes}
+
+// Bytefmt is the interpretation of bytefmt.ToBytes from code.cloudfoundry.org/bytefmt, which
+// treats every unit, like "KB" and "kb", as a power of 1024 and requires units.
+var Bytefmt = byteztest.Interpretation{Name: "bytefmt", Parse: bytefmt.ToBytes}
+
+// All lists bytez and every interpretation in this package.
+var All = []byteztest.Interpretation{byteztest.Bytez, GoHumanize, Bytefmt}
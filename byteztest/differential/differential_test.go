@@ -0,0 +1,35 @@
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package differential
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/nexvium/bytez/byteztest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDifferential(t *testing.T) {
+	diffs := byteztest.Compare(byteztest.Inputs(), All...)
+	byInput := map[string]byteztest.Difference{}
+	for _, diff := range diffs {
+		if testing.Verbose() {
+			fmt.Println(diff)
+		}
+		byInput[diff.Input] = diff
+	}
+
+	// The libraries disagree on the meaning of the most common units.
+	require.Equal(t, []byteztest.Result{{Bytes: 1024}, {Bytes: 1000}, {Bytes: 1024}},
+		byInput["1KB"].Results)
+	require.Equal(t, []byteztest.Result{{Bytes: 1000}, {Bytes: 1000}, {Bytes: 1024}},
+		byInput["1kb"].Results)
+
+	// All agree on unambiguous binary units.
+	require.NotContains(t, byInput, "1MiB")
+}
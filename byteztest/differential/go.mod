@@ -0,0 +1,18 @@
+module github.com/nexvium/bytez/byteztest/differential
+
+go 1.25.0
+
+replace github.com/nexvium/bytez => ../../
+
+require (
+	code.cloudfoundry.org/bytefmt v0.88.0
+	github.com/dustin/go-humanize v1.1.0
+	github.com/nexvium/bytez v0.0.0-00010101000000-000000000000
+	github.com/stretchr/testify v1.11.1
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
@@ -0,0 +1,17 @@
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package byteztest
+
+import "testing"
+
+// AddSeeds adds every string in the corpus to the seed corpus of the fuzz test f, whose fuzz
+// target must take a single string argument.
+func AddSeeds(f *testing.F) {
+	for _, input := range Inputs() {
+		f.Add(input)
+	}
+}
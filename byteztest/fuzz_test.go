@@ -0,0 +1,27 @@
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package byteztest
+
+import (
+	"testing"
+
+	"github.com/nexvium/bytez"
+)
+
+func FuzzAsInt(f *testing.F) {
+	AddSeeds(f)
+	f.Fuzz(func(t *testing.T, str string) {
+		n, err := bytez.AsInt(str)
+		if err != nil {
+			return
+		}
+		// Anything accepted must be accepted again with surrounding whitespace.
+		if m, err := bytez.AsInt(" " + str + "\n"); err != nil || m != n {
+			t.Errorf("AsInt(%q) = %d but with whitespace = %d, %v", str, n, m, err)
+		}
+	})
+}
@@ -0,0 +1,86 @@
+//go:build !bytez_minimal
+
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package bytez
+
+import (
+	"container/list"
+	"sync"
+)
+
+// A CachedParser is a Parser that remembers the results of parsing the most recently used strings,
+// for programs like metrics pipelines and configuration reloaders that parse the same handful of
+// values over and over. Errors are remembered as well as sizes.
+//
+// CachedParsers are safe for concurrent use by multiple goroutines.
+type CachedParser struct {
+	parser *Parser
+	size   int
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     list.List
+}
+
+type cacheEntry struct {
+	str  string
+	size Size
+	err  error
+}
+
+// NewCachedParser returns a CachedParser that parses sizes with p, or like AsInt if p is nil, and
+// remembers the results for at most n strings, discarding the least recently used one when full.
+// If n is less than 1, nothing is cached.
+func NewCachedParser(p *Parser, n int) *CachedParser {
+	if p == nil {
+		p = &Parser{}
+	}
+	if n < 0 {
+		n = 0
+	}
+	return &CachedParser{parser: p, size: n, entries: make(map[string]*list.Element)}
+}
+
+// Parse returns the size specified by str, like Parser.Parse, using the cached result if there
+// is one.
+func (c *CachedParser) Parse(str string) (Size, error) {
+	if c.size == 0 {
+		return c.parser.Parse(str)
+	}
+
+	c.mu.Lock()
+	if elem, ok := c.entries[str]; ok {
+		c.lru.MoveToFront(elem)
+		entry := elem.Value.(*cacheEntry)
+		c.mu.Unlock()
+		return entry.size, entry.err
+	}
+	c.mu.Unlock()
+
+	// Parse without holding the lock; at worst two goroutines parse the same string.
+	size, err := c.parser.Parse(str)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.entries[str]; !ok {
+		if c.lru.Len() >= c.size {
+			oldest := c.lru.Back()
+			c.lru.Remove(oldest)
+			delete(c.entries, oldest.Value.(*cacheEntry).str)
+		}
+		c.entries[str] = c.lru.PushFront(&cacheEntry{str: str, size: size, err: err})
+	}
+	return size, err
+}
+
+// Len returns the number of strings currently cached.
+func (c *CachedParser) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
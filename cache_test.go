@@ -0,0 +1,89 @@
+//go:build !bytez_minimal
+
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package bytez
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCachedParser(t *testing.T) {
+	c := NewCachedParser(nil, 2)
+
+	sz, err := c.Parse("4MiB")
+	require.NoError(t, err)
+	require.Equal(t, Size(4*Mebibyte), sz)
+	require.Equal(t, 1, c.Len())
+
+	// Errors are cached too.
+	_, err = c.Parse("4 MiBs")
+	require.Error(t, err)
+	_, err = c.Parse("4 MiBs")
+	require.Error(t, err)
+	require.Equal(t, 2, c.Len())
+
+	// "4MiB" is used again, so "4 MiBs" is the one discarded.
+	sz, err = c.Parse("4MiB")
+	require.NoError(t, err)
+	require.Equal(t, Size(4*Mebibyte), sz)
+	_, err = c.Parse("1kb")
+	require.NoError(t, err)
+	require.Equal(t, 2, c.Len())
+	require.Contains(t, c.entries, "4MiB")
+	require.Contains(t, c.entries, "1kb")
+	require.NotContains(t, c.entries, "4 MiBs")
+
+	c = NewCachedParser(nil, 0)
+	sz, err = c.Parse("1.5 GiB")
+	require.NoError(t, err)
+	require.Equal(t, Size(Gibibyte+Gibibyte/2), sz)
+	require.Zero(t, c.Len())
+
+	// The results are those of the given parser.
+	p, err := NewParser(WithStrictUnits())
+	require.NoError(t, err)
+	c = NewCachedParser(p, 2)
+	sz, err = c.Parse("4MB")
+	require.NoError(t, err)
+	require.Equal(t, Size(4*Megabyte), sz)
+	_, err = c.Parse("4KB")
+	require.Error(t, err)
+	require.Equal(t, 2, c.Len())
+}
+
+func TestCachedParserConcurrent(t *testing.T) {
+	c := NewCachedParser(nil, 8)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				n := j % 16
+				sz, err := c.Parse(strconv.Itoa(n) + "KiB")
+				require.NoError(t, err)
+				require.Equal(t, Size(uint64(n)*Kibibyte), sz)
+			}
+		}()
+	}
+	wg.Wait()
+	require.Equal(t, 8, c.Len())
+}
+
+func BenchmarkCachedParser(b *testing.B) {
+	c := NewCachedParser(nil, 16)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		c.Parse("4.5 GiB")
+	}
+}
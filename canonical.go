@@ -0,0 +1,30 @@
+//go:build !bytez_minimal
+
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package bytez
+
+// Canonicalize returns the size specified by str, in the syntax accepted by AsInt, in the
+// canonical notation of this package, like "2MiB" for "2048 Kb" or "1.5gb" for "1500 megabytes",
+// so that configuration values entered in different ways are stored the same way. It formats the
+// size like AsCanonicalStr.
+func Canonicalize(str string) (string, error) {
+	val, err := AsInt(str)
+	if err != nil {
+		return "", err
+	}
+	return AsCanonicalStr(val), nil
+}
+
+// AsCanonicalStr returns size formatted like AsStr does by default, like "4MiB" or "1250kb".
+// Unlike AsStr, it ignores SetDefaultFormat and BYTEZ_FORMAT, so that the result does not depend
+// on the program or its environment, as required by generated code and stored text, and it can
+// be parsed again by AsInt to the same size.
+func AsCanonicalStr(size uint64) string {
+	var buf [24]byte
+	return string(appendStr(buf[:0], size))
+}
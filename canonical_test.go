@@ -0,0 +1,62 @@
+//go:build !bytez_minimal
+
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package bytez
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanonicalize(t *testing.T) {
+	var tests = []struct {
+		in  string
+		out string
+		err bool
+	}{
+		{"2048 Kb", "2MiB", false},
+		{"1500 megabytes", "1.5gb", false},
+		{" 1,024 KiB ", "1MiB", false},
+		{"4096", "4KiB", false},
+		{"1000", "1kb", false},
+		{"1234", "1234", false},
+		{"0 GiB", "0", false},
+		{"1.5 GiB", "1.5GiB", false},
+		{"256MiBs", "", true},
+		{"", "", true},
+	}
+
+	// The canonical notation does not depend on the format of AsStr.
+	prev := DefaultFormat()
+	t.Cleanup(func() { SetDefaultFormat(prev) })
+	t.Setenv("BYTEZ_FORMAT", "si,space")
+	require.NoError(t, ConfigureFromEnv())
+
+	for _, test := range tests {
+		out, err := Canonicalize(test.in)
+		if testing.Verbose() {
+			fmt.Printf("%q --> %q, %v\n", test.in, out, err)
+		}
+		if test.err {
+			require.Error(t, err, test.in)
+		} else {
+			require.NoError(t, err, test.in)
+			require.Equal(t, test.out, out, test.in)
+
+			again, err := Canonicalize(out)
+			require.NoError(t, err, out)
+			require.Equal(t, out, again, out)
+
+			val, err := AsInt(test.in)
+			require.NoError(t, err, test.in)
+			require.Equal(t, out, AsCanonicalStr(val), test.in)
+		}
+	}
+}
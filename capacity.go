@@ -0,0 +1,77 @@
+//go:build !bytez_minimal
+
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package bytez
+
+import (
+	"errors"
+	"math/big"
+)
+
+// CacheSizing describes the memory used by the entries of a cache, hash table, or similar
+// structure, and answers the common sizing questions of how many entries fit in a memory budget
+// and what budget a number of entries need.
+type CacheSizing struct {
+	// Entry is the average size of an entry, including its key and value.
+	Entry Size
+
+	// Overhead is the additional memory used by each entry for bookkeeping, like pointers,
+	// list links, and hash table slots.
+	Overhead Size
+
+	// LoadFactor is the maximum fraction of the capacity that is used, as with hash tables
+	// that grow before becoming full. It must be in the range (0, 1]. Zero means 1.
+	LoadFactor float64
+}
+
+// perEntry returns the memory needed per entry, including the load factor, as an exact value.
+func (c CacheSizing) perEntry() (*big.Rat, error) {
+	lf := c.LoadFactor
+	if lf == 0 {
+		lf = 1
+	} else if !(lf > 0 && lf <= 1) {
+		return nil, errors.New("load factor must be greater than 0 and at most 1")
+	}
+
+	per := new(big.Rat).SetUint64(uint64(c.Entry))
+	per.Add(per, new(big.Rat).SetUint64(uint64(c.Overhead)))
+	if per.Sign() == 0 {
+		return nil, errors.New("entry size and overhead are both zero")
+	}
+	return per.Quo(per, new(big.Rat).SetFloat64(lf)), nil
+}
+
+// EntriesFor returns the number of entries that fit in budget, rounded down.
+func (c CacheSizing) EntriesFor(budget Size) (uint64, error) {
+	per, err := c.perEntry()
+	if err != nil {
+		return 0, err
+	}
+
+	n := new(big.Rat).Quo(new(big.Rat).SetUint64(uint64(budget)), per)
+	return new(big.Int).Quo(n.Num(), n.Denom()).Uint64(), nil
+}
+
+// BudgetFor returns the memory needed for the given number of entries, rounded up. An error is
+// returned if the budget is too large for a Size.
+func (c CacheSizing) BudgetFor(entries uint64) (Size, error) {
+	per, err := c.perEntry()
+	if err != nil {
+		return 0, err
+	}
+
+	total := new(big.Rat).Mul(new(big.Rat).SetUint64(entries), per)
+	budget, rem := new(big.Int).QuoRem(total.Num(), total.Denom(), new(big.Int))
+	if rem.Sign() != 0 {
+		budget.Add(budget, big.NewInt(1))
+	}
+	if !budget.IsUint64() {
+		return 0, errors.New("budget is too large")
+	}
+	return Size(budget.Uint64()), nil
+}
@@ -0,0 +1,52 @@
+//go:build !bytez_minimal
+
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package bytez
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheSizing(t *testing.T) {
+	var tests = []struct {
+		sizing  CacheSizing
+		budget  Size
+		entries uint64
+		need    Size
+	}{
+		{CacheSizing{Entry: Size(4 * Kibibyte)}, Size(64 * Mebibyte), 16384, Size(64 * Mebibyte)},
+		{CacheSizing{Entry: 960, Overhead: 64}, Size(1 * Mebibyte), 1024, Size(1 * Mebibyte)},
+		{CacheSizing{Entry: 100, LoadFactor: 0.75}, Size(1 * Kilobyte), 7, 934},
+		{CacheSizing{Entry: 48, Overhead: 16, LoadFactor: 0.5}, Size(1 * Gibibyte), 8388608, Size(1 * Gibibyte)},
+	}
+
+	for _, test := range tests {
+		entries, err := test.sizing.EntriesFor(test.budget)
+		require.NoError(t, err)
+		need, err := test.sizing.BudgetFor(entries)
+		require.NoError(t, err)
+		if testing.Verbose() {
+			fmt.Printf("%+v: %v --> %v entries --> %v\n", test.sizing, test.budget, entries, need)
+		}
+		require.Equal(t, test.entries, entries)
+		require.Equal(t, test.need, need)
+		require.True(t, need <= test.budget)
+	}
+
+	_, err := CacheSizing{}.EntriesFor(Size(Gibibyte))
+	require.Error(t, err)
+	_, err = CacheSizing{Entry: 1, LoadFactor: 1.5}.EntriesFor(Size(Gibibyte))
+	require.Error(t, err)
+	_, err = CacheSizing{Entry: 1, LoadFactor: -1}.BudgetFor(1)
+	require.Error(t, err)
+	_, err = CacheSizing{Entry: Size(Gibibyte)}.BudgetFor(1 << 40)
+	require.Error(t, err)
+}
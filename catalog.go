@@ -0,0 +1,105 @@
+//go:build !bytez_minimal
+
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package bytez
+
+import (
+	"errors"
+	"strings"
+)
+
+// A Catalog translates the units of a language to those of this package, for sizes in
+// configuration files written in that language. WithCatalog makes a Parser accept them.
+type Catalog struct {
+	name  string
+	units map[string]string
+}
+
+// String returns the name of the catalog, like "french".
+func (c *Catalog) String() string {
+	return c.name
+}
+
+// French is the catalog of French units, in octets: "o" for bytes, abbreviations like "Mo" and
+// "Mio", and words like "mégaoctets" and "mebioctets", with or without accents. As with the units
+// of this package, the case of the first letter of ambiguous abbreviations selects their base, so
+// "ko" is 1000 bytes and "Ko" 1024.
+var French = &Catalog{name: "french", units: func() map[string]string {
+	units := map[string]string{"o": "B", "octet": "byte", "octets": "bytes"}
+	for i := 0; i < len(bitPrefixes); i++ {
+		lower, upper := strings.ToLower(bitPrefixes[i:i+1]), strings.ToUpper(bitPrefixes[i:i+1])
+		units[lower+"o"], units[upper+"o"] = lower+"b", upper+"B"
+		units[upper+"io"], units[lower+"io"] = upper+"iB", upper+"iB"
+	}
+
+	accented := strings.NewReplacer("mega", "méga", "tera", "téra", "peta", "péta",
+		"mebi", "mébi", "tebi", "tébi", "pebi", "pébi")
+	for i := 1; i < len(wordsBase10); i++ {
+		for _, word := range []string{wordsBase10[i], wordsBase2[i]} {
+			octet := strings.TrimSuffix(word, "byte") + "octet"
+			units[octet], units[octet+"s"] = word, word+"s"
+			units[accented.Replace(octet)], units[accented.Replace(octet)+"s"] = word, word+"s"
+		}
+	}
+	return units
+}()}
+
+// German is the catalog of the German words for units, which are capitalized nouns, like
+// "Megabyte" and "Gibibytes". German abbreviations are the same as those of this package.
+var German = &Catalog{name: "german", units: func() map[string]string {
+	units := make(map[string]string)
+	for i := range wordsBase10 {
+		for _, word := range []string{wordsBase10[i], wordsBase2[i]} {
+			noun := strings.ToUpper(word[:1]) + word[1:]
+			units[noun], units[noun+"s"] = word, word+"s"
+		}
+	}
+	return units
+}()}
+
+// WithCatalog accepts the units of the given catalog in addition to those of this package. They
+// have the meaning of the units they translate to, so options like WithBase and WithStrictUnits
+// apply to them as to those units. It cannot be used with profiles that have their own syntax,
+// like Systemd and Nginx.
+func WithCatalog(c *Catalog) Option {
+	return func(p *Parser) error {
+		if c == nil {
+			return errors.New("nil catalog")
+		}
+		if p.catalog == nil {
+			p.catalog = make(map[string]string, len(c.units))
+		}
+		for local, units := range c.units {
+			p.catalog[local] = units
+		}
+		return nil
+	}
+}
+
+// translate returns the units of this package that the given units of the catalogs of the parser
+// translate to, regardless of case with WithIgnoreCase, whose catalog has lowercase keys.
+func (p *Parser) translate(units string) (string, bool) {
+	if !p.fold {
+		translated, ok := p.catalog[units]
+		return translated, ok
+	}
+
+	var buf [16]byte
+	if len(units) > len(buf) {
+		return "", false
+	}
+	for i := 0; i < len(units); i++ {
+		b := units[i]
+		if b >= 'A' && b <= 'Z' {
+			b += 'a' - 'A'
+		}
+		buf[i] = b
+	}
+	translated, ok := p.catalog[string(buf[:len(units)])]
+	return translated, ok
+}
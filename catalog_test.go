@@ -0,0 +1,74 @@
+//go:build !bytez_minimal
+
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package bytez
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithCatalog(t *testing.T) {
+	var tests = []struct {
+		opts []Option
+		in   string
+		out  uint64
+		err  bool
+	}{
+		{[]Option{WithCatalog(French)}, "512 o", 512, false},
+		{[]Option{WithCatalog(French)}, "4 Ko", 4 * Kibibyte, false},
+		{[]Option{WithCatalog(French)}, "4 ko", 4 * Kilobyte, false},
+		{[]Option{WithCatalog(French)}, "1,5 Go", 0, true},
+		{[]Option{WithCatalog(French), WithDecimalComma()}, "1,5 Go", 3 * Gibibyte / 2, false},
+		{[]Option{WithCatalog(French)}, "2 Mio", 2 * Mebibyte, false},
+		{[]Option{WithCatalog(French)}, "2 mio", 2 * Mebibyte, false},
+		{[]Option{WithCatalog(French)}, "3 mégaoctets", 3 * Megabyte, false},
+		{[]Option{WithCatalog(French)}, "3 megaoctets", 3 * Megabyte, false},
+		{[]Option{WithCatalog(French)}, "1 gibioctet", Gibibyte, false},
+		{[]Option{WithCatalog(French)}, "2 Téraoctets", 0, true},
+		{[]Option{WithCatalog(French)}, "4 MiB", 4 * Mebibyte, false},
+		{[]Option{WithCatalog(German)}, "4 Megabyte", 4 * Megabyte, false},
+		{[]Option{WithCatalog(German)}, "2 Gibibytes", 2 * Gibibyte, false},
+		{[]Option{WithCatalog(German)}, "100 Byte", 100, false},
+		{[]Option{WithCatalog(German)}, "4 Mo", 0, true},
+		{[]Option{WithCatalog(French), WithCatalog(German)}, "1 Kilobyte", Kilobyte, false},
+		{[]Option{WithCatalog(French), WithBase(Base10)}, "4 Ko", 4 * Kilobyte, false},
+		{[]Option{WithCatalog(French), WithStrictUnits()}, "4 Mo", 4 * Megabyte, false},
+		{[]Option{WithCatalog(French), WithStrictUnits()}, "4 Ko", 0, true},
+		{[]Option{WithCatalog(French), WithIgnoreCase(Base10)}, "4 MO", 4 * Megabyte, false},
+		{[]Option{WithCatalog(French), WithUnits("Mo", "GiB")}, "4 Mo", 4 * Mebibyte, false},
+		{[]Option{WithCatalog(French), WithUnits("Mo", "GiB")}, "4 MB", 0, true},
+		{[]Option{WithCatalog(French), WithProfile(Docker)}, "4 Go", 4 * Gibibyte, false},
+		{[]Option{WithCatalog(French), WithProfile(Systemd)}, "4 Go", 0, true},
+		{[]Option{WithCatalog(nil)}, "4", 0, true},
+	}
+
+	for _, test := range tests {
+		out, err := ParseSize(test.in, test.opts...)
+		if testing.Verbose() {
+			fmt.Printf("%q --> %v, %v\n", test.in, out, err)
+		}
+		if test.err {
+			require.Error(t, err, test.in)
+		} else {
+			require.NoError(t, err, test.in)
+			require.Equal(t, test.out, uint64(out), test.in)
+		}
+	}
+
+	require.Equal(t, "french", French.String())
+
+	p, err := NewParser(WithCatalog(French))
+	require.NoError(t, err)
+	res, err := p.ParseDetailed("4 Mo")
+	require.NoError(t, err)
+	require.Equal(t, ParseResult{Size: Size(4 * Mebibyte), Units: "Mo", Unit: Mebibyte, Base: Base2,
+		Ambiguous: true}, res)
+}
@@ -0,0 +1,59 @@
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/nexvium/bytez/humanize"
+)
+
+const benchUsage = `Usage: bytez bench [-benchstat] [file]
+
+Bench reads the output of "go test -bench" from file or standard input and writes it with the
+B/op and MB/s measurements in human form, for example:
+
+	go test -bench . -benchmem | bytez bench
+
+Flags:
+`
+
+func runBench(args []string) error {
+	flags := flag.NewFlagSet("bench", flag.ContinueOnError)
+	benchstat := flags.Bool("benchstat", false, "keep result lines parseable by benchstat")
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), benchUsage)
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return nil
+		}
+		return err
+	}
+	if flags.NArg() > 1 {
+		flags.Usage()
+		return errors.New("at most one file may be given")
+	}
+
+	var in io.Reader = os.Stdin
+	if flags.NArg() == 1 {
+		file, err := os.Open(flags.Arg(0))
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		in = file
+	}
+
+	return humanize.Bench(os.Stdout, in, &humanize.BenchOptions{Benchstat: *benchstat})
+}
@@ -0,0 +1,171 @@
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/format"
+	"go/token"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/nexvium/bytez"
+)
+
+// A constSpec is a single entry of a gen spec file.
+type constSpec struct {
+	name string
+	size uint64
+	doc  []string
+}
+
+const genUsage = `Usage: bytez gen [-pkg name] [-o file] spec
+
+Gen reads a spec file of named byte sizes and writes a Go source file declaring each one as a
+typed bytez.Size constant. It is intended to be run from a go:generate directive:
+
+	//go:generate go run github.com/nexvium/bytez/cmd/bytez gen -o sizes_gen.go sizes.spec
+
+Each non-blank line of the spec has the form
+
+	Name = 64MiB
+
+where the value is any size accepted by bytez.AsInt. Lines starting with '#' are comments; a block
+of comment lines immediately preceding an entry becomes the doc comment of the constant.
+
+Flags:
+`
+
+func runGen(args []string) error {
+	flags := flag.NewFlagSet("gen", flag.ContinueOnError)
+	pkg := flags.String("pkg", os.Getenv("GOPACKAGE"), "package `name` of the generated file (default $GOPACKAGE)")
+	out := flags.String("o", "", "write output to `file` instead of stdout")
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), genUsage)
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return nil
+		}
+		return err
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return errors.New("exactly one spec file is required")
+	}
+	if *pkg == "" {
+		return errors.New("package name not specified and $GOPACKAGE not set")
+	}
+
+	file, err := os.Open(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	specs, err := parseSpec(file)
+	if err != nil {
+		return fmt.Errorf("%s:%v", flags.Arg(0), err)
+	}
+
+	src, err := generate(*pkg, "bytez gen "+strings.Join(args, " "), specs)
+	if err != nil {
+		return err
+	}
+
+	if *out == "" {
+		_, err = os.Stdout.Write(src)
+		return err
+	}
+	return os.WriteFile(*out, src, 0644)
+}
+
+// parseSpec reads a gen spec file. Errors are prefixed with the offending line number.
+func parseSpec(r io.Reader) ([]constSpec, error) {
+	var specs []constSpec
+	var doc []string
+	seen := map[string]bool{}
+
+	scanner := bufio.NewScanner(r)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			doc = nil
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			doc = append(doc, strings.TrimSpace(strings.TrimPrefix(line, "#")))
+			continue
+		}
+
+		eq := strings.IndexByte(line, '=')
+		if eq < 0 {
+			return nil, fmt.Errorf("%d: expected \"Name = size\"", lineNo)
+		}
+		name := strings.TrimSpace(line[:eq])
+		text := strings.TrimSpace(line[eq+1:])
+
+		if !token.IsIdentifier(name) {
+			return nil, fmt.Errorf("%d: %q is not a valid Go identifier", lineNo, name)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("%d: duplicate name %q", lineNo, name)
+		}
+		size, err := bytez.AsInt(text)
+		if err != nil {
+			return nil, fmt.Errorf("%d: %q: %v", lineNo, text, err)
+		}
+
+		seen[name] = true
+		specs = append(specs, constSpec{name: name, size: size, doc: doc})
+		doc = nil
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return specs, nil
+}
+
+// generate returns the gofmt'ed source of a file declaring the given constants. At least one
+// constant is required, or the bytez import would be unused.
+func generate(pkg, cmdline string, specs []constSpec) ([]byte, error) {
+	if len(specs) == 0 {
+		return nil, errors.New("spec declares no sizes")
+	}
+
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "// Code generated by \"%s\"; DO NOT EDIT.\n\n", cmdline)
+	fmt.Fprintf(&buf, "package %s\n\n", pkg)
+	fmt.Fprintf(&buf, "import \"github.com/nexvium/bytez\"\n\n")
+
+	fmt.Fprintf(&buf, "const (\n")
+	for i, spec := range specs {
+		if i > 0 {
+			fmt.Fprintf(&buf, "\n")
+		}
+		for _, line := range spec.doc {
+			fmt.Fprintf(&buf, "\t// %s\n", line)
+		}
+		if len(spec.doc) > 0 {
+			fmt.Fprintf(&buf, "\t//\n")
+		}
+		fmt.Fprintf(&buf, "\t// %s is %s (%d bytes).\n", spec.name, bytez.AsCanonicalStr(spec.size), spec.size)
+		fmt.Fprintf(&buf, "\t%s bytez.Size = %d\n", spec.name, spec.size)
+	}
+	fmt.Fprintf(&buf, ")\n")
+
+	return format.Source(buf.Bytes())
+}
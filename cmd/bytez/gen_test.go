@@ -0,0 +1,86 @@
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/nexvium/bytez"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSpec(t *testing.T) {
+	var negative = []struct {
+		in string
+	}{
+		{"MaxUpload 64MiB"},
+		{"Max-Upload = 64MiB"},
+		{"MaxUpload = 64 MiBs"},
+		{"A = 1kb\nA = 2kb"},
+	}
+
+	for _, test := range negative {
+		_, err := parseSpec(strings.NewReader(test.in))
+		if testing.Verbose() {
+			fmt.Printf("%q ==> %v\n", test.in, err)
+		}
+		require.Error(t, err)
+	}
+
+	spec := `
+# Ignored: not followed directly by an entry.
+
+# Largest request body accepted.
+MaxUpload = 64MiB
+pageSize  = 4 KiB
+`
+	specs, err := parseSpec(strings.NewReader(spec))
+	require.NoError(t, err)
+	require.Equal(t, []constSpec{
+		{name: "MaxUpload", size: 64 * bytez.Mebibyte, doc: []string{"Largest request body accepted."}},
+		{name: "pageSize", size: 4 * bytez.Kibibyte},
+	}, specs)
+}
+
+func TestGenerate(t *testing.T) {
+	specs := []constSpec{
+		{name: "MaxUpload", size: 64 * bytez.Mebibyte, doc: []string{"Largest request body accepted."}},
+		{name: "pageSize", size: 4 * bytez.Kibibyte},
+	}
+
+	// The generated source does not depend on the default format.
+	prev := bytez.DefaultFormat()
+	t.Cleanup(func() { bytez.SetDefaultFormat(prev) })
+	bytez.SetDefaultFormat(bytez.FormatOptions{Base: bytez.Base10, Precision: 1, Space: true})
+
+	src, err := generate("limits", "bytez gen sizes.spec", specs)
+	if testing.Verbose() {
+		fmt.Printf("%s\n", src)
+	}
+	require.NoError(t, err)
+	require.Equal(t, `// Code generated by "bytez gen sizes.spec"; DO NOT EDIT.
+
+package limits
+
+import "github.com/nexvium/bytez"
+
+const (
+	// Largest request body accepted.
+	//
+	// MaxUpload is 64MiB (67108864 bytes).
+	MaxUpload bytez.Size = 67108864
+
+	// pageSize is 4KiB (4096 bytes).
+	pageSize bytez.Size = 4096
+)
+`, string(src))
+
+	_, err = generate("limits", "bytez gen empty.spec", nil)
+	require.Error(t, err)
+}
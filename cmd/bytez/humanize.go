@@ -0,0 +1,118 @@
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/nexvium/bytez/humanize"
+)
+
+const humanizeUsage = `Usage: bytez humanize [-col list] [-match regexp] [file]
+       bytez humanize -json fields | -logfmt fields [-suffix suffix] [file]
+
+Humanize reads text from file or standard input and writes it with byte counts in human form,
+for example:
+
+	kubectl top pod --no-headers | bytez humanize -col 3
+	grep allocated app.log | bytez humanize -match 'bytes=(\d+)'
+
+By default every column consisting only of digits is rewritten. With -json or -logfmt, the input
+is read as NDJSON or logfmt records and only the values of the given fields are rewritten:
+
+	tail -f access.log | bytez humanize -json bytes_sent,resp_size
+
+Flags:
+`
+
+// columnList is a flag.Value holding a comma-separated list of column numbers.
+type columnList []int
+
+func (l *columnList) String() string {
+	strs := make([]string, len(*l))
+	for i, col := range *l {
+		strs[i] = strconv.Itoa(col)
+	}
+	return strings.Join(strs, ",")
+}
+
+func (l *columnList) Set(value string) error {
+	for _, str := range strings.Split(value, ",") {
+		col, err := strconv.Atoi(strings.TrimSpace(str))
+		if err != nil || col < 1 {
+			return fmt.Errorf("invalid column %q", str)
+		}
+		*l = append(*l, col)
+	}
+	return nil
+}
+
+func runHumanize(args []string) error {
+	flags := flag.NewFlagSet("humanize", flag.ContinueOnError)
+	var columns columnList
+	flags.Var(&columns, "col", "rewrite only the comma-separated `list` of columns, numbered from 1")
+	match := flags.String("match", "", "rewrite only numbers matched by `regexp` or its first group")
+	jsonFields := flags.String("json", "", "rewrite the comma-separated `fields` of NDJSON records")
+	logfmtFields := flags.String("logfmt", "", "rewrite the comma-separated `fields` of logfmt records")
+	suffix := flags.String("suffix", "", "with -json or -logfmt, add fields named with `suffix` instead")
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), humanizeUsage)
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return nil
+		}
+		return err
+	}
+	if flags.NArg() > 1 {
+		flags.Usage()
+		return errors.New("at most one file may be given")
+	}
+
+	if *jsonFields != "" && *logfmtFields != "" {
+		return errors.New("-json and -logfmt are mutually exclusive")
+	} else if *suffix != "" && *jsonFields == "" && *logfmtFields == "" {
+		return errors.New("-suffix requires -json or -logfmt")
+	}
+
+	var in io.Reader = os.Stdin
+	if flags.NArg() == 1 {
+		file, err := os.Open(flags.Arg(0))
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		in = file
+	}
+
+	if *jsonFields != "" {
+		opts := &humanize.FieldOptions{Fields: strings.Split(*jsonFields, ","), Suffix: *suffix}
+		return humanize.NDJSON(os.Stdout, in, opts)
+	} else if *logfmtFields != "" {
+		opts := &humanize.FieldOptions{Fields: strings.Split(*logfmtFields, ","), Suffix: *suffix}
+		return humanize.Logfmt(os.Stdout, in, opts)
+	}
+
+	opts := &humanize.FilterOptions{Columns: columns}
+	if *match != "" {
+		re, err := regexp.Compile(*match)
+		if err != nil {
+			return err
+		}
+		opts.Match = re
+	}
+	return humanize.Filter(os.Stdout, in, opts)
+}
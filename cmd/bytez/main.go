@@ -0,0 +1,82 @@
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+// Command bytez provides small command-line tools built on package bytez.
+//
+// Usage:
+//
+//	bytez <command> [arguments]
+//
+// The commands are:
+//
+//	bench     humanize B/op and MB/s in "go test -bench" output
+//	gen       generate typed Size constants from a spec file
+//	humanize  humanize byte counts in text read from a file or standard input
+//	pprof     humanize byte values in pprof profiles and reports
+//	pv        copy standard input to standard output reporting throughput and ETA
+//
+// Run "bytez <command> -h" for details on a command.
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+// A command is a single bytez subcommand. The run function receives the arguments following the
+// command name and returns an error to be reported to the user.
+type command struct {
+	name  string
+	short string
+	run   func(args []string) error
+}
+
+var commands []*command
+
+func init() {
+	commands = []*command{
+		{"bench", "humanize B/op and MB/s in \"go test -bench\" output", runBench},
+		{"gen", "generate typed Size constants from a spec file", runGen},
+		{"humanize", "humanize byte counts in text read from a file or standard input", runHumanize},
+		{"pprof", "humanize byte values in pprof profiles and reports", runPprof},
+		{"pv", "copy standard input to standard output reporting throughput and ETA", runPv},
+	}
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage:\n\n\tbytez <command> [arguments]\n\nThe commands are:\n\n")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "\t%-9s %s\n", cmd.name, cmd.short)
+	}
+	fmt.Fprintf(os.Stderr, "\nRun \"bytez <command> -h\" for details on a command.\n")
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	name := os.Args[1]
+	for _, cmd := range commands {
+		if cmd.name == name {
+			if err := cmd.run(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "bytez %s: %v\n", name, err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
+	if name == "help" || name == "-h" || name == "--help" {
+		usage()
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "bytez: unknown command %q\n", name)
+	usage()
+	os.Exit(2)
+}
@@ -0,0 +1,60 @@
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/nexvium/bytez/humanize"
+)
+
+const pprofUsage = `Usage: bytez pprof [-sample_index type] [-nodecount n] [file]
+
+Pprof reads a pprof profile, or the text output of "go tool pprof", from file or standard input
+and writes it with byte values in human form. Profiles in protobuf format are summarized in a
+report similar to that of "go tool pprof -top".
+
+Flags:
+`
+
+func runPprof(args []string) error {
+	flags := flag.NewFlagSet("pprof", flag.ContinueOnError)
+	sampleIndex := flags.String("sample_index", "", "sample `type` to report, e.g. alloc_space")
+	nodeCount := flags.Int("nodecount", 0, "report at most `n` functions (0 for all)")
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), pprofUsage)
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return nil
+		}
+		return err
+	}
+	if flags.NArg() > 1 {
+		flags.Usage()
+		return errors.New("at most one file may be given")
+	}
+
+	var in io.Reader = os.Stdin
+	if flags.NArg() == 1 {
+		file, err := os.Open(flags.Arg(0))
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		in = file
+	}
+
+	opts := &humanize.PprofOptions{SampleType: *sampleIndex, NodeCount: *nodeCount}
+	return humanize.Pprof(os.Stdout, in, opts)
+}
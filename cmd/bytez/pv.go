@@ -0,0 +1,141 @@
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/nexvium/bytez"
+)
+
+const pvUsage = `Usage: bytez pv [-size size] [-interval duration] [file]
+
+Pv copies file or standard input to standard output while reporting the amount transferred,
+the current and average throughput, and, if the size of the input is known, the percentage done
+and the estimated time remaining on standard error, for example:
+
+	bytez pv -size 4GiB < disk.img | gzip > disk.img.gz
+
+The size of a file is known; the size of standard input may be given with -size.
+
+Flags:
+`
+
+var pvFormatter = bytez.NewFormatter(bytez.FormatOptions{Base: bytez.Base2, Precision: 1})
+
+func runPv(args []string) error {
+	flags := flag.NewFlagSet("pv", flag.ContinueOnError)
+	sizeStr := flags.String("size", "", "expected `size` of the input, like 4GiB")
+	interval := flags.Duration("interval", time.Second, "time between reports")
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), pvUsage)
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return nil
+		}
+		return err
+	}
+	if flags.NArg() > 1 {
+		flags.Usage()
+		return errors.New("at most one file may be given")
+	} else if *interval <= 0 {
+		return errors.New("interval must be positive")
+	}
+
+	var total bytez.Size
+	if *sizeStr != "" {
+		size, err := bytez.AsInt(*sizeStr)
+		if err != nil {
+			return fmt.Errorf("invalid size %q: %v", *sizeStr, err)
+		}
+		total = bytez.Size(size)
+	}
+
+	var in io.Reader = os.Stdin
+	if flags.NArg() == 1 {
+		file, err := os.Open(flags.Arg(0))
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		if info, err := file.Stat(); err == nil && info.Mode().IsRegular() && total == 0 {
+			total = bytez.Size(info.Size())
+		}
+		in = file
+	}
+
+	return pv(os.Stdout, in, os.Stderr, total, *interval)
+}
+
+// pv copies src to dst, writing a progress report to status every interval and a final report
+// when done.
+func pv(dst io.Writer, src io.Reader, status io.Writer, total bytez.Size, interval time.Duration) error {
+	progress := bytez.NewProgress(total)
+	done := make(chan error, 1)
+	go func() {
+		_, err := io.Copy(io.MultiWriter(dst, progress), src)
+		done <- err
+	}()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var last bytez.Size
+	var width int
+	report := func(current bytez.Rate, final bool) {
+		line := pvReport(progress, current, final)
+		pad := width - len(line)
+		if pad < 0 {
+			pad = 0
+		}
+		width = len(line)
+		end := ""
+		if final {
+			end = "\n"
+		}
+		fmt.Fprintf(status, "\r%s%s%s", line, strings.Repeat(" ", pad), end)
+	}
+
+	for {
+		select {
+		case <-ticker.C:
+			now := progress.Done()
+			report(bytez.RateOf(now-last, interval), false)
+			last = now
+		case err := <-done:
+			report(0, true)
+			return err
+		}
+	}
+}
+
+// pvReport returns a report like "11.5MiB of 100MiB (11%)  2.3MiB/s  avg 2.1MiB/s  ETA 38s". The
+// final report omits the current rate and the ETA.
+func pvReport(p *bytez.Progress, current bytez.Rate, final bool) string {
+	var b strings.Builder
+	b.WriteString(pvFormatter.Format(p.Done()))
+	if p.Total != 0 {
+		fmt.Fprintf(&b, " of %s (%d%%)", pvFormatter.Format(p.Total), int(p.Fraction()*100))
+	}
+	if !final {
+		fmt.Fprintf(&b, "  %s/s", pvFormatter.Format(bytez.Size(current)))
+	}
+	fmt.Fprintf(&b, "  avg %s/s", pvFormatter.Format(bytez.Size(p.Rate())))
+	if eta, ok := p.ETA(); ok && !final {
+		fmt.Fprintf(&b, "  ETA %s", eta.Round(time.Second))
+	}
+	return b.String()
+}
@@ -0,0 +1,60 @@
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nexvium/bytez"
+	"github.com/stretchr/testify/require"
+)
+
+// slowReader returns its data in small pieces, pausing between them.
+type slowReader struct {
+	r     io.Reader
+	pause time.Duration
+}
+
+func (s *slowReader) Read(p []byte) (int, error) {
+	time.Sleep(s.pause)
+	if len(p) > 1024 {
+		p = p[:1024]
+	}
+	return s.r.Read(p)
+}
+
+func TestPv(t *testing.T) {
+	data := strings.Repeat("x", 8*1024)
+	var out, status bytes.Buffer
+	src := &slowReader{r: strings.NewReader(data), pause: 10 * time.Millisecond}
+
+	require.NoError(t, pv(&out, src, &status, bytez.Size(len(data)), 5*time.Millisecond))
+	if testing.Verbose() {
+		fmt.Printf("%q\n", status.String())
+	}
+	require.Equal(t, data, out.String())
+
+	// Intermediate reports are overwritten by the final one, which ends the line.
+	reports := strings.Split(strings.TrimSuffix(status.String(), "\n"), "\r")
+	require.Greater(t, len(reports), 2)
+	require.Contains(t, reports[1], " of 8KiB (")
+	final := strings.TrimRight(reports[len(reports)-1], " ")
+	require.Regexp(t, `^8KiB of 8KiB \(100%\)  avg \S+/s$`, final)
+	require.True(t, strings.HasSuffix(status.String(), "\n"))
+}
+
+func TestPvUnknownSize(t *testing.T) {
+	var out, status bytes.Buffer
+	require.NoError(t, pv(&out, strings.NewReader("hello"), &status, 0, time.Hour))
+	require.Equal(t, "hello", out.String())
+	require.Regexp(t, `^\r5  avg \S+/s\n$`, status.String())
+}
@@ -0,0 +1,39 @@
+//go:build !bytez_minimal
+
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package bytez
+
+// EqualSizeStr reports whether a and b, in the syntax accepted by AsInt, specify the same size,
+// like "1GiB" and "1024MiB". The error is that of AsInt for the first that cannot be parsed; its
+// Input tells which one it is.
+func EqualSizeStr(a, b string) (bool, error) {
+	cmp, err := CompareSizeStr(a, b)
+	return cmp == 0 && err == nil, err
+}
+
+// CompareSizeStr compares the sizes specified by a and b, in the syntax accepted by AsInt, and
+// returns -1 if a is smaller, 0 if they are equal, or +1 if a is larger, like "1.5GiB" and
+// "2000mb". Errors are those of EqualSizeStr.
+func CompareSizeStr(a, b string) (int, error) {
+	x, err := AsInt(a)
+	if err != nil {
+		return 0, err
+	}
+	y, err := AsInt(b)
+	if err != nil {
+		return 0, err
+	}
+
+	switch {
+	case x < y:
+		return -1, nil
+	case x > y:
+		return +1, nil
+	}
+	return 0, nil
+}
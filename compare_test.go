@@ -0,0 +1,58 @@
+//go:build !bytez_minimal
+
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package bytez
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareSizeStr(t *testing.T) {
+	var tests = []struct {
+		a, b string
+		cmp  int
+		err  bool
+	}{
+		{"1GiB", "1024MiB", 0, false},
+		{"1 gigabyte", "1000mb", 0, false},
+		{"1KB", "1kb", +1, false},
+		{"1.5GiB", "2000mb", -1, false},
+		{"0", "0 TiB", 0, false},
+		{"4096", "4KiB", 0, false},
+		{"1GiBs", "1GiB", 0, true},
+		{"1GiB", "", 0, true},
+	}
+
+	for _, test := range tests {
+		cmp, err := CompareSizeStr(test.a, test.b)
+		eq, eqErr := EqualSizeStr(test.a, test.b)
+		if testing.Verbose() {
+			fmt.Printf("%q %q --> %v, %v, %v\n", test.a, test.b, cmp, eq, err)
+		}
+		if test.err {
+			require.Error(t, err, test.a)
+			require.Error(t, eqErr, test.a)
+			require.False(t, eq)
+		} else {
+			require.NoError(t, err, test.a)
+			require.NoError(t, eqErr, test.a)
+			require.Equal(t, test.cmp, cmp, test.a)
+			require.Equal(t, test.cmp == 0, eq, test.a)
+
+			cmp, err = CompareSizeStr(test.b, test.a)
+			require.NoError(t, err, test.b)
+			require.Equal(t, -test.cmp, cmp, test.b)
+		}
+	}
+
+	_, err := EqualSizeStr("1GiB", "1 GiBs")
+	require.Equal(t, "1 GiBs", err.(*ParseError).Input)
+}
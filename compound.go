@@ -0,0 +1,77 @@
+//go:build !bytez_minimal
+
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package bytez
+
+import (
+	"math/bits"
+	"strings"
+)
+
+// WithCompound accepts sizes made of several components in different units, which are added up,
+// like "1GiB512MiB" or "2TB 300GB", as time.ParseDuration does for "1h30m". Each component has
+// the syntax accepted by the parser, and every component must have units if there are several.
+// Components may be separated by whitespace, unless WithStrict is given. ParseDetailed reports the
+// units of the last component. It cannot be combined with profiles that parse sizes themselves,
+// like Systemd, which already accepts compound sizes.
+func WithCompound() Option {
+	return func(p *Parser) error {
+		p.compound = true
+		return nil
+	}
+}
+
+// parseCompound returns the sum of the components of the size specified by str, as parse does.
+func (p *Parser) parseCompound(str string, res *ParseResult) (Size, int, error) {
+	group, point := p.separators()
+
+	var total uint64
+	start := len(str) - len(strings.TrimLeft(str, " \t\r\n"))
+	if p.strict {
+		start = 0
+	}
+	for first := true; ; first = false {
+		// A component ends after the letters of its units; one without units takes the rest.
+		end := len(str)
+		if _, n, err := scanNumber(str[start:], group, point); err == nil {
+			idx := start + n
+			idx += spaces(str[idx:])
+			units := idx
+			for idx < len(str) && isASCIILetter(str[idx]) {
+				idx++
+			}
+			if idx > units {
+				end = idx
+			} else if !first {
+				return 0, start + n, ErrMissingUnits
+			}
+		}
+
+		size, offset, err := p.parseSingle(str[start:end], res)
+		if err != nil {
+			return 0, start + offset, err
+		}
+		var carry uint64
+		if total, carry = bits.Add64(total, uint64(size), 0); carry != 0 {
+			return 0, start, ErrOverflow
+		} else if p.hasMax && Size(total) > p.max {
+			return 0, start, ErrTooLarge
+		}
+
+		gap := spaces(str[end:])
+		if p.strict && gap > 0 {
+			return 0, end, ErrWhitespace
+		} else if end+gap == len(str) {
+			if p.hasMin && Size(total) < p.min {
+				return 0, spaces(str), ErrTooSmall
+			}
+			return Size(total), 0, nil
+		}
+		start = end + gap
+	}
+}
@@ -0,0 +1,91 @@
+//go:build !bytez_minimal
+
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package bytez
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompound(t *testing.T) {
+	var tests = []struct {
+		opts []Option
+		in   string
+		out  uint64
+		err  bool
+	}{
+		{nil, "1GiB512MiB", Gibibyte + 512*Mebibyte, false},
+		{nil, "2TB 300GB", 2*Tebibyte + 300*Gibibyte, false},
+		{nil, "2tb 300gb", 2*Terabyte + 300*Gigabyte, false},
+		{nil, " 1 GiB, 512 MiB ", 0, true},
+		{nil, " 1 GiB 512 MiB ", Gibibyte + 512*Mebibyte, false},
+		{nil, "1.5GiB 1.5KiB", 3*Gibibyte/2 + 1536, false},
+		{nil, "1 gigabyte 1 byte", Gigabyte + 1, false},
+		{nil, "0x10KiB 1e3kb", 16*Kibibyte + Megabyte, false},
+		{nil, "1,024KiB 1KiB", Mebibyte + Kibibyte, false},
+		{nil, "4096", 4096, false},
+		{nil, "4GiB", 4 * Gibibyte, false},
+		{nil, "1GiB 512", 0, true},
+		{nil, "512 1GiB", 0, true},
+		{nil, "1GiB 512MiBs", 0, true},
+		{nil, "1GiB,512MiB", 0, true},
+		{nil, "1GiB+512MiB", 0, true},
+		{nil, "15EiB 1EiB", 0, true},
+		{nil, "", 0, true},
+		{[]Option{WithStrict()}, "1GiB512MiB", Gibibyte + 512*Mebibyte, false},
+		{[]Option{WithStrict()}, "1GiB 512MiB", 0, true},
+		{[]Option{WithStrict()}, "1GiB512MiB ", 0, true},
+		{[]Option{WithStrict()}, " 1GiB512MiB", 0, true},
+		{[]Option{WithMax(Size(Gibibyte))}, "1GiB 1", 0, true},
+		{[]Option{WithMax(Size(Gibibyte))}, "512MiB 512MiB", Gibibyte, false},
+		{[]Option{WithMax(Size(Gibibyte))}, "512MiB 513MiB", 0, true},
+		{[]Option{WithMin(Size(Gibibyte))}, "1GiB512MiB", 3 * Gibibyte / 2, false},
+		{[]Option{WithMin(Size(Gibibyte))}, "512MiB 511MiB", 0, true},
+		{[]Option{WithUnits("GiB", "MiB")}, "1GiB 1KiB", 0, true},
+		{[]Option{WithKeywords(Unlimited)}, "unlimited", uint64(Unlimited), false},
+		{[]Option{WithProfile(Kubernetes)}, "1Gi512Mi", Gibibyte + 512*Mebibyte, false},
+		{[]Option{WithDecimalComma()}, "1,5GiB 1.024KiB", 3*Gibibyte/2 + Mebibyte, false},
+	}
+
+	for _, test := range tests {
+		out, err := ParseSize(test.in, append([]Option{WithCompound()}, test.opts...)...)
+		if testing.Verbose() {
+			fmt.Printf("%q --> %v, %v\n", test.in, out, err)
+		}
+		if test.err {
+			require.Error(t, err, test.in)
+		} else {
+			require.NoError(t, err, test.in)
+			require.Equal(t, Size(test.out), out, test.in)
+		}
+	}
+
+	p, err := NewParser(WithCompound())
+	require.NoError(t, err)
+	_, err = p.Parse("1GiB 512MiBs")
+	var perr *ParseError
+	require.True(t, errors.As(err, &perr))
+	require.Equal(t, 8, perr.Offset)
+	require.Equal(t, "MiB", perr.Suggestion)
+
+	_, err = p.Parse("1GiB 512")
+	require.True(t, errors.As(err, &perr))
+	require.Equal(t, ErrMissingUnits, perr.Err)
+	require.Equal(t, 8, perr.Offset)
+
+	res, err := p.ParseDetailed("1GiB 512MiB")
+	require.NoError(t, err)
+	require.Equal(t, ParseResult{Size(Gibibyte + 512*Mebibyte), "MiB", Mebibyte, Base2, false}, res)
+
+	_, err = NewParser(WithCompound(), WithProfile(Nginx))
+	require.Error(t, err)
+}
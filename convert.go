@@ -0,0 +1,84 @@
+//go:build !bytez_minimal
+
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package bytez
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ToSize converts a loosely typed value to a Size, as found in the map[string]any values returned
+// by JSON and YAML decoders: strings and byte slices are parsed by AsInt, like "64MiB", integers
+// of any type must not be negative, floats must be whole numbers, like 1048576.0, and json.Number
+// values are parsed by AsInt, so they may have exponents, like "1e6". Sizes are returned as they
+// are. Other types are rejected.
+func ToSize(v any) (Size, error) {
+	switch val := v.(type) {
+	case Size:
+		return val, nil
+	case string:
+		return toSize(AsInt(val))
+	case []byte:
+		return toSize(AsIntBytes(val))
+	case json.Number:
+		return toSize(AsInt(string(val)))
+	case int:
+		return fromInt(int64(val))
+	case int8:
+		return fromInt(int64(val))
+	case int16:
+		return fromInt(int64(val))
+	case int32:
+		return fromInt(int64(val))
+	case int64:
+		return fromInt(val)
+	case uint:
+		return Size(val), nil
+	case uint8:
+		return Size(val), nil
+	case uint16:
+		return Size(val), nil
+	case uint32:
+		return Size(val), nil
+	case uint64:
+		return Size(val), nil
+	case uintptr:
+		return Size(val), nil
+	case float32:
+		return fromFloat(float64(val))
+	case float64:
+		return fromFloat(val)
+	case nil:
+		return 0, errors.New("cannot convert nil to Size")
+	}
+	return 0, fmt.Errorf("cannot convert %T to Size", v)
+}
+
+// toSize returns the result of AsInt as a Size.
+func toSize(val uint64, err error) (Size, error) {
+	return Size(val), err
+}
+
+// fromInt returns val as a Size if it is not negative.
+func fromInt(val int64) (Size, error) {
+	if val < 0 {
+		return 0, fmt.Errorf("negative value %d is not a valid size", val)
+	}
+	return Size(val), nil
+}
+
+// fromFloat returns val as a Size if it is a whole number that fits.
+func fromFloat(val float64) (Size, error) {
+	if val < 0 || val >= math.MaxUint64 || val != math.Trunc(val) {
+		return 0, fmt.Errorf("value %v is not a valid size", val)
+	}
+	return Size(val), nil
+}
@@ -0,0 +1,76 @@
+//go:build !bytez_minimal
+
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package bytez
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestToSize(t *testing.T) {
+	var positive = []struct {
+		in  any
+		out uint64
+	}{
+		{Size(Mebibyte), Mebibyte},
+		{"64MiB", 64 * Mebibyte},
+		{[]byte("1.5 GiB"), 3 * Gibibyte / 2},
+		{json.Number("1048576"), Mebibyte},
+		{json.Number("1e6"), 1000000},
+		{int(4096), 4096},
+		{int8(127), 127},
+		{int16(1024), 1024},
+		{int32(1 << 30), Gibibyte},
+		{int64(math.MaxInt64), math.MaxInt64},
+		{uint(4096), 4096},
+		{uint8(255), 255},
+		{uint16(65535), 65535},
+		{uint32(1 << 31), 1 << 31},
+		{uint64(1<<64 - 1), 1<<64 - 1},
+		{uintptr(8), 8},
+		{float32(1024), 1024},
+		{float64(1048576), Mebibyte},
+		{0.0, 0},
+	}
+
+	for _, test := range positive {
+		out, err := ToSize(test.in)
+		if testing.Verbose() {
+			fmt.Printf("%T(%v) --> %v\n", test.in, test.in, out)
+		}
+		require.NoError(t, err, "%T(%v)", test.in, test.in)
+		require.Equal(t, Size(test.out), out, "%T(%v)", test.in, test.in)
+	}
+
+	for _, in := range []any{nil, "", "64MiBs", []byte("x"), json.Number("1.5"), int(-1), int8(-1),
+		int64(math.MinInt64), float32(-1), 1.5, math.NaN(), math.Inf(1), 1e20, true, []int{1},
+		struct{}{}, BitSize(8)} {
+		_, err := ToSize(in)
+		if testing.Verbose() {
+			fmt.Printf("%T(%v) ==> %v\n", in, in, err)
+		}
+		require.Error(t, err, "%T(%v)", in, in)
+	}
+
+	// Values from decoded configurations convert directly.
+	var conf map[string]any
+	dec := json.NewDecoder(strings.NewReader(`{"cache": "512MiB", "buffer": 65536}`))
+	require.NoError(t, dec.Decode(&conf))
+	cache, err := ToSize(conf["cache"])
+	require.NoError(t, err)
+	require.Equal(t, Size(512*Mebibyte), cache)
+	buffer, err := ToSize(conf["buffer"])
+	require.NoError(t, err)
+	require.Equal(t, Size(64*Kibibyte), buffer)
+}
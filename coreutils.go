@@ -0,0 +1,110 @@
+//go:build !bytez_minimal
+
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package bytez
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Coreutils is the profile of the human-readable sizes printed by GNU coreutils with -h, as in
+// "du -h", "df -h", and "ls -lh": the suffixes "K", "M", "G", "T", "P", and "E" are binary and
+// uppercase, with no space or "B", like "4.0K" or "1.5G", and plain numbers are bytes. Since the
+// tools round sizes up to at most one decimal, parsed sizes are approximate. The decimal sizes
+// printed with --si are not supported.
+var Coreutils = &Profile{name: "coreutils", strict: true,
+	names: []string{"", "K", "M", "G", "T", "P", "E"}, units: map[string]uint64{
+		"K": Kibibyte, "M": Mebibyte, "G": Gibibyte, "T": Tebibyte, "P": Pebibyte, "E": Exbibyte,
+	},
+}
+
+// coreutilsParser parses the sizes of the Coreutils profile.
+var coreutilsParser = &Parser{profile: Coreutils}
+
+// ParseDuLine parses a line printed by "du -h", like "4.0K\t./src", and returns the size and the
+// path.
+func ParseDuLine(line string) (Size, string, error) {
+	field, path, ok := strings.Cut(strings.TrimRight(line, "\r\n"), "\t")
+	if !ok {
+		return 0, "", fmt.Errorf("invalid du line %q: missing tab", line)
+	}
+	size, err := coreutilsParser.Parse(field)
+	if err != nil {
+		return 0, "", fmt.Errorf("invalid du line %q: %v", line, err)
+	}
+	return size, path, nil
+}
+
+// DfEntry is a file system as listed by "df -h".
+type DfEntry struct {
+	Filesystem        string
+	Size, Used, Avail Size
+	MountedOn         string
+}
+
+// ParseDfLine parses a line printed by "df -h" with the default columns, like
+// "/dev/sda1  50G  21G  27G  44% /". The header line is rejected like any other invalid line.
+func ParseDfLine(line string) (DfEntry, error) {
+	fields, starts := splitFields(line, 6)
+	if len(fields) < 6 {
+		return DfEntry{}, fmt.Errorf("invalid df line %q: expected 6 columns", line)
+	}
+
+	var sizes [3]Size
+	for i := range sizes {
+		size, err := coreutilsParser.Parse(fields[i+1])
+		if err != nil {
+			return DfEntry{}, fmt.Errorf("invalid df line %q: %v", line, err)
+		}
+		sizes[i] = size
+	}
+	mount := strings.TrimRight(line[starts[5]:], " \t\r\n")
+	return DfEntry{Filesystem: fields[0], Size: sizes[0], Used: sizes[1], Avail: sizes[2],
+		MountedOn: mount}, nil
+}
+
+// ParseLsLine parses a line printed by "ls -lh" for a file, like
+// "-rw-r--r-- 1 root root 1.5K Jan  2 12:00 notes.txt", and returns the size and the name. The
+// targets of symbolic links are removed from their names. Lines for directory totals and device
+// files, which have no size, are rejected.
+func ParseLsLine(line string) (Size, string, error) {
+	fields, starts := splitFields(line, 9)
+	if len(fields) < 9 {
+		return 0, "", fmt.Errorf("invalid ls line %q: expected 9 columns", line)
+	}
+	size, err := coreutilsParser.Parse(fields[4])
+	if err != nil {
+		return 0, "", fmt.Errorf("invalid ls line %q: %v", line, err)
+	}
+
+	name := strings.TrimRight(line[starts[8]:], "\r\n")
+	if strings.HasPrefix(fields[0], "l") {
+		name, _, _ = strings.Cut(name, " -> ")
+	}
+	return size, name, nil
+}
+
+// splitFields returns up to n fields of line separated by whitespace, along with their offsets in
+// line.
+func splitFields(line string, n int) ([]string, []int) {
+	fields, starts := make([]string, 0, n), make([]int, 0, n)
+	for i := 0; len(fields) < n; {
+		i += spaces(line[i:])
+		if i == len(line) {
+			break
+		}
+		end := i + 1
+		for end < len(line) && spaces(line[end:]) == 0 {
+			end++
+		}
+		fields, starts = append(fields, line[i:end]), append(starts, i)
+		i = end
+	}
+	return fields, starts
+}
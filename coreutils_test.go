@@ -0,0 +1,131 @@
+//go:build !bytez_minimal
+
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package bytez
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoreutilsProfile(t *testing.T) {
+	var tests = []struct {
+		in  string
+		out uint64
+		err bool
+	}{
+		{"0", 0, false},
+		{"512", 512, false},
+		{"4.0K", 4 * Kibibyte, false},
+		{"1.5G", 3 * Gibibyte / 2, false},
+		{"1.1K", 1126, false},
+		{"12T", 12 * Tebibyte, false},
+		{"4 K", 0, true},
+		{"4KB", 0, true},
+		{"4KiB", 0, true},
+		{"4k", 0, true},
+		{"1.5", 0, true},
+		{"1,024", 0, true},
+	}
+
+	p, err := NewParser(WithProfile(Coreutils))
+	require.NoError(t, err)
+	require.Equal(t, "coreutils", Coreutils.String())
+
+	for _, test := range tests {
+		out, err := p.Parse(test.in)
+		if testing.Verbose() {
+			fmt.Printf("%q --> %v, %v\n", test.in, out, err)
+		}
+		if test.err {
+			require.Error(t, err, test.in)
+		} else {
+			require.NoError(t, err, test.in)
+			require.Equal(t, Size(test.out), out, test.in)
+		}
+	}
+
+	f := NewFormatter(FormatOptions{Profile: Coreutils, Precision: 1})
+	require.Equal(t, "1.5G", f.Format(Size(3*Gibibyte/2)))
+}
+
+func TestParseDuLine(t *testing.T) {
+	size, path, err := ParseDuLine("4.0K\t./src\n")
+	require.NoError(t, err)
+	require.Equal(t, Size(4*Kibibyte), size)
+	require.Equal(t, "./src", path)
+
+	size, path, err = ParseDuLine("1.2G\t/home/user/My Documents")
+	require.NoError(t, err)
+	require.Equal(t, Size(1288490189), size)
+	require.Equal(t, "/home/user/My Documents", path)
+
+	for _, in := range []string{"", "4.0K ./src", "4.0 K\t./src", "total"} {
+		_, _, err := ParseDuLine(in)
+		if testing.Verbose() {
+			fmt.Printf("%q ==> %v\n", in, err)
+		}
+		require.Error(t, err, in)
+	}
+}
+
+func TestParseDfLine(t *testing.T) {
+	entry, err := ParseDfLine("/dev/sda1        50G   21G   27G  44% /")
+	require.NoError(t, err)
+	require.Equal(t, DfEntry{Filesystem: "/dev/sda1", Size: Size(50 * Gibibyte), Used: Size(21 * Gibibyte),
+		Avail: Size(27 * Gibibyte), MountedOn: "/"}, entry)
+
+	entry, err = ParseDfLine("tmpfs  3.9G  1.5M  3.9G   1% /media/USB Drive\n")
+	require.NoError(t, err)
+	require.Equal(t, Size(3*Mebibyte/2), entry.Used)
+	require.Equal(t, "/media/USB Drive", entry.MountedOn)
+
+	for _, in := range []string{"", "Filesystem      Size  Used Avail Use% Mounted on",
+		"/dev/sda1 50G 21G 27G 44%", "/dev/sda1 50G - 27G 44% /"} {
+		_, err := ParseDfLine(in)
+		if testing.Verbose() {
+			fmt.Printf("%q ==> %v\n", in, err)
+		}
+		require.Error(t, err, in)
+	}
+}
+
+func TestParseLsLine(t *testing.T) {
+	var tests = []struct {
+		in   string
+		size uint64
+		name string
+	}{
+		{"-rw-r--r-- 1 root root 1.5K Jan  2 12:00 notes.txt", 1536, "notes.txt"},
+		{"-rw-r--r--  1 user staff  512 Mar 14  2023 my notes.txt\n", 512, "my notes.txt"},
+		{"drwxr-xr-x 2 user user 4.0K Feb 29 09:15 src", 4 * Kibibyte, "src"},
+		{"lrwxrwxrwx 1 root root   7 Jan  2 12:00 bin -> usr/bin", 7, "bin"},
+		{"-rw------- 1 user user 2.0G Jan  2 12:00 disk.img", 2 * Gibibyte, "disk.img"},
+	}
+
+	for _, test := range tests {
+		size, name, err := ParseLsLine(test.in)
+		if testing.Verbose() {
+			fmt.Printf("%q --> %v %q\n", test.in, size, name)
+		}
+		require.NoError(t, err, test.in)
+		require.Equal(t, Size(test.size), size, test.in)
+		require.Equal(t, test.name, name, test.in)
+	}
+
+	for _, in := range []string{"", "total 12K", "brw-rw---- 1 root disk 8, 0 Jan  2 12:00 sda",
+		"-rw-r--r-- 1 root root 1.5KB Jan  2 12:00 notes.txt"} {
+		_, _, err := ParseLsLine(in)
+		if testing.Verbose() {
+			fmt.Printf("%q ==> %v\n", in, err)
+		}
+		require.Error(t, err, in)
+	}
+}
@@ -0,0 +1,66 @@
+//go:build !bytez_minimal
+
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package bytez
+
+// ParseResult is a size parsed by Parser.ParseDetailed, along with how its units were
+// interpreted.
+type ParseResult struct {
+	Size Size
+
+	// Units are the units of the size as written, like "KB", or the default units of the parser
+	// if it had none. They are empty for sizes without units.
+	Units string
+
+	// Unit is the number of bytes in Units, or 1 if there are none. It is 0 for keywords and for
+	// sizes parsed by profiles that parse sizes themselves, like Systemd, which report only the
+	// size.
+	Unit uint64
+
+	// Base is Base2 or Base10 if the units have a binary or decimal prefix, like "KB" read as
+	// kibibytes or "Mbit" as megabits, and BaseAuto otherwise, as for bytes and custom units.
+	Base Base
+
+	// Ambiguous reports whether the base was not named by the units but chosen by a convention,
+	// like the case of the first letter in "KB" and "kb" or WithBase, so that the writer may have
+	// meant the other one. Programs loading configurations can warn users about such units. Units
+	// of profiles, custom units, and units accepted by WithStrictUnits are never ambiguous.
+	Ambiguous bool
+}
+
+// ParseDetailed is like Parse but also returns the units of the size and the base in which they
+// were interpreted.
+func (p *Parser) ParseDetailed(str string) (ParseResult, error) {
+	var res ParseResult
+	size, offset, err := p.parse(str, &res)
+	if err != nil {
+		return ParseResult{}, p.error(str, offset, err)
+	}
+	res.Size = size
+
+	if _, ok := p.custom[res.Units]; ok {
+		return res, nil
+	}
+	res.Base = baseOf(res.Unit)
+	res.Ambiguous = res.Base != BaseAuto && !explicitBase(res.Units) && !p.si && p.profile == nil
+	return res, nil
+}
+
+// baseOf returns the base of the given unit, or of the bit unit with the same prefix, or BaseAuto
+// if it has no prefix.
+func baseOf(unit uint64) Base {
+	for i := 1; i < len(valuesBase10); i++ {
+		switch unit {
+		case valuesBase2[i], valuesBase2[i] / 8:
+			return Base2
+		case valuesBase10[i], valuesBase10[i] / 8:
+			return Base10
+		}
+	}
+	return BaseAuto
+}
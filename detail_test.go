@@ -0,0 +1,62 @@
+//go:build !bytez_minimal
+
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package bytez
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDetailed(t *testing.T) {
+	var r Registry
+	require.NoError(t, r.RegisterUnit("pg", 4096))
+
+	var tests = []struct {
+		opts      []Option
+		in        string
+		out       uint64
+		units     string
+		unit      uint64
+		base      Base
+		ambiguous bool
+	}{
+		{nil, "4096", 4096, "", 1, BaseAuto, false},
+		{nil, "4096 bytes", 4096, "bytes", 1, BaseAuto, false},
+		{nil, "4KB", 4 * Kibibyte, "KB", Kibibyte, Base2, true},
+		{nil, "4kb", 4 * Kilobyte, "kb", Kilobyte, Base10, true},
+		{nil, "4 KiB", 4 * Kibibyte, "KiB", Kibibyte, Base2, false},
+		{nil, "4 kilobytes", 4 * Kilobyte, "kilobytes", Kilobyte, Base10, false},
+		{nil, "100Mbit", 100 * Megabyte / 8, "Mbit", Megabyte / 8, Base10, false},
+		{[]Option{WithBase(Base2)}, "1.5mb", 3 * Mebibyte / 2, "mb", Mebibyte, Base2, true},
+		{[]Option{WithIgnoreCase(Base10)}, "4Gi", 4 * Gibibyte, "Gi", Gibibyte, Base2, false},
+		{[]Option{WithStrictUnits()}, "4MB", 4 * Megabyte, "MB", Megabyte, Base10, false},
+		{[]Option{WithProfile(JEDEC)}, "4KB", 4 * Kibibyte, "KB", Kibibyte, Base2, false},
+		{[]Option{WithDefaultUnit("M")}, "2", 2 * Mebibyte, "M", Mebibyte, Base2, true},
+		{[]Option{WithRegistry(&r)}, "2pg", 8192, "pg", 4096, BaseAuto, false},
+		{[]Option{WithKeywords(Unlimited)}, "none", 0, "", 0, BaseAuto, false},
+		{[]Option{WithProfile(Systemd)}, "1G 512M", 3 * Gibibyte / 2, "", 0, BaseAuto, false},
+	}
+
+	for _, test := range tests {
+		p, err := NewParser(test.opts...)
+		require.NoError(t, err)
+		out, err := p.ParseDetailed(test.in)
+		if testing.Verbose() {
+			fmt.Printf("%q --> %+v\n", test.in, out)
+		}
+		require.NoError(t, err, test.in)
+		expected := ParseResult{Size(test.out), test.units, test.unit, test.base, test.ambiguous}
+		require.Equal(t, expected, out, test.in)
+	}
+
+	_, err := new(Parser).ParseDetailed("4 KiBs")
+	require.EqualError(t, err, `invalid units: did you mean "KiB"?`)
+}
@@ -0,0 +1,72 @@
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+// Package entsize provides ent (entgo.io/ent) schema helpers for byte-size fields that are stored
+// as int64 columns but exposed as bytez.Size in the generated code. For example:
+//
+//	func (User) Fields() []ent.Field {
+//		return []ent.Field{
+//			field.String("name"),
+//			entsize.Field("quota"),
+//			entsize.Of(field.Int64("upload_limit").Optional().Comment("Largest upload.")),
+//		}
+//	}
+//
+// This package is a separate module so that package bytez itself does not depend on ent.
+package entsize
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/field"
+	"github.com/nexvium/bytez"
+)
+
+// ValueScanner converts between bytez.Size field values and int64 column values. Sizes larger
+// than the maximum int64 cannot be stored and negative column values cannot be scanned.
+var ValueScanner field.TypeValueScanner[bytez.Size] = field.ValueScannerFunc[bytez.Size, *sql.NullInt64]{
+	V: func(sz bytez.Size) (driver.Value, error) {
+		if uint64(sz) > math.MaxInt64 {
+			return nil, fmt.Errorf("size %d is too large to store", uint64(sz))
+		}
+		return int64(sz), nil
+	},
+	S: func(ns *sql.NullInt64) (bytez.Size, error) {
+		if !ns.Valid {
+			return 0, errors.New("cannot scan NULL into bytez.Size")
+		} else if ns.Int64 < 0 {
+			return 0, fmt.Errorf("negative value %d is not a valid size", ns.Int64)
+		}
+		return bytez.Size(ns.Int64), nil
+	},
+}
+
+// sizeBuilder is implemented by the ent integer field builders, like the one returned by
+// field.Int64.
+type sizeBuilder[B any] interface {
+	GoType(typ any) B
+	ValueScanner(vs any) B
+}
+
+// Of configures an ent integer field builder to expose the field as bytez.Size, and returns it
+// so that other options can be chained. Use it to customize a size field beyond what Field
+// provides:
+//
+//	entsize.Of(field.Int64("limit")).Optional().Nillable()
+func Of[B sizeBuilder[B]](builder B) B {
+	return builder.GoType(bytez.Size(0)).ValueScanner(ValueScanner)
+}
+
+// Field returns a required field named name that is stored as an int64 column and exposed as
+// bytez.Size.
+func Field(name string) ent.Field {
+	return Of(field.Int64(name))
+}
@@ -0,0 +1,65 @@
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package entsize
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"entgo.io/ent/schema/field"
+	"github.com/nexvium/bytez"
+	"github.com/stretchr/testify/require"
+)
+
+func TestField(t *testing.T) {
+	desc := Field("quota").Descriptor()
+	require.NoError(t, desc.Err)
+	require.Equal(t, "quota", desc.Name)
+	require.Equal(t, field.TypeInt64, desc.Info.Type)
+	require.Equal(t, "bytez.Size", desc.Info.Ident)
+
+	desc = Of(field.Int64("limit")).Optional().Descriptor()
+	require.NoError(t, desc.Err)
+	require.True(t, desc.Optional)
+	require.Equal(t, "bytez.Size", desc.Info.Ident)
+}
+
+func TestValueScanner(t *testing.T) {
+	val, err := ValueScanner.Value(bytez.Size(64 * bytez.Mebibyte))
+	require.NoError(t, err)
+	require.Equal(t, int64(64*bytez.Mebibyte), val)
+
+	_, err = ValueScanner.Value(bytez.Size(1<<64 - 1))
+	require.Error(t, err)
+
+	var tests = []struct {
+		in  sql.NullInt64
+		out bytez.Size
+		ok  bool
+	}{
+		{sql.NullInt64{Int64: 4096, Valid: true}, bytez.Size(4096), true},
+		{sql.NullInt64{Int64: -1, Valid: true}, 0, false},
+		{sql.NullInt64{}, 0, false},
+	}
+
+	require.IsType(t, &sql.NullInt64{}, ValueScanner.ScanValue())
+
+	for _, test := range tests {
+		in := test.in
+		out, err := ValueScanner.FromValue(&in)
+		if testing.Verbose() {
+			fmt.Printf("%+v --> %v, %v\n", test.in, out, err)
+		}
+		if test.ok {
+			require.NoError(t, err)
+			require.Equal(t, test.out, out)
+		} else {
+			require.Error(t, err)
+		}
+	}
+}
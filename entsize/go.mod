@@ -0,0 +1,18 @@
+module github.com/nexvium/bytez/entsize
+
+go 1.24
+
+require (
+	entgo.io/ent v0.14.6
+	github.com/nexvium/bytez v0.0.0-00010101000000-000000000000
+	github.com/stretchr/testify v1.8.4
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/kr/text v0.2.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/nexvium/bytez => ../
@@ -0,0 +1,42 @@
+//go:build !bytez_minimal
+
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package bytez
+
+import (
+	"fmt"
+	"os"
+)
+
+// GetenvSize returns the size in the environment variable with the given name, in the syntax
+// accepted by AsInt, or def if the variable is unset or empty. Errors name the variable and its
+// value, like `CACHE_SIZE="256MiBs": invalid units: did you mean "MiB"?`, and wrap the
+// *ParseError.
+func GetenvSize(name string, def Size) (Size, error) {
+	val := os.Getenv(name)
+	if val == "" {
+		return def, nil
+	}
+	size, err := AsInt(val)
+	if err != nil {
+		return 0, fmt.Errorf("%s=%q: %w", name, val, err)
+	}
+	return Size(size), nil
+}
+
+// MustGetenvSize is like GetenvSize but panics if the variable cannot be parsed. It simplifies
+// the initialization of package-level variables holding sizes, like
+//
+//	var cacheSize = bytez.MustGetenvSize("CACHE_SIZE", 256*bytez.Mebibyte)
+func MustGetenvSize(name string, def Size) Size {
+	size, err := GetenvSize(name, def)
+	if err != nil {
+		panic("bytez: MustGetenvSize: " + err.Error())
+	}
+	return size
+}
@@ -0,0 +1,62 @@
+//go:build !bytez_minimal
+
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package bytez
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetenvSize(t *testing.T) {
+	var tests = []struct {
+		val string
+		out Size
+		err string
+	}{
+		{"", Size(Kibibyte), ""},
+		{"256MiB", Size(256 * Mebibyte), ""},
+		{" 1.5 GiB ", Size(3 * Gibibyte / 2), ""},
+		{"256MiBs", 0, `BYTEZ_TEST_SIZE="256MiBs": invalid units: did you mean "MiB"?`},
+		{"lots", 0, `BYTEZ_TEST_SIZE="lots": no number in string`},
+	}
+
+	for _, test := range tests {
+		t.Setenv("BYTEZ_TEST_SIZE", test.val)
+		out, err := GetenvSize("BYTEZ_TEST_SIZE", Size(Kibibyte))
+		if testing.Verbose() {
+			fmt.Printf("%q --> %v, %v\n", test.val, out, err)
+		}
+		if test.err != "" {
+			require.EqualError(t, err, test.err)
+			var perr *ParseError
+			require.True(t, errors.As(err, &perr), test.val)
+		} else {
+			require.NoError(t, err, test.val)
+			require.Equal(t, test.out, out, test.val)
+		}
+	}
+
+	out, err := GetenvSize("BYTEZ_TEST_UNSET", Size(Mebibyte))
+	require.NoError(t, err)
+	require.Equal(t, Size(Mebibyte), out)
+}
+
+func TestMustGetenvSize(t *testing.T) {
+	t.Setenv("BYTEZ_TEST_SIZE", "4GiB")
+	require.Equal(t, Size(4*Gibibyte), MustGetenvSize("BYTEZ_TEST_SIZE", 0))
+	require.Equal(t, Size(Mebibyte), MustGetenvSize("BYTEZ_TEST_UNSET", Size(Mebibyte)))
+
+	t.Setenv("BYTEZ_TEST_SIZE", "4GiBs")
+	require.PanicsWithValue(t,
+		`bytez: MustGetenvSize: BYTEZ_TEST_SIZE="4GiBs": invalid units: did you mean "GiB"?`,
+		func() { MustGetenvSize("BYTEZ_TEST_SIZE", 0) })
+}
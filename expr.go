@@ -0,0 +1,288 @@
+//go:build !bytez_minimal
+
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package bytez
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Vars maps the names of variables used in expressions to their values.
+type Vars map[string]Size
+
+// Eval evaluates an arithmetic expression of sizes, like "total_ram / 4 + 128MiB", and returns
+// the result. It allows sizing rules to be written in configuration files instead of code.
+//
+// Operands can be sizes in any format accepted by AsInt, like "1.5GiB", "0x40 KiB", or
+// "1,048,576", plain numbers in the same syntax, like "1.5e6", and names of variables in vars,
+// which may be nil. Variable names start with a letter or underscore followed by
+// letters, digits, underscores, and dots. Operators are +, -, *, and /, with the usual
+// precedence, and parentheses can be used for grouping.
+//
+// Plain numbers can have any number of decimals, like "0.25 * total_ram", and exponents of at most
+// 1000, like "1e-3". They are treated as scalars when multiplying and dividing, and as a number of
+// bytes when added to or subtracted from sizes. Two sizes cannot be multiplied, and dividing a size
+// by another size results in a scalar. Percentages, like "10%", are relative to the value they are
+// added to or subtracted from, so "10GiB - 1%" is 99% of 10GiB, and are scalars when multiplying
+// and dividing, so "50% * total_ram" is half of total_ram. Arithmetic is exact; the final result is
+// rounded down to a whole number of bytes and must be a non-negative value that fits in a Size.
+func Eval(expr string, vars Vars) (Size, error) {
+	p := exprParser{str: expr, vars: vars}
+	val, err := p.parseExpr()
+	if err != nil {
+		return 0, err
+	}
+	if p.skipSpace(); p.pos < len(p.str) {
+		return 0, p.errorf("unexpected %q", p.str[p.pos])
+	}
+
+	if val.isPercent {
+		return 0, errors.New("expression result is a percentage")
+	} else if val.num.Sign() < 0 {
+		return 0, errors.New("expression result is negative")
+	}
+	num := new(big.Int).Quo(val.num.Num(), val.num.Denom())
+	if !num.IsUint64() {
+		return 0, errors.New("expression result is too large")
+	}
+	return Size(num.Uint64()), nil
+}
+
+// ParseExpr evaluates an arithmetic expression of sizes, like "1GiB + 512MiB", "2 * 64KiB", or
+// "10GiB - 1%", so that configuration authors can express derived limits without computing them
+// in bytes. It is like Eval without variables.
+func ParseExpr(expr string) (Size, error) {
+	return Eval(expr, nil)
+}
+
+// exprValue is the value of a (sub)expression. Sizes are numbers of bytes while scalars are
+// dimensionless numbers. Percentages are scalars, already divided by 100, that apply to the
+// value they are added to or subtracted from.
+type exprValue struct {
+	num       *big.Rat
+	isSize    bool
+	isPercent bool
+}
+
+type exprParser struct {
+	str  string
+	pos  int
+	vars Vars
+}
+
+func (p *exprParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("offset %d: %s", p.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *exprParser) skipSpace() {
+	for p.pos < len(p.str) && (p.str[p.pos] == ' ' || p.str[p.pos] == '\t' ||
+		p.str[p.pos] == '\r' || p.str[p.pos] == '\n') {
+		p.pos++
+	}
+}
+
+// peek returns the next non-space byte, or 0 at the end of the expression.
+func (p *exprParser) peek() byte {
+	if p.skipSpace(); p.pos < len(p.str) {
+		return p.str[p.pos]
+	}
+	return 0
+}
+
+// parseExpr parses a sequence of terms separated by + and -.
+func (p *exprParser) parseExpr() (exprValue, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return left, err
+	}
+
+	for op := p.peek(); op == '+' || op == '-'; op = p.peek() {
+		opPos := p.pos
+		p.pos++
+		right, err := p.parseTerm()
+		if err != nil {
+			return right, err
+		}
+
+		if right.isPercent && !left.isPercent {
+			right = exprValue{num: new(big.Rat).Mul(left.num, right.num), isSize: left.isSize}
+		} else if left.isPercent && !right.isPercent {
+			p.pos = opPos
+			return left, p.errorf("percentage must follow the value it applies to")
+		}
+
+		if op == '+' {
+			left.num = new(big.Rat).Add(left.num, right.num)
+		} else {
+			left.num = new(big.Rat).Sub(left.num, right.num)
+		}
+		left.isSize = left.isSize || right.isSize
+	}
+	return left, nil
+}
+
+// parseTerm parses a sequence of factors separated by * and /.
+func (p *exprParser) parseTerm() (exprValue, error) {
+	left, err := p.parseFactor()
+	if err != nil {
+		return left, err
+	}
+
+	for op := p.peek(); op == '*' || op == '/'; op = p.peek() {
+		opPos := p.pos
+		p.pos++
+		right, err := p.parseFactor()
+		if err != nil {
+			return right, err
+		}
+
+		if op == '*' {
+			if left.isSize && right.isSize {
+				p.pos = opPos
+				return left, p.errorf("cannot multiply two sizes")
+			}
+			left.num = new(big.Rat).Mul(left.num, right.num)
+			left.isSize = left.isSize || right.isSize
+			left.isPercent = false
+		} else {
+			if right.num.Sign() == 0 {
+				p.pos = opPos
+				return left, p.errorf("division by zero")
+			} else if !left.isSize && right.isSize {
+				p.pos = opPos
+				return left, p.errorf("cannot divide a number by a size")
+			}
+			left.num = new(big.Rat).Quo(left.num, right.num)
+			left.isSize = left.isSize && !right.isSize
+			left.isPercent = false
+		}
+	}
+	return left, nil
+}
+
+// parseFactor parses a size, number, variable, parenthesized expression, or a factor preceded by
+// a unary sign.
+func (p *exprParser) parseFactor() (exprValue, error) {
+	switch ch := p.peek(); {
+	case ch == 0:
+		return exprValue{}, p.errorf("unexpected end of expression")
+	case ch == '+' || ch == '-':
+		p.pos++
+		val, err := p.parseFactor()
+		if err == nil && ch == '-' {
+			val.num = new(big.Rat).Neg(val.num)
+		}
+		return val, err
+	case ch == '(':
+		p.pos++
+		val, err := p.parseExpr()
+		if err != nil {
+			return val, err
+		}
+		if p.peek() != ')' {
+			return val, p.errorf("missing closing parenthesis")
+		}
+		p.pos++
+		return val, nil
+	case isDigit(ch) || ch == '.':
+		return p.parseNumber()
+	case isIdentStart(ch):
+		start := p.pos
+		for p.pos < len(p.str) && isIdentChar(p.str[p.pos]) {
+			p.pos++
+		}
+		name := p.str[start:p.pos]
+		val, ok := p.vars[name]
+		if !ok {
+			p.pos = start
+			return exprValue{}, p.errorf("undefined variable %q", name)
+		}
+		return exprValue{num: new(big.Rat).SetUint64(uint64(val)), isSize: true}, nil
+	default:
+		return exprValue{}, p.errorf("unexpected %q", ch)
+	}
+}
+
+// parseNumber parses a number, which becomes a size if it is followed by units.
+func (p *exprParser) parseNumber() (exprValue, error) {
+	start := p.pos
+	num, n, err := scanNumber(p.str[start:], ',', '.')
+	numEnd := start + n
+	if err != nil {
+		return exprValue{}, p.errorf("invalid number %q: %v", p.str[start:numEnd], err)
+	}
+	p.pos = numEnd
+
+	// Units may follow the number directly or after a single space, as in AsInt.
+	unitStart := p.pos
+	if unitStart < len(p.str) && p.str[unitStart] == ' ' {
+		unitStart++
+	}
+	unitEnd := unitStart
+	for unitEnd < len(p.str) && isLetter(p.str[unitEnd]) {
+		unitEnd++
+	}
+	if _, ok := lookupUnit(p.str[unitStart:unitEnd]); ok {
+		val, err := AsInt(p.str[start:unitEnd])
+		if err != nil {
+			return exprValue{}, p.errorf("%q: %v", p.str[start:unitEnd], err)
+		}
+		p.pos = unitEnd
+		return exprValue{num: new(big.Rat).SetUint64(val), isSize: true}, nil
+	}
+
+	// scanNumber caps exponents, which keeps sizes exact but not scalars.
+	if exp := p.str[start+len(num.text) : numEnd]; exp != "" {
+		if n, err := strconv.Atoi(strings.TrimLeft(exp[1:], "+-")); err != nil || n > maxExponent {
+			p.pos = start
+			return exprValue{}, p.errorf("exponent of %q is larger than %d",
+				p.str[start:numEnd], maxExponent)
+		}
+	}
+	val := num.rat()
+	if numEnd < len(p.str) && p.str[numEnd] == '%' {
+		p.pos++
+		return exprValue{num: val.Quo(val, big.NewRat(100, 1)), isPercent: true}, nil
+	}
+	return exprValue{num: val}, nil
+}
+
+// rat returns the exact value of num.
+func (num number) rat() *big.Rat {
+	if num.prefixed {
+		return new(big.Rat).SetUint64(num.value)
+	}
+
+	// The digits form a whole number to be scaled by the position of the point among them.
+	digits := make([]byte, 0, len(num.text))
+	for i := 0; i < len(num.text); i++ {
+		if isDigit(num.text[i]) {
+			digits = append(digits, num.text[i])
+		}
+	}
+	whole, _ := new(big.Int).SetString(string(digits), 10)
+	exp := num.point - len(digits)
+	if exp >= 0 {
+		scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exp)), nil)
+		return new(big.Rat).SetInt(whole.Mul(whole, scale))
+	}
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(-exp)), nil)
+	return new(big.Rat).SetFrac(whole, scale)
+}
+
+func isIdentStart(b byte) bool {
+	return (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z') || b == '_'
+}
+
+func isIdentChar(b byte) bool {
+	return isIdentStart(b) || isDigit(b) || b == '.'
+}
@@ -0,0 +1,116 @@
+//go:build !bytez_minimal
+
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package bytez
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEval(t *testing.T) {
+	vars := Vars{"total_ram": Size(16 * Gibibyte), "disk.free": Size(100 * Gigabyte)}
+
+	var negative = []struct {
+		in string
+	}{
+		{""},
+		{"1GiB +"},
+		{"(1GiB"},
+		{"1GiB)"},
+		{"2GiB * 2GiB"},
+		{"2 / 1GiB"},
+		{"1GiB / 0"},
+		{"1GiB - 2GiB"},
+		{"unknown / 2"},
+		{"1__0 * 1GiB"},
+		{"1.2.3 * 1GiB"},
+		{"1._5GiB"},
+		{"16 * 1EiB"},
+		{"4 x"},
+		{"10%"},
+		{"1% + 10GiB"},
+		{"10GiB - 101%"},
+		{"10GiB * (1% + 2%) + 1%%"},
+		{".5 * 1GiB"},
+		{"1,00 * 1GiB"},
+		{"0x * 1GiB"},
+		{"1e999 * 1GiB"},
+		{"1e2000 / 1e1999"},
+		{"1GiB * 1e-1001"},
+	}
+
+	for _, test := range negative {
+		_, err := Eval(test.in, vars)
+		if testing.Verbose() {
+			fmt.Printf("\"%v\" ==> %v\n", test.in, err)
+		}
+		require.Error(t, err)
+	}
+
+	var positive = []struct {
+		in  string
+		out uint64
+	}{
+		{"4096", 4096},
+		{"4 KiB", 4 * Kibibyte},
+		{"total_ram / 4 + 128MiB", 4*Gibibyte + 128*Mebibyte},
+		{"0.25 * total_ram", 4 * Gibibyte},
+		{"total_ram * 3 / 4", 12 * Gibibyte},
+		{"(disk.free - 10gb) / 2", 45 * Gigabyte},
+		{"1GiB + 512", Gibibyte + 512},
+		{"-1GiB + 2GiB", Gibibyte},
+		{"1GiB / 3", Gibibyte / 3},
+		{"1.25GiB * 2", 5 * Gibibyte / 2},
+		{"total_ram / 1GiB * 1mb", 16 * Megabyte},
+		{"1_000 * 1.5kb", 1500 * Kilobyte},
+		{"10GiB - 1%", 10 * Gibibyte * 99 / 100},
+		{"1GiB + 50%", 3 * Gibibyte / 2},
+		{"50% * total_ram", 8 * Gibibyte},
+		{"total_ram / 400%", 4 * Gibibyte},
+		{"1GiB + 10% + 10%", Gibibyte * 121 / 100},
+		{"1GiB + (10% + 10%)", Gibibyte * 120 / 100},
+		{"1GiB - -10%", Gibibyte * 110 / 100},
+		{"1.5e6", 1500000},
+		{"1.5e6 + 1KiB", 1500000 + Kibibyte},
+		{"2e-1 * 10GiB", 2 * Gibibyte},
+		{"1.5e6 KiB", 1500000 * Kibibyte},
+		{"1e", Exabyte},
+		{"0x100000", Mebibyte},
+		{"0x40 KiB * 2", 128 * Kibibyte},
+		{"0b101 * 1KiB", 5 * Kibibyte},
+		{"1,048,576 / 2", 512 * Kibibyte},
+		{"1,024 KiB + 1", Mebibyte + 1},
+		{"1.5e1%  * 100", 15},
+		{"1e1000 / 1e999", 10},
+	}
+
+	for _, test := range positive {
+		out, err := Eval(test.in, vars)
+		if testing.Verbose() {
+			fmt.Printf("\"%v\" --> %v\n", test.in, out)
+		}
+		require.NoError(t, err)
+		require.Equal(t, test.out, uint64(out))
+	}
+}
+
+func TestParseExpr(t *testing.T) {
+	sz, err := ParseExpr("1GiB + 512MiB")
+	require.NoError(t, err)
+	require.Equal(t, Size(3*Gibibyte/2), sz)
+
+	sz, err = ParseExpr("2 * 64KiB")
+	require.NoError(t, err)
+	require.Equal(t, Size(128*Kibibyte), sz)
+
+	_, err = ParseExpr("total_ram / 2")
+	require.Error(t, err)
+}
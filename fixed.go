@@ -0,0 +1,53 @@
+//go:build !bytez_minimal
+
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package bytez
+
+// SizeBinary is a Size that is always formatted in binary units, like "1.5GiB", regardless of
+// BYTEZ_FORMAT and SetDefaultFormat, for output read by programs that expect a single style. Sizes
+// that are not a whole or half number of binary units are formatted as plain numbers, like
+// "1000", so that they are parsed back exactly. Text is unmarshaled like Size.
+type SizeBinary Size
+
+// SizeDecimal is like SizeBinary but always formatted in decimal units, like "1.5gb".
+type SizeDecimal Size
+
+var binaryFormatter = NewFormatter(FormatOptions{Base: Base2})
+var decimalFormatter = NewFormatter(FormatOptions{Base: Base10})
+
+// String returns the size formatted in binary units.
+func (sz SizeBinary) String() string {
+	return binaryFormatter.Format(Size(sz))
+}
+
+// MarshalText implements the encoding.TextMarshaler interface, formatting the size like String.
+// Returned error is always nil.
+func (sz SizeBinary) MarshalText() ([]byte, error) {
+	return binaryFormatter.AppendFormat(nil, Size(sz)), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface like Size.UnmarshalText.
+func (sz *SizeBinary) UnmarshalText(text []byte) error {
+	return (*Size)(sz).UnmarshalText(text)
+}
+
+// String returns the size formatted in decimal units.
+func (sz SizeDecimal) String() string {
+	return decimalFormatter.Format(Size(sz))
+}
+
+// MarshalText implements the encoding.TextMarshaler interface, formatting the size like String.
+// Returned error is always nil.
+func (sz SizeDecimal) MarshalText() ([]byte, error) {
+	return decimalFormatter.AppendFormat(nil, Size(sz)), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface like Size.UnmarshalText.
+func (sz *SizeDecimal) UnmarshalText(text []byte) error {
+	return (*Size)(sz).UnmarshalText(text)
+}
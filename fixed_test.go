@@ -0,0 +1,63 @@
+//go:build !bytez_minimal
+
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package bytez
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFixedSizes(t *testing.T) {
+	var tests = []struct {
+		in      uint64
+		binary  string
+		decimal string
+	}{
+		{0, "0", "0"},
+		{1000, "1000", "1kb"},
+		{1536, "1.5KiB", "1536"},
+		{3 * Gibibyte / 2, "1.5GiB", "1610612736"},
+		{3 * Gigabyte / 2, "1500000000", "1.5gb"},
+		{1280001, "1280001", "1280001"},
+	}
+
+	// The style does not depend on the default format.
+	prev := DefaultFormat()
+	t.Cleanup(func() { SetDefaultFormat(prev) })
+	SetDefaultFormat(FormatOptions{Precision: 1, Space: true})
+
+	for _, test := range tests {
+		out, err := json.Marshal(struct {
+			Binary  SizeBinary
+			Decimal SizeDecimal
+		}{SizeBinary(test.in), SizeDecimal(test.in)})
+		if testing.Verbose() {
+			fmt.Printf("%v --> %s\n", test.in, out)
+		}
+		require.NoError(t, err)
+		require.Equal(t, fmt.Sprintf(`{"Binary":%q,"Decimal":%q}`, test.binary, test.decimal),
+			string(out))
+		require.Equal(t, test.binary, SizeBinary(test.in).String())
+		require.Equal(t, test.decimal, SizeDecimal(test.in).String())
+
+		var back struct {
+			Binary  SizeBinary
+			Decimal SizeDecimal
+		}
+		require.NoError(t, json.Unmarshal(out, &back))
+		require.Equal(t, SizeBinary(test.in), back.Binary)
+		require.Equal(t, SizeDecimal(test.in), back.Decimal)
+	}
+
+	var sz SizeBinary
+	require.Error(t, sz.UnmarshalText([]byte("1.5GiBs")))
+}
@@ -0,0 +1,15 @@
+//go:build bytez_minimal
+
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package bytez
+
+// appendDefault appends size to dst as formatted by AsStr. The minimal build always uses the
+// default format.
+func appendDefault(dst []byte, size uint64) []byte {
+	return appendStr(dst, size)
+}
@@ -0,0 +1,805 @@
+//go:build !bytez_minimal
+
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package bytez
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/bits"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"unicode/utf8"
+)
+
+// Base selects the units used to format sizes.
+type Base int
+
+const (
+	// BaseAuto uses decimal units for multiples of 500 and binary units otherwise, as AsStr
+	// does by default.
+	BaseAuto Base = iota
+
+	// Base2 always uses binary (ISO/IEC) units, like "MiB".
+	Base2
+
+	// Base10 always uses decimal (SI) units, like "mb".
+	Base10
+)
+
+// UnitStyle selects how a Formatter writes units, as required by some programs and file formats.
+type UnitStyle int
+
+const (
+	// StyleDefault writes binary units like "GiB" and decimal units like "gb", as AsStr does and
+	// AsInt accepts. It is the zero value.
+	StyleDefault UnitStyle = iota
+
+	// StyleIEC writes binary units like "GiB" and decimal units with their SI symbols, like "kB"
+	// and "GB", as accepted by parsers with WithStrictUnits or the SIStrict profile.
+	StyleIEC
+
+	// StyleLetter writes units as single letters, like "4G", and StyleUpper and StyleLower as
+	// two, like "4GB" and "4gb", whether they are binary or decimal. AsInt accepts all of them
+	// but reads uppercase units as binary and lowercase ones as decimal, so Base should be set
+	// to the one the reader expects.
+	StyleLetter
+	StyleUpper
+	StyleLower
+)
+
+var (
+	namesSI     = []string{"", "kB", "MB", "GB", "TB", "PB", "EB"}
+	namesLetter = []string{"", "K", "M", "G", "T", "P", "E"}
+	namesUpper  = []string{"", "KB", "MB", "GB", "TB", "PB", "EB"}
+)
+
+// names returns the names of the units of the given base in the style, or nil for the default.
+func (s UnitStyle) names(base int) []string {
+	switch s {
+	case StyleIEC:
+		if base == 10 {
+			return namesSI
+		}
+	case StyleLetter:
+		return namesLetter
+	case StyleUpper:
+		return namesUpper
+	case StyleLower:
+		return unitsBase10
+	}
+	return nil
+}
+
+// FormatOptions controls how a Formatter formats sizes. The zero value formats sizes like AsStr
+// does by default.
+type FormatOptions struct {
+	// Base selects between binary and decimal units.
+	Base Base
+
+	// BinaryOnly and DecimalOnly override Base to guarantee that all sizes of at least one unit
+	// are formatted in binary or decimal units, like "1.2MiB" for 1280001, even when they are not
+	// a whole or half number of units. Such sizes are rounded to one decimal, unless Precision
+	// is set. DecimalOnly has no effect if BinaryOnly is set.
+	BinaryOnly  bool
+	DecimalOnly bool
+
+	// Precision is the maximum number of decimals. If zero, sizes are formatted exactly using
+	// the largest units in which they are a whole or half number, like "1.5MiB" or "1250kb".
+	// Otherwise they are rounded to the given number of decimals in the largest units that fit,
+	// like "1.22MiB", with trailing zeros removed unless KeepZeros is set, as in "1.20MiB". Note
+	// that AsInt parses such values as the rounded size, not the original one.
+	Precision int
+	KeepZeros bool
+
+	// Digits, if set, rounds sizes like Precision but to the given number of significant digits,
+	// like "1.23GiB", "12.3GiB", and "123GiB" for 3, which reads better across a wide range of
+	// sizes. Digits of the whole number are never removed, as in "1023KiB". It overrides Precision.
+	Digits int
+
+	// Rounding selects how sizes are rounded to Precision or Digits, or when BinaryOnly or
+	// DecimalOnly require it, like RoundCeil to never understate usage, as in "1.3MiB" rather
+	// than "1.2MiB" for 1280001. The zero value rounds to the nearest value, with halves rounded
+	// to even. Invalid modes round like the zero value.
+	Rounding RoundingMode
+
+	// Style selects how units are written, like "4G", "4GB", or "4gb" rather than "4GiB". It has
+	// no effect with Profile or Words.
+	Style UnitStyle
+
+	// Space puts a space between the number and the units, like "4 MiB".
+	Space bool
+
+	// Separator, if not empty, goes between the number and the units instead, whether Space is
+	// set or not, like a no-break space ("\u00a0") to keep them on the same line, or a thin
+	// space ("\u2009") as some style guides require.
+	Separator string
+
+	// Keywords formats zero as "none" and the size Unlimited as "unlimited", as accepted by
+	// parsers with WithKeywords. If Unlimited is zero, the Unlimited constant is used.
+	Keywords  bool
+	Unlimited Size
+
+	// Profile, if set, formats sizes in binary units accepted by parsers with the profile, like
+	// "1.5KB" with JEDEC or "1.5Gi" with Kubernetes, overriding Base.
+	Profile *Profile
+
+	// ByteSuffix appends "B" to sizes that would otherwise be formatted without units, like
+	// "999B" or "1280001 B", unless Profile does not accept it, as with Kubernetes and Nginx.
+	ByteSuffix bool
+
+	// ByteWord is like ByteSuffix but appends "bytes", or "byte" for exactly one, after a space
+	// or Separator, like "512 bytes", for sizes that sit next to ones with units in messages.
+	// With Profile, the word is written only if the profile accepts it, unlike JEDEC or Kubernetes.
+	ByteWord bool
+
+	// Width, if positive, pads sizes with spaces to at least Width columns, on the left to align
+	// them to the right, like "  1.5MiB" for 8, or on the right if AlignLeft is set, so that
+	// plain-text reports line up without padding the strings again.
+	Width     int
+	AlignLeft bool
+
+	// PadUnits pads units shorter than three characters with spaces, like "kb " or "Ki ", and
+	// sizes without units with as many, so that the numbers of sizes aligned to the right line up
+	// too, as in "  1.5MiB" and "  256kb ".
+	PadUnits bool
+
+	// Words writes units as words after a space, in the singular for exactly one unit, like
+	// "4 megabytes", "1 gibibyte", or "999 bytes", for messages to users. It has no effect with
+	// Profile.
+	Words bool
+}
+
+// A Formatter converts Sizes to human-friendly strings, like "4MiB". The zero value is ready to
+// use and formats sizes like AsStr does by default.
+//
+// Formatters are immutable and safe for concurrent use by multiple goroutines. AppendFormat does
+// not allocate memory if dst has enough capacity, and Format allocates only the returned string.
+type Formatter struct {
+	opts FormatOptions
+}
+
+// NewFormatter returns a Formatter that formats sizes according to opts.
+func NewFormatter(opts FormatOptions) *Formatter {
+	return &Formatter{opts: opts}
+}
+
+// Options returns the options of the Formatter.
+func (f *Formatter) Options() FormatOptions {
+	return f.opts
+}
+
+// Format returns size as a string. See AsStr for details.
+func (f *Formatter) Format(size Size) string {
+	var buf [32]byte
+	return string(f.AppendFormat(buf[:0], size))
+}
+
+// AppendFormat appends the formatted size to dst and returns the extended buffer.
+func (f *Formatter) AppendFormat(dst []byte, size Size) []byte {
+	start := len(dst)
+	dst = f.appendSize(dst, size)
+	if f.opts.PadUnits {
+		dst = padUnits(dst, start, 3+utf8.RuneCountInString(f.separator()))
+	}
+	if pad := f.opts.Width - utf8.RuneCount(dst[start:]); pad > 0 {
+		dst = appendSpaces(dst, pad)
+		if !f.opts.AlignLeft {
+			copy(dst[start+pad:], dst[start:len(dst)-pad])
+			for i := start; i < start+pad; i++ {
+				dst[i] = ' '
+			}
+		}
+	}
+	return dst
+}
+
+// separator returns what goes between the number and the units.
+func (f *Formatter) separator() string {
+	switch {
+	case f.opts.Separator != "":
+		return f.opts.Separator
+	case f.opts.Space || (f.opts.Words && f.opts.Profile == nil):
+		return " "
+	}
+	return ""
+}
+
+// appendSize appends the formatted size to dst without the padding required by the options.
+func (f *Formatter) appendSize(dst []byte, size Size) []byte {
+	if f.opts.Keywords {
+		if buf, ok := appendKeyword(dst, size, f.opts); ok {
+			return buf
+		}
+	}
+
+	var base int
+	var names []string
+	switch f.opts.Base {
+	case Base2:
+		base = 2
+	case Base10:
+		base = 10
+	}
+	if f.opts.BinaryOnly {
+		base = 2
+	} else if f.opts.DecimalOnly {
+		base = 10
+	}
+	if f.opts.Profile != nil {
+		base, names = 2, f.opts.Profile.names
+	}
+	sep := f.separator()
+	words := f.opts.Words && names == nil
+	if words {
+		if base == 0 {
+			base = 2
+			if size%500 == 0 {
+				base = 10
+			}
+		}
+		names = wordsBase2
+		if base == 10 {
+			names = wordsBase10
+		}
+	} else if names == nil && f.opts.Style != StyleDefault {
+		if base == 0 {
+			base = 2
+			if size%500 == 0 {
+				base = 10
+			}
+		}
+		names = f.opts.Style.names(base)
+	}
+
+	start := len(dst)
+	switch {
+	case f.opts.Precision > 0 || f.opts.Digits > 0:
+		if base == 0 {
+			base = 2
+			if size%500 == 0 {
+				base = 10
+			}
+		}
+		dst = appendRounded(dst, uint64(size), base, f.opts.Precision, f.opts.Digits,
+			f.opts.Rounding, !f.opts.KeepZeros, sep, names)
+	case f.opts.Profile != nil && f.opts.Profile.whole:
+		dst = appendWhole(dst, uint64(size), sep, names)
+	default:
+		dst = appendExact(dst, uint64(size), base, sep, names)
+		if (f.opts.BinaryOnly || f.opts.DecimalOnly) && isDigit(dst[len(dst)-1]) {
+			dst = appendRounded(dst[:start], uint64(size), base, 1, 0, f.opts.Rounding,
+				!f.opts.KeepZeros, sep, names)
+		}
+	}
+
+	if words {
+		return appendPlural(dst, start, sep)
+	}
+
+	// Sizes formatted without units end in a digit, and all units end in a letter.
+	if (f.opts.ByteSuffix || f.opts.ByteWord) && isDigit(dst[len(dst)-1]) {
+		unit := "B"
+		if f.opts.ByteWord {
+			unit = "bytes"
+		}
+		ok := true
+		if f.opts.Profile != nil {
+			_, ok = f.opts.Profile.lookup(unit)
+		}
+		switch {
+		case !ok:
+		case f.opts.ByteWord && sep == "":
+			dst = appendPlural(dst, start, " ")
+		case f.opts.ByteWord:
+			dst = appendPlural(dst, start, sep)
+		default:
+			dst = append(append(dst, sep...), 'B')
+		}
+	}
+	return dst
+}
+
+// padUnits appends spaces to the size that starts at dst[start] until its units, including the
+// separator before them, fill the given width. Keywords are left as is.
+func padUnits(dst []byte, start, width int) []byte {
+	num := numberEnd(dst, start)
+	if num == start {
+		return dst
+	}
+	return appendSpaces(dst, width-utf8.RuneCount(dst[num:]))
+}
+
+// numberEnd returns the index of the end of the number that starts at dst[start].
+func numberEnd(dst []byte, start int) int {
+	for start < len(dst) && (isDigit(dst[start]) || dst[start] == '.') {
+		start++
+	}
+	return start
+}
+
+// appendSpaces appends n spaces to dst, if n is positive.
+func appendSpaces(dst []byte, n int) []byte {
+	for ; n > 0; n-- {
+		dst = append(dst, ' ')
+	}
+	return dst
+}
+
+// appendPlural appends sep and "bytes" to the number that starts at dst[start] if it has no
+// units, and makes the units plural unless the number is 1.
+func appendPlural(dst []byte, start int, sep string) []byte {
+	if isDigit(dst[len(dst)-1]) {
+		dst = append(append(dst, sep...), "byte"...)
+	}
+	if string(dst[start:numberEnd(dst, start)]) != "1" {
+		dst = append(dst, 's')
+	}
+	return dst
+}
+
+// Format returns size formatted according to opts, like "1.37GiB" with a Precision of 2. Programs
+// that format many sizes with the same options can use a Formatter instead.
+func Format(size Size, opts FormatOptions) string {
+	f := Formatter{opts: opts}
+	return f.Format(size)
+}
+
+// FormatIn returns size in the given units with exactly prec decimals, like "1536.0MiB" for 1.5GiB
+// in "MiB" with 1 decimal, so that tables and metrics can show all sizes in the same units. The
+// units are any accepted by AsInt, with the same meaning, and are written as given. The value is
+// exact, rounded to the nearest last decimal with halves rounded to even.
+func FormatIn(size Size, units string, prec int) (string, error) {
+	unit, ok := lookupUnit(units)
+	if !ok {
+		return "", fmt.Errorf("unknown units %q", units)
+	} else if prec < 0 || prec > 15 {
+		return "", fmt.Errorf("invalid precision %d", prec)
+	}
+
+	var buf [48]byte
+	whole, frac := divRound(uint64(size), unit, prec, RoundHalfEven)
+	dst := strconv.AppendUint(buf[:0], whole, 10)
+	if prec > 0 {
+		// Append the decimals with their leading zeros as those of 1 followed by prec zeros plus
+		// frac, without the 1.
+		start := len(dst)
+		dst = strconv.AppendUint(dst, uint64(math.Pow10(prec))+frac, 10)
+		dst[start] = '.'
+	}
+	return string(append(dst, units...)), nil
+}
+
+// FormatExact returns the exact number of bytes in size with digit groups separated by commas,
+// like "1,234,567,890 bytes" or "1 byte", for audit logs and invoices, where sizes must not be
+// rounded but should still be readable. AsInt parses the result back to the same size.
+func FormatExact(size Size) string {
+	return FormatExactSep(size, ',')
+}
+
+// FormatExactSep is like FormatExact but separates digit groups with sep, like '.' or ' ', or
+// not at all if sep is 0. Parsers with WithGrouping(sep) parse the result back to the same size.
+func FormatExactSep(size Size, sep byte) string {
+	var buf [40]byte
+	digits := strconv.AppendUint(buf[:0], uint64(size), 10)
+
+	dst := make([]byte, 0, 2*len(digits)+len(" bytes"))
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 && sep != 0 {
+			dst = append(dst, sep)
+		}
+		dst = append(dst, d)
+	}
+	if size == 1 {
+		return string(append(dst, " byte"...))
+	}
+	return string(append(dst, " bytes"...))
+}
+
+// approxFormatter is the Formatter used by AsApproxStr.
+var approxFormatter = NewFormatter(FormatOptions{Precision: 1})
+
+// AsApproxStr accepts a number of bytes and returns it as a short, approximate string in the
+// largest units that fit, rounded to one decimal, like "292.6GiB" for 314159265359, where AsStr
+// would return the exact number of bytes. It is meant for displays like dashboards, where a short
+// value is more useful than an exact one. Multiples of 500 use decimal units and other sizes use
+// binary units. Unlike AsStr, it is not affected by SetDefaultFormat or BYTEZ_FORMAT.
+func AsApproxStr(size uint64) string {
+	return approxFormatter.Format(Size(size))
+}
+
+// Shorthand for AsApproxStr(uint64(sz)).
+func (sz Size) AsApproxStr() string {
+	return AsApproxStr(uint64(sz))
+}
+
+// Format implements the fmt.Formatter interface. The verbs %v and %s format the size like AsStr,
+// %q quotes it, and %#v formats it like GoString. A precision rounds the size like
+// FormatOptions.Precision, as in %.2v for "1.37GiB", and the space flag puts a space before the
+// units, as in "% v" for "1.5 KiB". Width and the minus flag pad the result as for strings. Other
+// verbs, like %d and %x, format the number of bytes as for integers.
+func (sz Size) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v', 's', 'q':
+		if verb == 'v' && s.Flag('#') {
+			io.WriteString(s, sz.GoString())
+			return
+		}
+	default:
+		fmt.Fprintf(s, fmt.FormatString(s, verb), uint64(sz))
+		return
+	}
+
+	var buf [32]byte
+	var dst []byte
+	prec, hasPrec := s.Precision()
+	if hasPrec || s.Flag(' ') {
+		f := Formatter{opts: FormatOptions{Precision: prec, Space: s.Flag(' ')}}
+		dst = f.AppendFormat(buf[:0], sz)
+	} else {
+		dst = appendDefault(buf[:0], uint64(sz))
+	}
+
+	directive := []byte{'%'}
+	if s.Flag('-') {
+		directive = append(directive, '-')
+	}
+	if width, ok := s.Width(); ok {
+		directive = strconv.AppendInt(directive, int64(width), 10)
+	}
+	if verb == 'q' {
+		directive = append(directive, 'q')
+	} else {
+		directive = append(directive, 's')
+	}
+	fmt.Fprintf(s, string(directive), dst)
+}
+
+// GoString implements the fmt.GoStringer interface, formatting the size as a Go expression using
+// the constants of this package, followed by the size as formatted by AsStr by default, like
+// "bytez.Size(4*bytez.Mebibyte) /* 4MiB */", so that %#v makes test failures and debug dumps
+// legible.
+func (sz Size) GoString() string {
+	// The size is written as a multiple of the unit giving the smallest whole multiplier.
+	mult, word := uint64(sz), ""
+	for i := 1; i < len(valuesBase10) && sz > 0; i++ {
+		if q := uint64(sz) / valuesBase10[i]; uint64(sz)%valuesBase10[i] == 0 && q < mult {
+			mult, word = q, wordsBase10[i]
+		}
+		if q := uint64(sz) / valuesBase2[i]; uint64(sz)%valuesBase2[i] == 0 && q < mult {
+			mult, word = q, wordsBase2[i]
+		}
+	}
+
+	dst := append(make([]byte, 0, 64), "bytez.Size("...)
+	if word == "" {
+		dst = strconv.AppendUint(dst, mult, 10)
+	} else {
+		if mult > 1 {
+			dst = append(strconv.AppendUint(dst, mult, 10), '*')
+		}
+		dst = append(append(dst, "bytez."...), word[0]-'a'+'A')
+		dst = append(dst, word[1:]...)
+	}
+	dst = append(dst, ')')
+
+	start := len(dst)
+	dst = appendStr(append(dst, " /* "...), uint64(sz))
+	if word == "" && string(dst[start+4:]) == strconv.FormatUint(uint64(sz), 10) {
+		return string(dst[:start])
+	}
+	return string(append(dst, " */"...))
+}
+
+// appendRounded appends size to dst in the largest units that fit, rounded to prec decimals, or
+// to digits significant digits if digits is positive, according to mode, with trailing zeros
+// removed if trim is set. If names is not nil, it replaces the names of the units of the base,
+// and may omit the largest ones.
+func appendRounded(dst []byte, size uint64, base, prec, digits int, mode RoundingMode, trim bool,
+	sep string, names []string) []byte {
+	values, units := valuesBase10, unitsBase10
+	if base == 2 {
+		values, units = valuesBase2, unitsBase2
+	}
+	if names != nil {
+		values, units = values[:len(names)], names
+	}
+
+	idx := len(values) - 1
+	for idx > 0 && size < values[idx] {
+		idx--
+	}
+	if idx == 0 {
+		return strconv.AppendUint(dst, size, 10)
+	}
+
+	if digits > 0 {
+		prec = decimals(size, values[idx], digits, mode)
+	}
+	val := roundUnits(size, values[idx], prec, mode)
+	if idx < len(values)-1 && val >= float64(values[1]) {
+		// Rounding reached the next unit, as in 1023.999KiB rounding to "1024.00KiB".
+		idx++
+		if digits > 0 {
+			prec = decimals(size, values[idx], digits, mode)
+		}
+		val = roundUnits(size, values[idx], prec, mode)
+	}
+
+	start := len(dst)
+	dst = strconv.AppendFloat(dst, val, 'f', prec, 64)
+	if trim {
+		dst = trimZeros(dst, start)
+	}
+	dst = append(dst, sep...)
+	return append(dst, units[idx]...)
+}
+
+// roundUnits returns size in the given units, rounded to prec decimals according to mode. The
+// rounding is exact, so that RoundCeil, for example, rounds up only sizes that are not already a
+// whole number of the last decimal.
+func roundUnits(size, unit uint64, prec int, mode RoundingMode) float64 {
+	if prec > 19 {
+		// More decimals than fit in a uint64, and far more than a float64 represents.
+		return float64(size) / float64(unit)
+	}
+	whole, frac := divRound(size, unit, prec, mode)
+	return float64(whole) + float64(frac)/math.Pow10(prec)
+}
+
+// divRound returns size divided by unit, rounded to prec decimals according to mode, as the
+// whole part and the decimals taken as a whole number, like 1 and 25 for 1.25 with 2 decimals.
+// prec must not exceed 19, the most decimals that fit in a uint64.
+func divRound(size, unit uint64, prec int, mode RoundingMode) (whole, frac uint64) {
+	scale := uint64(1)
+	for i := 0; i < prec; i++ {
+		scale *= 10
+	}
+
+	// The remainder is less than unit, so its decimals are less than scale.
+	whole, rem := size/unit, size%unit
+	hi, lo := bits.Mul64(rem, scale)
+	frac, rem = bits.Div64(hi, lo, unit)
+
+	odd := frac%2 == 1
+	if prec == 0 {
+		odd = whole%2 == 1
+	}
+	var up bool
+	switch mode {
+	case RoundFloor:
+	case RoundCeil:
+		up = rem != 0
+	case RoundHalfUp:
+		up = rem != 0 && rem >= unit-rem
+	default:
+		up = rem > unit-rem || (rem != 0 && rem == unit-rem && odd)
+	}
+	if up {
+		if frac++; frac == scale {
+			whole, frac = whole+1, 0
+		}
+	}
+	return whole, frac
+}
+
+// decimals returns the number of decimals that round size in the given units to the given number
+// of significant digits according to mode, or 0 if its whole number has as many digits.
+func decimals(size, unit uint64, digits int, mode RoundingMode) int {
+	whole := 1
+	for val := size / unit; val >= 10; val /= 10 {
+		whole++
+	}
+	prec := max(digits-whole, 0)
+	if prec > 0 && roundUnits(size, unit, prec, mode) >= math.Pow10(digits-prec) {
+		// Rounding added a digit to the whole number, as in 9.996 rounding to "10.00".
+		prec--
+	}
+	return prec
+}
+
+// trimZeros removes the trailing zeros of the decimal number that starts at dst[start], and its
+// decimal point if no decimals remain.
+func trimZeros(dst []byte, start int) []byte {
+	if bytes.IndexByte(dst[start:], '.') < 0 {
+		return dst
+	}
+	for dst[len(dst)-1] == '0' {
+		dst = dst[:len(dst)-1]
+	}
+	if dst[len(dst)-1] == '.' {
+		dst = dst[:len(dst)-1]
+	}
+	return dst
+}
+
+// defaultFormatter is the Formatter used by AsStr.
+var defaultFormatter atomic.Pointer[Formatter]
+
+// appendDefault appends size to dst as formatted by AsStr.
+func appendDefault(dst []byte, size uint64) []byte {
+	if f := defaultFormatter.Load(); f != nil {
+		return f.AppendFormat(dst, Size(size))
+	}
+	return appendStr(dst, size)
+}
+
+// ConfigureFromEnv sets the format used by AsStr and Size.String from the BYTEZ_FORMAT
+// environment variable, so that operators can change how a program displays sizes without
+// recompiling it. The variable is only read when a program calls ConfigureFromEnv, usually at
+// startup, so that programs whose output must be reproducible, like code generators, are not
+// affected by the environment. If it is empty or unset, the default format is used. If it is not
+// valid, an error is returned and the format is not changed.
+//
+// The variable holds a comma-separated list of the following settings, in the syntax of
+// ParseFormatOptions:
+//
+//	auto            units depending on the size, as by default
+//	iec or binary   binary units, like "MiB"
+//	si or decimal   decimal units, like "mb"
+//	iec-only        binary units even for sizes rounded to them, also "binary-only"
+//	si-only         decimal units even for sizes rounded to them, also "decimal-only"
+//	prec=N          round to at most N decimals
+//	digits=N        round to N significant digits
+//	round=MODE      round by MODE: half-even, half-up, floor, or ceil
+//	words           units as words, like "4 megabytes"
+//	style=STYLE     units in STYLE: default, iec, letter, upper, or lower
+//	width=N         pad sizes to N columns, aligned to the right
+//	left            align padded sizes to the left
+//	pad-units       pad units to three letters, like "kb "
+//	zeros           keep trailing zeros of rounded sizes, like "1.20MiB"
+//	space           put a space between the number and the units
+//	nbsp            put a no-break space between the number and the units
+//	nospace         put nothing between the number and the units
+//	bytes           put "B" after sizes without other units, like "999B"
+//	byte-word       put "bytes" after sizes without other units, like "999 bytes"
+//	profile=NAME    units of the profile registered in Profiles, like "jedec"
+//
+// For example, BYTEZ_FORMAT="iec,prec=2,space" formats 1280000 as "1.22 MiB".
+func ConfigureFromEnv() error {
+	opts, err := ParseFormatOptions(os.Getenv("BYTEZ_FORMAT"))
+	if err != nil {
+		return fmt.Errorf("BYTEZ_FORMAT: %v", err)
+	}
+
+	SetDefaultFormat(opts)
+	return nil
+}
+
+// SetDefaultFormat sets the format used by AsStr, AppendStr, and Size.String for the whole
+// program, replacing the one read from BYTEZ_FORMAT, so that an application can choose how every
+// size it displays is formatted. The zero FormatOptions restores the default format. It is safe
+// to call while sizes are being formatted.
+//
+// The default format does not affect MarshalText, which always formats sizes exactly so that
+// they are read back as the same size, since options like Keywords, Precision, or Style may
+// produce text that UnmarshalText rejects or reads as a different size.
+func SetDefaultFormat(opts FormatOptions) {
+	if opts == (FormatOptions{}) {
+		defaultFormatter.Store(nil)
+	} else {
+		defaultFormatter.Store(NewFormatter(opts))
+	}
+}
+
+// DefaultFormat returns the options of the format used by AsStr, as set by SetDefaultFormat or
+// BYTEZ_FORMAT.
+func DefaultFormat() FormatOptions {
+	if f := defaultFormatter.Load(); f != nil {
+		return f.opts
+	}
+	return FormatOptions{}
+}
+
+// ParseFormatOptions parses format options written as a comma-separated list of settings, like
+// "iec,prec=2,space". See ConfigureFromEnv for the settings. Settings are case insensitive and
+// later settings override earlier ones.
+func ParseFormatOptions(spec string) (FormatOptions, error) {
+	var opts FormatOptions
+	if strings.TrimSpace(spec) == "" {
+		return opts, nil
+	}
+
+	for _, setting := range strings.Split(spec, ",") {
+		setting = strings.ToLower(strings.TrimSpace(setting))
+		name, value, hasValue := strings.Cut(setting, "=")
+
+		switch {
+		case name == "auto" && !hasValue:
+			opts.Base = BaseAuto
+		case (name == "iec" || name == "binary") && !hasValue:
+			opts.Base = Base2
+		case (name == "si" || name == "decimal") && !hasValue:
+			opts.Base = Base10
+		case (name == "iec-only" || name == "binary-only") && !hasValue:
+			opts.BinaryOnly, opts.DecimalOnly = true, false
+		case (name == "si-only" || name == "decimal-only") && !hasValue:
+			opts.BinaryOnly, opts.DecimalOnly = false, true
+		case name == "space" && !hasValue:
+			opts.Space = true
+		case name == "nospace" && !hasValue:
+			opts.Space, opts.Separator = false, ""
+		case name == "nbsp" && !hasValue:
+			opts.Separator = "\u00a0"
+		case name == "bytes" && !hasValue:
+			opts.ByteSuffix = true
+		case name == "byte-word" && !hasValue:
+			opts.ByteWord = true
+		case name == "words" && !hasValue:
+			opts.Words = true
+		case name == "left" && !hasValue:
+			opts.AlignLeft = true
+		case name == "pad-units" && !hasValue:
+			opts.PadUnits = true
+		case name == "width":
+			width, err := strconv.Atoi(value)
+			if err != nil || width < 0 {
+				return FormatOptions{}, fmt.Errorf("invalid width %q", value)
+			}
+			opts.Width = width
+		case name == "zeros" && !hasValue:
+			opts.KeepZeros = true
+		case name == "profile":
+			profile, err := Profiles.Get(value)
+			if err != nil {
+				return FormatOptions{}, err
+			}
+			opts.Profile = profile
+		case name == "prec" || name == "precision":
+			prec, err := strconv.Atoi(value)
+			if err != nil || prec < 0 || prec > 15 {
+				return FormatOptions{}, fmt.Errorf("invalid precision %q", value)
+			}
+			opts.Precision = prec
+		case name == "digits":
+			digits, err := strconv.Atoi(value)
+			if err != nil || digits < 1 || digits > 15 {
+				return FormatOptions{}, fmt.Errorf("invalid digits %q", value)
+			}
+			opts.Digits = digits
+		case name == "style":
+			switch strings.ToLower(value) {
+			case "default":
+				opts.Style = StyleDefault
+			case "iec":
+				opts.Style = StyleIEC
+			case "letter":
+				opts.Style = StyleLetter
+			case "upper":
+				opts.Style = StyleUpper
+			case "lower":
+				opts.Style = StyleLower
+			default:
+				return FormatOptions{}, fmt.Errorf("invalid style %q", value)
+			}
+		case name == "round":
+			switch strings.ToLower(value) {
+			case "half-even":
+				opts.Rounding = RoundHalfEven
+			case "half-up":
+				opts.Rounding = RoundHalfUp
+			case "floor":
+				opts.Rounding = RoundFloor
+			case "ceil":
+				opts.Rounding = RoundCeil
+			default:
+				return FormatOptions{}, fmt.Errorf("invalid rounding mode %q", value)
+			}
+		case setting == "":
+			return FormatOptions{}, errors.New("empty setting")
+		default:
+			return FormatOptions{}, fmt.Errorf("unknown setting %q", setting)
+		}
+	}
+	return opts, nil
+}
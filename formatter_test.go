@@ -0,0 +1,575 @@
+//go:build !bytez_minimal
+
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package bytez
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatter(t *testing.T) {
+	var f Formatter
+
+	for _, size := range []uint64{0, 500, 1000, 1536, 3670016, 5905580032, 314159265359, 1<<64 - 1} {
+		require.Equal(t, AsStr(size), f.Format(Size(size)))
+		require.Equal(t, "x:"+AsStr(size), string(f.AppendFormat([]byte("x:"), Size(size))))
+	}
+}
+
+func TestFormatOptions(t *testing.T) {
+	var tests = []struct {
+		opts FormatOptions
+		in   uint64
+		out  string
+	}{
+		{FormatOptions{}, 1536, "1.5KiB"},
+		{FormatOptions{Space: true}, 1536, "1.5 KiB"},
+		{FormatOptions{Space: true}, 999, "999"},
+		{FormatOptions{Base: Base2}, 1000, "1000"},
+		{FormatOptions{Base: Base2}, 2000384, "1953.5KiB"},
+		{FormatOptions{Base: Base2}, 1280000, "1250KiB"},
+		{FormatOptions{Base: Base2}, 1280001, "1280001"},
+		{FormatOptions{BinaryOnly: true}, 1280001, "1.2MiB"},
+		{FormatOptions{BinaryOnly: true}, 1000, "1000"},
+		{FormatOptions{BinaryOnly: true}, 1500, "1.5KiB"},
+		{FormatOptions{BinaryOnly: true}, 5000000000, "4882812.5KiB"},
+		{FormatOptions{BinaryOnly: true}, 5000000001, "4.7GiB"},
+		{FormatOptions{BinaryOnly: true}, 1536, "1.5KiB"},
+		{FormatOptions{BinaryOnly: true, Base: Base10}, 2000, "2KiB"},
+		{FormatOptions{BinaryOnly: true, DecimalOnly: true}, 2000, "2KiB"},
+		{FormatOptions{BinaryOnly: true, Precision: 3}, 1280001, "1.221MiB"},
+		{FormatOptions{BinaryOnly: true, KeepZeros: true}, 1048577, "1.0MiB"},
+		{FormatOptions{DecimalOnly: true}, 1536, "1.5kb"},
+		{FormatOptions{DecimalOnly: true}, 1048576, "1mb"},
+		{FormatOptions{DecimalOnly: true, Space: true}, 5905580032, "5.9 gb"},
+		{FormatOptions{DecimalOnly: true}, 999, "999"},
+		{FormatOptions{Base: Base10}, 1536, "1536"},
+		{FormatOptions{Base: Base10}, 1048576, "1048576"},
+		{FormatOptions{Base: Base10, Space: true}, 3500000, "3.5 mb"},
+		{FormatOptions{Precision: 2}, 1280000, "1.28mb"},
+		{FormatOptions{Precision: 2}, 1280001, "1.22MiB"},
+		{FormatOptions{Precision: 2}, 999, "999"},
+		{FormatOptions{Base: Base2, Precision: 2, Space: true}, 1280000, "1.22 MiB"},
+		{FormatOptions{Base: Base2, Precision: 2}, 1536, "1.5KiB"},
+		{FormatOptions{Base: Base2, Precision: 2}, 1048575, "1MiB"},
+		{FormatOptions{Base: Base2, Precision: 3}, 1048575, "1023.999KiB"},
+		{FormatOptions{Base: Base10, Precision: 1}, 314159265359, "314.2gb"},
+		{FormatOptions{Base: Base2, Precision: 1}, 314159265359, "292.6GiB"},
+		{FormatOptions{Base: Base2, Precision: 2}, 1<<64 - 1, "16EiB"},
+		{FormatOptions{Precision: 2}, 1470000000, "1.47gb"},
+		{FormatOptions{Precision: 2}, 1470000001, "1.37GiB"},
+		{FormatOptions{Base: Base2, Precision: 2, KeepZeros: true}, 1536, "1.50KiB"},
+		{FormatOptions{Base: Base2, Precision: 2, KeepZeros: true}, 1048575, "1.00MiB"},
+		{FormatOptions{Base: Base2, Precision: 1, KeepZeros: true}, 1<<64 - 1, "16.0EiB"},
+		{FormatOptions{Precision: 2, KeepZeros: true}, 999, "999"},
+		{FormatOptions{KeepZeros: true}, 1536, "1.5KiB"},
+		{FormatOptions{Digits: 3}, 1320702444, "1.23GiB"},
+		{FormatOptions{Digits: 3}, 13207024435, "12.3GiB"},
+		{FormatOptions{Digits: 3}, 132070244352, "123GiB"},
+		{FormatOptions{Digits: 3}, 1048063, "1023KiB"},
+		{FormatOptions{Digits: 3}, 1048575, "1MiB"},
+		{FormatOptions{Digits: 3, KeepZeros: true}, 1048575, "1.00MiB"},
+		{FormatOptions{Digits: 3, KeepZeros: true}, 10481566, "10.0MiB"},
+		{FormatOptions{Digits: 3, KeepZeros: true}, 10474185, "9.99MiB"},
+		{FormatOptions{Digits: 2, Precision: 5}, 1280001, "1.2MiB"},
+		{FormatOptions{Digits: 1}, 1280000, "1mb"},
+		{FormatOptions{Digits: 3}, 999, "999"},
+		{FormatOptions{Precision: 1}, 1258291, "1.2MiB"},
+		{FormatOptions{Precision: 1}, 1363149, "1.3MiB"},
+		{FormatOptions{Precision: 1}, 1153434, "1.1MiB"},
+		{FormatOptions{Precision: 1, Rounding: RoundHalfUp}, 1258291, "1.2MiB"},
+		{FormatOptions{Precision: 1, Rounding: RoundHalfUp}, 1258290, "1.2MiB"},
+		{FormatOptions{Precision: 1, Rounding: RoundCeil}, 1153434, "1.2MiB"},
+		{FormatOptions{Precision: 1, Rounding: RoundCeil}, 1153433, "1.1MiB"},
+		{FormatOptions{Precision: 1, Rounding: RoundFloor}, 1258291, "1.1MiB"},
+		{FormatOptions{Precision: 1, Rounding: RoundFloor}, 1363148, "1.2MiB"},
+		{FormatOptions{Precision: 2, Rounding: RoundCeil}, 1310720, "1.25MiB"},
+		{FormatOptions{Precision: 1}, 1310720, "1.2MiB"},
+		{FormatOptions{Precision: 1, Rounding: RoundHalfUp}, 1310720, "1.3MiB"},
+		{FormatOptions{Precision: 1, Base: Base10}, 1250000, "1.2mb"},
+		{FormatOptions{Precision: 1, Base: Base10, Rounding: RoundHalfUp}, 1250000, "1.3mb"},
+		{FormatOptions{Precision: 1, Base: Base10, Rounding: RoundCeil}, 1200000, "1.2mb"},
+		{FormatOptions{Precision: 1, Base: Base10, Rounding: RoundCeil}, 1200001, "1.3mb"},
+		{FormatOptions{Precision: 1, Rounding: RoundCeil}, 1048575, "1MiB"},
+		{FormatOptions{Precision: 1, Rounding: RoundFloor}, 1048575, "1023.9KiB"},
+		{FormatOptions{Digits: 3, Rounding: RoundCeil}, 10480517, "10MiB"},
+		{FormatOptions{Digits: 3, Rounding: RoundFloor}, 10481566, "9.99MiB"},
+		{FormatOptions{BinaryOnly: true, Rounding: RoundCeil}, 1280001, "1.3MiB"},
+		{FormatOptions{BinaryOnly: true, Rounding: RoundFloor}, 1363147, "1.2MiB"},
+		{FormatOptions{Precision: 1, Rounding: RoundingMode(42)}, 1363149, "1.3MiB"},
+		{FormatOptions{Width: 8}, 1536 * Kibibyte, "  1.5MiB"},
+		{FormatOptions{Width: 8, AlignLeft: true}, 1536 * Kibibyte, "1.5MiB  "},
+		{FormatOptions{Width: 4}, 1536 * Kibibyte, "1.5MiB"},
+		{FormatOptions{Width: 8, PadUnits: true}, 256 * Kilobyte, "  256kb "},
+		{FormatOptions{Width: 8, PadUnits: true}, 999, "  999   "},
+		{FormatOptions{Width: 8, PadUnits: true, Space: true}, 999, " 999    "},
+		{FormatOptions{Width: 8, PadUnits: true, Space: true}, 4 * Megabyte, "   4 mb "},
+		{FormatOptions{PadUnits: true, Space: true}, 4 * Mebibyte, "4 MiB"},
+		{FormatOptions{PadUnits: true, Profile: Kubernetes}, 4 * Mebibyte, "4Mi "},
+		{FormatOptions{PadUnits: true, ByteSuffix: true}, 999, "999B  "},
+		{FormatOptions{PadUnits: true, Words: true}, 999, "999 bytes"},
+		{FormatOptions{PadUnits: true, Keywords: true}, 0, "none"},
+		{FormatOptions{Width: 6, Keywords: true}, 0, "  none"},
+		{FormatOptions{Width: 6, Precision: 1, PadUnits: true}, 1280001, "1.2MiB"},
+		{FormatOptions{Style: StyleLetter}, 4 * Gibibyte, "4G"},
+		{FormatOptions{Style: StyleLetter}, 4 * Gigabyte, "4G"},
+		{FormatOptions{Style: StyleUpper}, 4 * Gibibyte, "4GB"},
+		{FormatOptions{Style: StyleLower}, 4 * Gibibyte, "4gb"},
+		{FormatOptions{Style: StyleIEC, Space: true}, 4 * Gibibyte, "4 GiB"},
+		{FormatOptions{Style: StyleIEC}, 1500 * Kilobyte, "1.5MB"},
+		{FormatOptions{Style: StyleIEC}, 500, "500"},
+		{FormatOptions{Style: StyleIEC}, 1500, "1.5kB"},
+		{FormatOptions{Style: StyleDefault}, 1500, "1.5kb"},
+		{FormatOptions{Style: StyleLetter}, 1280001, "1280001"},
+		{FormatOptions{Style: StyleLetter, Precision: 2}, 1280001, "1.22M"},
+		{FormatOptions{Style: StyleUpper, Base: Base10}, 3 * Terabyte, "3TB"},
+		{FormatOptions{Style: StyleLower, BinaryOnly: true}, 1280001, "1.2mb"},
+		{FormatOptions{Style: StyleLetter, Profile: JEDEC}, 4 * Gibibyte, "4GB"},
+		{FormatOptions{Style: StyleLetter, Words: true}, 4 * Gibibyte, "4 gibibytes"},
+		{FormatOptions{Style: StyleLetter, ByteSuffix: true}, 999, "999B"},
+		{FormatOptions{Style: UnitStyle(42)}, 4 * Gibibyte, "4GiB"},
+		{FormatOptions{Separator: "\u00a0"}, 4 * Gibibyte, "4\u00a0GiB"},
+		{FormatOptions{Separator: "\u2009", Space: true}, 1500, "1.5\u2009kb"},
+		{FormatOptions{Separator: "_", Precision: 2}, 1280001, "1.22_MiB"},
+		{FormatOptions{Separator: "\u00a0"}, 999, "999"},
+		{FormatOptions{Separator: "\u00a0", ByteSuffix: true}, 999, "999\u00a0B"},
+		{FormatOptions{Separator: "\u00a0", Words: true}, 1, "1\u00a0byte"},
+		{FormatOptions{Separator: "\u00a0", Words: true}, 4 * Megabyte, "4\u00a0megabytes"},
+		{FormatOptions{Separator: "\u00a0", Profile: Kubernetes}, 4 * Gibibyte, "4\u00a0Gi"},
+		{FormatOptions{Separator: "\u00a0", Width: 8}, 4 * Gibibyte, "   4\u00a0GiB"},
+		{FormatOptions{Separator: "\u00a0", Width: 8, PadUnits: true}, 999, " 999    "},
+		{FormatOptions{Words: true, Width: 12}, 1, "      1 byte"},
+		{FormatOptions{ByteWord: true}, 512, "512 bytes"},
+		{FormatOptions{ByteWord: true}, 1, "1 byte"},
+		{FormatOptions{ByteWord: true}, 0, "0 bytes"},
+		{FormatOptions{ByteWord: true}, 512 * Mebibyte, "512MiB"},
+		{FormatOptions{ByteWord: true}, 1280001, "1280001 bytes"},
+		{FormatOptions{ByteWord: true, ByteSuffix: true}, 512, "512 bytes"},
+		{FormatOptions{ByteWord: true, Space: true}, 512, "512 bytes"},
+		{FormatOptions{ByteWord: true, Separator: "\u00a0"}, 512, "512\u00a0bytes"},
+		{FormatOptions{ByteWord: true, Profile: Default}, 512, "512 bytes"},
+		{FormatOptions{ByteWord: true, Profile: JEDEC}, 512, "512"},
+		{FormatOptions{ByteWord: true, Profile: Kubernetes}, 512, "512"},
+		{FormatOptions{ByteWord: true, Keywords: true}, 0, "none"},
+		{FormatOptions{Words: true}, 4 * Megabyte, "4 megabytes"},
+		{FormatOptions{Words: true}, Gibibyte, "1 gibibyte"},
+		{FormatOptions{Words: true}, 3 * Terabyte / 2, "1.5 terabytes"},
+		{FormatOptions{Words: true}, 1536, "1.5 kibibytes"},
+		{FormatOptions{Words: true}, 999, "999 bytes"},
+		{FormatOptions{Words: true}, 1, "1 byte"},
+		{FormatOptions{Words: true}, 0, "0 bytes"},
+		{FormatOptions{Words: true, Base: Base10}, 1024, "1024 bytes"},
+		{FormatOptions{Words: true, Precision: 2}, 1280001, "1.22 mebibytes"},
+		{FormatOptions{Words: true, Precision: 2}, 1048575, "1 mebibyte"},
+		{FormatOptions{Words: true, DecimalOnly: true}, 1048576, "1 megabyte"},
+		{FormatOptions{Words: true, ByteSuffix: true}, 999, "999 bytes"},
+		{FormatOptions{Words: true, Profile: JEDEC}, 1536, "1.5KB"},
+		{FormatOptions{ByteSuffix: true}, 999, "999B"},
+		{FormatOptions{ByteSuffix: true}, 0, "0B"},
+		{FormatOptions{ByteSuffix: true}, 1536, "1.5KiB"},
+		{FormatOptions{ByteSuffix: true, Space: true}, 1280001, "1280001 B"},
+		{FormatOptions{ByteSuffix: true, Precision: 2}, 999, "999B"},
+		{FormatOptions{ByteSuffix: true, Profile: JEDEC}, 1023, "1023B"},
+		{FormatOptions{ByteSuffix: true, Profile: Docker}, 1023, "1023B"},
+		{FormatOptions{ByteSuffix: true, Profile: Kubernetes}, 1023, "1023"},
+		{FormatOptions{ByteSuffix: true, Profile: Nginx}, 1023, "1023"},
+	}
+
+	for _, test := range tests {
+		out := NewFormatter(test.opts).Format(Size(test.in))
+		if testing.Verbose() {
+			fmt.Printf("%+v %v --> %v\n", test.opts, test.in, out)
+		}
+		require.Equal(t, test.out, out)
+		require.Equal(t, test.out, Format(Size(test.in), test.opts))
+	}
+}
+
+func TestAsApproxStr(t *testing.T) {
+	var tests = []struct {
+		in  uint64
+		out string
+	}{
+		{0, "0"},
+		{999, "999"},
+		{1000, "1kb"},
+		{1023, "1023"},
+		{1536, "1.5KiB"},
+		{4 * Mebibyte, "4MiB"},
+		{1250000, "1.2mb"},
+		{1049600, "1MiB"},
+		{314159265359, "292.6GiB"},
+		{1<<64 - 1, "16EiB"},
+	}
+
+	for _, test := range tests {
+		out := AsApproxStr(test.in)
+		if testing.Verbose() {
+			fmt.Printf("%v --> %v\n", test.in, out)
+		}
+		require.Equal(t, test.out, out)
+		require.Equal(t, test.out, Size(test.in).AsApproxStr())
+	}
+
+	// The approximation is not affected by BYTEZ_FORMAT.
+	t.Setenv("BYTEZ_FORMAT", "si,space")
+	require.NoError(t, ConfigureFromEnv())
+	require.Equal(t, "292.6GiB", AsApproxStr(314159265359))
+
+	t.Setenv("BYTEZ_FORMAT", "")
+	require.NoError(t, ConfigureFromEnv())
+}
+
+func TestSizeFormat(t *testing.T) {
+	var tests = []struct {
+		format string
+		in     uint64
+		out    string
+	}{
+		{"%v", 1536, "1.5KiB"},
+		{"%s", 4 * Mebibyte, "4MiB"},
+		{"%q", 1000, `"1kb"`},
+		{"%d", 1536, "1536"},
+		{"%x", 4096, "1000"},
+		{"%#x", 4096, "0x1000"},
+		{"%08d", 1536, "00001536"},
+		{"%.2v", 1470000001, "1.37GiB"},
+		{"%.1s", 314159265359, "292.6GiB"},
+		{"% v", 1536, "1.5 KiB"},
+		{"% .2v", 1280001, "1.22 MiB"},
+		{"%8v|", 1536, "  1.5KiB|"},
+		{"%-8v|", 1536, "1.5KiB  |"},
+		{"%v", 0, "0"},
+		{"%#v", 4 * Mebibyte, "bytez.Size(4*bytez.Mebibyte) /* 4MiB */"},
+	}
+
+	for _, test := range tests {
+		out := fmt.Sprintf(test.format, Size(test.in))
+		if testing.Verbose() {
+			fmt.Printf("%s %d --> %s\n", test.format, test.in, out)
+		}
+		require.Equal(t, test.out, out, test.format)
+	}
+
+	require.Equal(t, "[1.5KiB 4MiB]", fmt.Sprint([]Size{1536, Size(4 * Mebibyte)}))
+}
+
+func TestGoString(t *testing.T) {
+	var tests = []struct {
+		in  uint64
+		out string
+	}{
+		{0, "bytez.Size(0)"},
+		{1234, "bytez.Size(1234)"},
+		{1500, "bytez.Size(1500) /* 1.5kb */"},
+		{1024, "bytez.Size(bytez.Kibibyte) /* 1KiB */"},
+		{4 * Mebibyte, "bytez.Size(4*bytez.Mebibyte) /* 4MiB */"},
+		{3 * Gibibyte / 2, "bytez.Size(1536*bytez.Mebibyte) /* 1.5GiB */"},
+		{5 * Gigabyte, "bytez.Size(5*bytez.Gigabyte) /* 5gb */"},
+		{4096000, "bytez.Size(4000*bytez.Kibibyte) /* 4096kb */"},
+		{1<<64 - 1, "bytez.Size(18446744073709551615)"},
+	}
+
+	for _, test := range tests {
+		out := Size(test.in).GoString()
+		if testing.Verbose() {
+			fmt.Printf("%v --> %v\n", test.in, out)
+		}
+		require.Equal(t, test.out, out)
+	}
+
+	type conf struct {
+		Cache Size
+	}
+	require.Equal(t, "bytez.conf{Cache:bytez.Size(bytez.Gibibyte) /* 1GiB */}",
+		fmt.Sprintf("%#v", conf{Cache: Size(Gibibyte)}))
+}
+
+func TestFormatExact(t *testing.T) {
+	var tests = []struct {
+		in  uint64
+		sep byte
+		out string
+	}{
+		{1234567890, ',', "1,234,567,890 bytes"},
+		{1234567890, '.', "1.234.567.890 bytes"},
+		{1234567890, '\'', "1'234'567'890 bytes"},
+		{1234567890, 0, "1234567890 bytes"},
+		{123456, ',', "123,456 bytes"},
+		{999, ',', "999 bytes"},
+		{1000, ',', "1,000 bytes"},
+		{1, ',', "1 byte"},
+		{0, ',', "0 bytes"},
+		{1<<64 - 1, ',', "18,446,744,073,709,551,615 bytes"},
+	}
+
+	for _, test := range tests {
+		out := FormatExactSep(Size(test.in), test.sep)
+		if testing.Verbose() {
+			fmt.Printf("%v %q --> %v\n", test.in, test.sep, out)
+		}
+		require.Equal(t, test.out, out)
+
+		opts := []Option{}
+		if test.sep != 0 {
+			opts = append(opts, WithGrouping(test.sep))
+		}
+		if test.sep == '.' {
+			opts = append(opts, WithDecimalComma())
+		}
+		back, err := ParseSize(out, opts...)
+		require.NoError(t, err, out)
+		require.Equal(t, test.in, uint64(back), out)
+	}
+
+	require.Equal(t, "4,194,304 bytes", FormatExact(Size(4*Mebibyte)))
+}
+
+func TestFormatIn(t *testing.T) {
+	var tests = []struct {
+		in    uint64
+		units string
+		prec  int
+		out   string
+	}{
+		{3 * Gibibyte / 2, "MiB", 1, "1536.0MiB"},
+		{3 * Gibibyte / 2, "GiB", 2, "1.50GiB"},
+		{3 * Gibibyte / 2, "gb", 3, "1.611gb"},
+		{512 * Kibibyte, "MiB", 0, "0MiB"},
+		{512 * Kibibyte, "MB", 2, "0.50MB"},
+		{1 << 20, "kilobytes", 1, "1048.6kilobytes"},
+		{1000, "B", 0, "1000B"},
+		{0, "TiB", 1, "0.0TiB"},
+		{100 * Megabyte, "Mbit", 0, "800Mbit"},
+		{1<<64 - 1, "B", 0, "18446744073709551615B"},
+		{1<<64 - 1, "B", 3, "18446744073709551615.000B"},
+		{1<<64 - 1, "KiB", 15, "18014398509481983.999023437500000KiB"},
+		{1<<53 + 1, "B", 1, "9007199254740993.0B"},
+		{1536, "KiB", 0, "2KiB"},
+		{2560, "KiB", 0, "2KiB"},
+		{2560, "KiB", 1, "2.5KiB"},
+		{1, "kb", 15, "0.001000000000000kb"},
+		{1, "EiB", 15, "0.000000000000000EiB"},
+		{1<<60 - 1, "EiB", 2, "1.00EiB"},
+	}
+
+	for _, test := range tests {
+		out, err := FormatIn(Size(test.in), test.units, test.prec)
+		if testing.Verbose() {
+			fmt.Printf("%v %s %d --> %v\n", test.in, test.units, test.prec, out)
+		}
+		require.NoError(t, err)
+		require.Equal(t, test.out, out)
+	}
+
+	_, err := FormatIn(1, "MiBs", 1)
+	require.EqualError(t, err, `unknown units "MiBs"`)
+	_, err = FormatIn(1, "MiB", -1)
+	require.EqualError(t, err, "invalid precision -1")
+}
+
+func TestParseFormatOptions(t *testing.T) {
+	var positive = []struct {
+		in  string
+		out FormatOptions
+	}{
+		{"", FormatOptions{}},
+		{"auto", FormatOptions{}},
+		{"iec,prec=2,space", FormatOptions{Base: Base2, Precision: 2, Space: true}},
+		{" SI , Precision=1 ", FormatOptions{Base: Base10, Precision: 1}},
+		{"binary,space,nospace,decimal", FormatOptions{Base: Base10}},
+		{"si,bytes", FormatOptions{Base: Base10, ByteSuffix: true}},
+		{"prec=2,zeros", FormatOptions{Precision: 2, KeepZeros: true}},
+		{"iec-only", FormatOptions{BinaryOnly: true}},
+		{"words", FormatOptions{Words: true}},
+		{"style=letter", FormatOptions{Style: StyleLetter}},
+		{"nbsp", FormatOptions{Separator: "\u00a0"}},
+		{"byte-word", FormatOptions{ByteWord: true}},
+		{"nbsp,nospace", FormatOptions{}},
+		{"style=IEC,style=default", FormatOptions{}},
+		{"si,style=upper", FormatOptions{Base: Base10, Style: StyleUpper}},
+		{"width=10,left,pad-units", FormatOptions{Width: 10, AlignLeft: true, PadUnits: true}},
+		{"digits=3", FormatOptions{Digits: 3}},
+		{"prec=1,round=ceil", FormatOptions{Precision: 1, Rounding: RoundCeil}},
+		{"round=Floor", FormatOptions{Rounding: RoundFloor}},
+		{"round=half-up,round=half-even", FormatOptions{}},
+		{"binary-only,decimal-only", FormatOptions{DecimalOnly: true}},
+		{"profile=JEDEC,space", FormatOptions{Profile: JEDEC, Space: true}},
+	}
+
+	for _, test := range positive {
+		out, err := ParseFormatOptions(test.in)
+		require.NoError(t, err, test.in)
+		require.Equal(t, test.out, out, test.in)
+	}
+
+	negative := []string{"iec,", "prec", "prec=-1", "prec=16", "prec=x", "iec=1", "metric",
+		"bytes=1", "profile=bogus", "digits=0", "digits", "round=up", "round", "width=-1",
+		"width", "left=1", "style=si", "style"}
+	for _, in := range negative {
+		_, err := ParseFormatOptions(in)
+		if testing.Verbose() {
+			fmt.Printf("%q ==> %v\n", in, err)
+		}
+		require.Error(t, err, in)
+	}
+}
+
+func TestSetDefaultFormat(t *testing.T) {
+	prev := DefaultFormat()
+	t.Cleanup(func() { SetDefaultFormat(prev) })
+
+	opts := FormatOptions{BinaryOnly: true, Precision: 2, Space: true}
+	SetDefaultFormat(opts)
+	require.Equal(t, opts, DefaultFormat())
+	require.Equal(t, "1.22 MiB", AsStr(1280001))
+	require.Equal(t, "1.22 MiB", Size(1280001).String())
+	bytes, err := json.Marshal(Size(1280001))
+	require.NoError(t, err)
+	require.Equal(t, `"1280001"`, string(bytes))
+
+	SetDefaultFormat(FormatOptions{})
+	require.Equal(t, FormatOptions{}, DefaultFormat())
+	require.Equal(t, "1280001", AsStr(1280001))
+}
+
+func TestMarshalDefaultFormat(t *testing.T) {
+	prev := DefaultFormat()
+	t.Cleanup(func() { SetDefaultFormat(prev) })
+
+	options := []FormatOptions{
+		{},
+		{Base: Base2},
+		{Base: Base10},
+		{BinaryOnly: true},
+		{DecimalOnly: true},
+		{Precision: 2},
+		{Precision: 2, KeepZeros: true},
+		{Digits: 3},
+		{Precision: 1, Rounding: RoundCeil},
+		{Style: StyleIEC},
+		{Style: StyleLetter},
+		{Style: StyleUpper},
+		{Style: StyleLower},
+		{Space: true},
+		{Separator: "\u00a0"},
+		{Keywords: true},
+		{Profile: JEDEC},
+		{Profile: Kubernetes},
+		{ByteSuffix: true},
+		{ByteWord: true},
+		{Words: true},
+		{Width: 12, PadUnits: true},
+	}
+	sizes := []Size{0, 1, 999, 1536, 1280001, Size(4 * Gigabyte), Size(4 * Gibibyte), 1<<64 - 1}
+
+	for _, opts := range options {
+		SetDefaultFormat(opts)
+		for _, size := range sizes {
+			text, err := json.Marshal(struct {
+				Size     Size
+				Signed   SignedSize
+				Rate     Rate
+				Range    SizeRange
+				Optional OptionalSize
+			}{size, -SignedSize(size / 2), Rate(size), SizeRange{size / 2, size},
+				OptionalSize(size)})
+			if testing.Verbose() {
+				fmt.Printf("%+v %v --> %s\n", opts, uint64(size), text)
+			}
+			require.NoError(t, err)
+
+			var out struct {
+				Size     Size
+				Signed   SignedSize
+				Rate     Rate
+				Range    SizeRange
+				Optional OptionalSize
+			}
+			require.NoError(t, json.Unmarshal(text, &out), string(text))
+			require.Equal(t, size, out.Size, string(text))
+			require.Equal(t, -SignedSize(size/2), out.Signed, string(text))
+			require.Equal(t, Rate(size), out.Rate, string(text))
+			require.Equal(t, SizeRange{size / 2, size}, out.Range, string(text))
+			require.Equal(t, OptionalSize(size), out.Optional, string(text))
+		}
+	}
+}
+
+func TestConfigureFromEnv(t *testing.T) {
+	defer ConfigureFromEnv()
+
+	t.Setenv("BYTEZ_FORMAT", "iec,prec=2,space")
+	require.NoError(t, ConfigureFromEnv())
+	require.Equal(t, "1.22 MiB", AsStr(1280000))
+	bytes, err := Size(1280000).MarshalText()
+	require.NoError(t, err)
+	require.Equal(t, "1280kb", string(bytes))
+
+	t.Setenv("BYTEZ_FORMAT", "bogus")
+	require.EqualError(t, ConfigureFromEnv(), `BYTEZ_FORMAT: unknown setting "bogus"`)
+	require.Equal(t, "1.22 MiB", AsStr(1280000))
+
+	t.Setenv("BYTEZ_FORMAT", "")
+	require.NoError(t, ConfigureFromEnv())
+	require.Equal(t, "1280kb", AsStr(1280000))
+}
+
+func TestFormatterAllocs(t *testing.T) {
+	var f Formatter
+	buf := make([]byte, 0, 64)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		f.AppendFormat(buf[:0], Size(3670016))
+	})
+	require.Zero(t, allocs)
+
+	rounded := NewFormatter(FormatOptions{Base: Base2, Precision: 2, Space: true})
+	allocs = testing.AllocsPerRun(100, func() {
+		rounded.AppendFormat(buf[:0], Size(1280000))
+	})
+	require.Zero(t, allocs)
+
+	// Format allocates at most the returned string.
+	allocs = testing.AllocsPerRun(100, func() {
+		f.Format(Size(3670016))
+	})
+	require.LessOrEqual(t, allocs, 1.0)
+}
+
+func BenchmarkFormatter(b *testing.B) {
+	var f Formatter
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		f.Format(Size(3670016))
+	}
+}
+
+func BenchmarkFormatterAppend(b *testing.B) {
+	var f Formatter
+	buf := make([]byte, 0, 64)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf = f.AppendFormat(buf[:0], Size(3670016))
+	}
+}
+
+func BenchmarkFormatterParallel(b *testing.B) {
+	var f Formatter
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		buf := make([]byte, 0, 64)
+		for pb.Next() {
+			buf = f.AppendFormat(buf[:0], Size(3670016))
+		}
+	})
+}
@@ -0,0 +1,70 @@
+//go:build !bytez_minimal
+
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package bytez
+
+import (
+	"io/fs"
+	"path"
+)
+
+// FSUsage is the space used by the files in a tree of a file system, as computed by MeasureFS.
+type FSUsage struct {
+	// Total is the combined size of all regular files in the tree.
+	Total Size
+
+	// Files is the number of regular files in the tree.
+	Files int
+
+	// Dirs maps the path of each directory in the tree, including the root, to the combined size
+	// of the regular files it contains, directly or in subdirectories.
+	Dirs map[string]Size
+}
+
+// MeasureFS walks the tree rooted at root in fsys and returns the space used by its regular files.
+// It works with any fs.FS, such as an embed.FS, a zip.Reader, or an fstest.MapFS, so tools can
+// report the footprint of embedded assets and virtual file systems as well as directories on
+// disk (using os.DirFS). Use "." as the root to measure the whole file system.
+//
+// Sizes are the lengths of the files as reported by fs.FileInfo, not the disk blocks they occupy.
+func MeasureFS(fsys fs.FS, root string) (*FSUsage, error) {
+	usage := &FSUsage{Dirs: map[string]Size{}}
+
+	err := fs.WalkDir(fsys, root, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			usage.Dirs[name] = 0
+			return nil
+		} else if !d.Type().IsRegular() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		size := Size(info.Size())
+		usage.Total += size
+		usage.Files++
+
+		// Add the file to every directory from its own up to the root.
+		for dir := path.Dir(name); ; dir = path.Dir(dir) {
+			usage.Dirs[dir] += size
+			if dir == root || dir == "." || dir == "/" {
+				break
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return usage, nil
+}
@@ -0,0 +1,55 @@
+//go:build !bytez_minimal
+
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package bytez
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMeasureFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"index.html":          {Data: make([]byte, 1000)},
+		"static/app.js":       {Data: make([]byte, 3*Kibibyte)},
+		"static/img/logo.png": {Data: make([]byte, 1*Mebibyte)},
+		"static/img/bg.png":   {Data: make([]byte, 2*Mebibyte)},
+		"empty":               {Mode: 0755 | 1<<31},
+	}
+
+	usage, err := MeasureFS(fsys, ".")
+	require.NoError(t, err)
+	if testing.Verbose() {
+		fmt.Printf("%d files, %s total\n", usage.Files, usage.Total.AsStr())
+	}
+	require.Equal(t, 4, usage.Files)
+	require.Equal(t, Size(1000+3*Kibibyte+3*Mebibyte), usage.Total)
+	require.Equal(t, map[string]Size{
+		".":          usage.Total,
+		"empty":      0,
+		"static":     Size(3*Kibibyte + 3*Mebibyte),
+		"static/img": Size(3 * Mebibyte),
+	}, usage.Dirs)
+
+	usage, err = MeasureFS(fsys, "static")
+	require.NoError(t, err)
+	require.Equal(t, 3, usage.Files)
+	require.Equal(t, Size(3*Kibibyte+3*Mebibyte), usage.Total)
+	require.Equal(t, map[string]Size{
+		"static":     Size(3*Kibibyte + 3*Mebibyte),
+		"static/img": Size(3 * Mebibyte),
+	}, usage.Dirs)
+
+	_, err = MeasureFS(fsys, "missing")
+	require.Error(t, err)
+	require.True(t, strings.Contains(err.Error(), "missing"))
+}
@@ -0,0 +1,166 @@
+//go:build !bytez_minimal
+
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package bytez
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// A Histogram accounts for a stream of sizes, like payload or allocation sizes, keeping their
+// count, sum, minimum, and maximum, and how many fall in each bucket. Histograms are safe for
+// concurrent use by multiple goroutines, and Observe does not block or allocate.
+type Histogram struct {
+	bounds []Size
+	counts []atomic.Uint64 // counts[i] is the count of sizes in (bounds[i-1], bounds[i]]
+	count  atomic.Uint64
+	sum    atomic.Uint64
+	min    atomic.Uint64 // stored inverted so that the zero value is the largest size
+	max    atomic.Uint64
+}
+
+// NewHistogram returns a Histogram with buckets for sizes up to and including each of bounds,
+// which must be in increasing order, and a final bucket for larger sizes. Use Buckets to create
+// readable bounds.
+func NewHistogram(bounds []Size) (*Histogram, error) {
+	for i := 1; i < len(bounds); i++ {
+		if bounds[i] <= bounds[i-1] {
+			return nil, errors.New("bounds must be in increasing order")
+		}
+	}
+
+	return &Histogram{
+		bounds: append([]Size(nil), bounds...),
+		counts: make([]atomic.Uint64, len(bounds)+1),
+	}, nil
+}
+
+// Observe adds size to the histogram. The sum saturates at the largest Size.
+func (h *Histogram) Observe(size Size) {
+	idx := sort.Search(len(h.bounds), func(i int) bool { return h.bounds[i] >= size })
+	h.counts[idx].Add(1)
+	h.count.Add(1)
+
+	for {
+		old := h.sum.Load()
+		sum := old + uint64(size)
+		if sum < old {
+			sum = math.MaxUint64
+		}
+		if h.sum.CompareAndSwap(old, sum) {
+			break
+		}
+	}
+	for inv := ^uint64(size); ; {
+		old := h.min.Load()
+		if inv <= old || h.min.CompareAndSwap(old, inv) {
+			break
+		}
+	}
+	for {
+		old := h.max.Load()
+		if uint64(size) <= old || h.max.CompareAndSwap(old, uint64(size)) {
+			break
+		}
+	}
+}
+
+// Snapshot returns the current state of the histogram. Observations made concurrently with the
+// call may be partially reflected.
+func (h *Histogram) Snapshot() HistogramSnapshot {
+	snap := HistogramSnapshot{
+		Bounds: h.bounds,
+		Counts: make([]uint64, len(h.counts)),
+		Sum:    Size(h.sum.Load()),
+		Max:    Size(h.max.Load()),
+	}
+	for i := range h.counts {
+		snap.Counts[i] = h.counts[i].Load()
+		snap.Count += snap.Counts[i]
+	}
+	if snap.Count > 0 {
+		snap.Min = Size(^h.min.Load())
+	}
+	return snap
+}
+
+// HistogramSnapshot is the state of a Histogram at some point in time.
+type HistogramSnapshot struct {
+	// Bounds are the upper bounds of the buckets, excluding the last one, which has no bound. It
+	// must not be modified.
+	Bounds []Size
+
+	// Counts holds the number of sizes in each bucket, not cumulative, so it is one longer than
+	// Bounds.
+	Counts []uint64
+
+	// Count is the number of sizes observed.
+	Count uint64
+
+	// Sum, Min, and Max are the sum, smallest, and largest of the sizes observed. They are zero
+	// if no sizes have been observed.
+	Sum, Min, Max Size
+}
+
+// Mean returns the average of the sizes observed, rounded down, or 0 if there are none.
+func (s HistogramSnapshot) Mean() Size {
+	if s.Count == 0 {
+		return 0
+	}
+	return s.Sum / Size(s.Count)
+}
+
+// Quantile returns an estimate of the q-quantile of the sizes observed, for q between 0 and 1:
+// the upper bound of the bucket holding it, limited to the range of the sizes observed. It
+// returns 0 if there are none.
+func (s HistogramSnapshot) Quantile(q float64) Size {
+	if s.Count == 0 {
+		return 0
+	} else if q <= 0 {
+		return s.Min
+	} else if q >= 1 {
+		return s.Max
+	}
+
+	rank := uint64(math.Ceil(q * float64(s.Count)))
+	var seen uint64
+	for i, n := range s.Counts {
+		if seen += n; seen >= rank && n > 0 {
+			if i == len(s.Bounds) || s.Bounds[i] > s.Max {
+				return s.Max
+			} else if s.Bounds[i] < s.Min {
+				return s.Min
+			}
+			return s.Bounds[i]
+		}
+	}
+	return s.Max
+}
+
+// String returns a summary of the snapshot with humanized sizes, like
+// "count=3 sum=2.5MiB min=512KiB mean=853.5KiB p50=1MiB p99=1MiB max=1MiB".
+func (s HistogramSnapshot) String() string {
+	var b strings.Builder
+	b.WriteString("count=")
+	b.WriteString(strconv.FormatUint(s.Count, 10))
+	for _, field := range []struct {
+		name string
+		size Size
+	}{
+		{"sum", s.Sum}, {"min", s.Min}, {"mean", s.Mean()},
+		{"p50", s.Quantile(0.5)}, {"p99", s.Quantile(0.99)}, {"max", s.Max},
+	} {
+		b.WriteString(" " + field.name + "=" + field.size.AsStr())
+	}
+	return b.String()
+}
@@ -0,0 +1,95 @@
+//go:build !bytez_minimal
+
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package bytez
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistogram(t *testing.T) {
+	_, err := NewHistogram([]Size{4096, 1024})
+	require.Error(t, err)
+
+	bounds, err := Buckets(Size(Kibibyte), Size(Mebibyte), 4)
+	require.NoError(t, err)
+	h, err := NewHistogram(bounds)
+	require.NoError(t, err)
+
+	snap := h.Snapshot()
+	require.Zero(t, snap.Count)
+	require.Zero(t, snap.Min)
+	require.Zero(t, snap.Mean())
+	require.Zero(t, snap.Quantile(0.5))
+
+	for _, size := range []Size{100, 1024, 1025, 3000, Size(Mebibyte), Size(2 * Mebibyte)} {
+		h.Observe(size)
+	}
+	snap = h.Snapshot()
+	if testing.Verbose() {
+		fmt.Println(snap)
+	}
+	require.Equal(t, []Size{1024, 4096, 16384, 65536, 262144, 1048576}, snap.Bounds)
+	require.Equal(t, []uint64{2, 2, 0, 0, 0, 1, 1}, snap.Counts)
+	require.Equal(t, uint64(6), snap.Count)
+	require.Equal(t, Size(100+1024+1025+3000+3*Mebibyte), snap.Sum)
+	require.Equal(t, Size(100), snap.Min)
+	require.Equal(t, Size(2*Mebibyte), snap.Max)
+	require.Equal(t, snap.Sum/6, snap.Mean())
+	require.Equal(t, Size(100), snap.Quantile(0))
+	require.Equal(t, Size(1024), snap.Quantile(0.2))
+	require.Equal(t, Size(4096), snap.Quantile(0.5))
+	require.Equal(t, Size(Mebibyte), snap.Quantile(0.8))
+	require.Equal(t, Size(2*Mebibyte), snap.Quantile(1))
+	require.Equal(t, "count=6 sum="+snap.Sum.AsStr()+" min=100 mean="+snap.Mean().AsStr()+
+		" p50=4KiB p99=2MiB max=2MiB", snap.String())
+
+	h.Observe(math.MaxUint64)
+	require.Equal(t, Size(math.MaxUint64), h.Snapshot().Sum)
+}
+
+func TestHistogramConcurrent(t *testing.T) {
+	h, err := NewHistogram([]Size{10, 100})
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 1; i <= 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				h.Observe(Size(i * j))
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	snap := h.Snapshot()
+	require.Equal(t, uint64(8000), snap.Count)
+	require.Equal(t, Size(0), snap.Min)
+	require.Equal(t, Size(8*999), snap.Max)
+	require.Equal(t, Size(36*999*1000/2), snap.Sum)
+}
+
+func BenchmarkHistogramObserve(b *testing.B) {
+	bounds, _ := Buckets(256, Size(Gibibyte), 4)
+	h, _ := NewHistogram(bounds)
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		var size Size
+		for pb.Next() {
+			h.Observe(size)
+			size += 4096
+		}
+	})
+}
@@ -0,0 +1,79 @@
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package httpsize
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/nexvium/bytez"
+)
+
+// ErrMissing is the error of a FieldError for a parameter that is missing or empty.
+var ErrMissing = errors.New("missing value")
+
+// A FieldError reports a size parameter of a request that is missing or invalid, in which case
+// the request should be rejected with 400 Bad Request and the message of the error.
+type FieldError struct {
+	Key   string
+	Value string
+	Err   error
+}
+
+// Error returns a message naming the parameter, like
+// `invalid maxSize "100MiBs": invalid units: did you mean "MiB"?`.
+func (e *FieldError) Error() string {
+	if e.Err == ErrMissing {
+		return "missing " + e.Key
+	}
+	return fmt.Sprintf("invalid %s %q: %v", e.Key, e.Value, e.Err)
+}
+
+// Unwrap returns the underlying error, which is ErrMissing or a *bytez.ParseError.
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// SizeFromRequest returns the size in the query parameter of r with the given key, like
+// "?maxSize=100MiB", in the syntax accepted by bytez.AsInt. Errors are of type *FieldError.
+func SizeFromRequest(r *http.Request, key string) (bytez.Size, error) {
+	return parseField(key, r.URL.Query().Get(key), nil)
+}
+
+// FormValueSize returns the size in the form value of r with the given key, which, as with
+// http.Request.FormValue, may be a query parameter or a field of a form posted in the body. The
+// size is parsed with the given options, as by bytez.ParseSize. Errors for invalid options are
+// returned as they are, and other errors are of type *FieldError.
+func FormValueSize(r *http.Request, key string, opts ...bytez.Option) (bytez.Size, error) {
+	p, err := bytez.NewParser(opts...)
+	if err != nil {
+		return 0, err
+	}
+	return parseField(key, r.FormValue(key), p)
+}
+
+// parseField parses the value of a parameter with p, or bytez.AsInt if p is nil.
+func parseField(key, value string, p *bytez.Parser) (bytez.Size, error) {
+	if value == "" {
+		return 0, &FieldError{Key: key, Err: ErrMissing}
+	}
+
+	var size bytez.Size
+	var err error
+	if p != nil {
+		size, err = p.Parse(value)
+	} else {
+		var val uint64
+		val, err = bytez.AsInt(value)
+		size = bytez.Size(val)
+	}
+	if err != nil {
+		return 0, &FieldError{Key: key, Value: value, Err: err}
+	}
+	return size, nil
+}
@@ -0,0 +1,82 @@
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package httpsize
+
+import (
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/nexvium/bytez"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSizeFromRequest(t *testing.T) {
+	var tests = []struct {
+		target string
+		out    bytez.Size
+		err    string
+	}{
+		{"/?maxSize=100MiB", bytez.Size(100 * bytez.Mebibyte), ""},
+		{"/?maxSize=1.5+GiB", bytez.Size(3 * bytez.Gibibyte / 2), ""},
+		{"/?maxSize=4096&other=1", 4096, ""},
+		{"/", 0, "missing maxSize"},
+		{"/?maxSize=", 0, "missing maxSize"},
+		{"/?maxSize=100MiBs", 0, `invalid maxSize "100MiBs": invalid units: did you mean "MiB"?`},
+	}
+
+	for _, test := range tests {
+		out, err := SizeFromRequest(httptest.NewRequest("GET", test.target, nil), "maxSize")
+		if testing.Verbose() {
+			fmt.Printf("%s --> %v, %v\n", test.target, out, err)
+		}
+		if test.err != "" {
+			require.EqualError(t, err, test.err)
+			var ferr *FieldError
+			require.True(t, errors.As(err, &ferr), test.target)
+			require.Equal(t, "maxSize", ferr.Key)
+		} else {
+			require.NoError(t, err, test.target)
+			require.Equal(t, test.out, out, test.target)
+		}
+	}
+
+	_, err := SizeFromRequest(httptest.NewRequest("GET", "/", nil), "maxSize")
+	require.True(t, errors.Is(err, ErrMissing))
+	_, err = SizeFromRequest(httptest.NewRequest("GET", "/?maxSize=x", nil), "maxSize")
+	var perr *bytez.ParseError
+	require.True(t, errors.As(err, &perr))
+}
+
+func TestFormValueSize(t *testing.T) {
+	req := httptest.NewRequest("POST", "/?limit=2GB", strings.NewReader("quota=512MiB&bad=5XB"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	out, err := FormValueSize(req, "quota")
+	require.NoError(t, err)
+	require.Equal(t, bytez.Size(512*bytez.Mebibyte), out)
+
+	out, err = FormValueSize(req, "limit", bytez.WithBase(bytez.Base10))
+	require.NoError(t, err)
+	require.Equal(t, bytez.Size(2*bytez.Gigabyte), out)
+
+	_, err = FormValueSize(req, "limit", bytez.WithMax(bytez.Size(bytez.Gigabyte)))
+	require.EqualError(t, err, `invalid limit "2GB": size exceeds maximum`)
+
+	_, err = FormValueSize(req, "bad")
+	require.EqualError(t, err, `invalid bad "5XB": invalid units`)
+
+	_, err = FormValueSize(req, "size")
+	require.EqualError(t, err, "missing size")
+
+	_, err = FormValueSize(req, "quota", bytez.WithBase(bytez.Base(3)))
+	require.Error(t, err)
+	var ferr *FieldError
+	require.False(t, errors.As(err, &ferr))
+}
@@ -0,0 +1,199 @@
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package httpsize
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/nexvium/bytez"
+)
+
+// ErrUnsatisfiable is returned by ParseRange when none of the requested ranges overlaps the
+// representation, to which servers respond with 416 Range Not Satisfiable.
+var ErrUnsatisfiable = errors.New("range not satisfiable")
+
+// FormatContentLength returns the value of a Content-Length header for a body of the given size,
+// like "1048576".
+func FormatContentLength(size bytez.Size) string {
+	return strconv.FormatUint(uint64(size), 10)
+}
+
+// ParseContentLength parses the value of a Content-Length header, which is a number of bytes
+// without units, like "1048576", optionally surrounded by spaces. Lengths that do not fit in an
+// int64, like those of net/http, are rejected.
+func ParseContentLength(value string) (bytez.Size, error) {
+	length, ok := parseUint(strings.Trim(value, " \t"))
+	if !ok || length > math.MaxInt64 {
+		return 0, fmt.Errorf("invalid Content-Length %q", value)
+	}
+	return bytez.Size(length), nil
+}
+
+// ByteRange is a range of bytes of a representation, from the offset Start to the offset End
+// inclusive, as in HTTP range requests.
+type ByteRange struct {
+	Start, End bytez.Size
+}
+
+// Len returns the number of bytes in the range.
+func (r ByteRange) Len() bytez.Size {
+	return r.End - r.Start + 1
+}
+
+// String returns the range as in a Range header, like "0-1048575".
+func (r ByteRange) String() string {
+	return strconv.FormatUint(uint64(r.Start), 10) + "-" + strconv.FormatUint(uint64(r.End), 10)
+}
+
+// FormatRange returns the value of a Range header requesting the given ranges, like
+// "bytes=0-1048575" or "bytes=0-99,200-299".
+func FormatRange(ranges ...ByteRange) string {
+	var sb strings.Builder
+	sb.WriteString("bytes=")
+	for i, r := range ranges {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(r.String())
+	}
+	return sb.String()
+}
+
+// ParseRange parses the value of a Range header, like "bytes=0-1048575" or "bytes=0-99, -500",
+// and returns the requested ranges of a representation of total bytes, in the order in which they
+// were requested. Ranges may omit their end, like "1000-", to request the rest of the
+// representation, or their start, like "-500", to request its last bytes. Ranges ending past the
+// end of the representation are shortened, and ranges starting past it are dropped; if no range is
+// left, ErrUnsatisfiable is returned. Other errors mean that the header is invalid and, as
+// recommended by RFC 9110, should be ignored.
+func ParseRange(value string, total bytez.Size) ([]ByteRange, error) {
+	const unit = "bytes="
+	if len(value) < len(unit) || !strings.EqualFold(value[:len(unit)], unit) {
+		return nil, fmt.Errorf("invalid range %q: not a byte range", value)
+	}
+
+	var ranges []ByteRange
+	var empty = true
+	for _, spec := range strings.Split(value[len(unit):], ",") {
+		spec = strings.Trim(spec, " \t")
+		if spec == "" {
+			continue
+		}
+		empty = false
+
+		first, last, ok := strings.Cut(spec, "-")
+		start, startOK := parseUint(first)
+		end, endOK := parseUint(last)
+		if !ok || (!startOK && first != "") || (!endOK && last != "") || first+last == "" {
+			return nil, fmt.Errorf("invalid range %q: invalid range %q", value, spec)
+		}
+		switch {
+		case first == "":
+			// A suffix range, for the last bytes.
+			if end == 0 || total == 0 {
+				continue
+			}
+			start, end = uint64(total)-min(end, uint64(total)), uint64(total)-1
+		case last == "":
+			end = uint64(total) - 1
+		case start > end:
+			return nil, fmt.Errorf("invalid range %q: range %q ends before it starts", value, spec)
+		}
+
+		if start >= uint64(total) {
+			continue
+		}
+		end = min(end, uint64(total)-1)
+		ranges = append(ranges, ByteRange{Start: bytez.Size(start), End: bytez.Size(end)})
+	}
+
+	if empty {
+		return nil, fmt.Errorf("invalid range %q: no ranges", value)
+	} else if ranges == nil {
+		return nil, ErrUnsatisfiable
+	}
+	return ranges, nil
+}
+
+// ContentRange is the value of a Content-Range header, which gives the range of a representation
+// sent in a partial response and the total size of the representation.
+type ContentRange struct {
+	// Range is the range sent, unless Unsatisfied is set, as in "bytes */1000", the value sent
+	// with 416 Range Not Satisfiable responses.
+	Range       ByteRange
+	Unsatisfied bool
+
+	// Total is the size of the representation, unless UnknownTotal is set, as in
+	// "bytes 0-99/*".
+	Total        bytez.Size
+	UnknownTotal bool
+}
+
+// String returns the value of the header, like "bytes 0-1048575/4194304".
+func (c ContentRange) String() string {
+	var rng, total = "*", "*"
+	if !c.Unsatisfied {
+		rng = c.Range.String()
+	}
+	if !c.UnknownTotal {
+		total = strconv.FormatUint(uint64(c.Total), 10)
+	}
+	return "bytes " + rng + "/" + total
+}
+
+// ParseContentRange parses the value of a Content-Range header, like "bytes 0-1048575/4194304",
+// "bytes 0-99/*", or "bytes */4194304", and checks that the range is within the total size.
+func ParseContentRange(value string) (ContentRange, error) {
+	const unit = "bytes "
+	if len(value) < len(unit) || !strings.EqualFold(value[:len(unit)], unit) {
+		return ContentRange{}, fmt.Errorf("invalid Content-Range %q: not a byte range", value)
+	}
+	rng, total, ok := strings.Cut(value[len(unit):], "/")
+	if !ok {
+		return ContentRange{}, fmt.Errorf("invalid Content-Range %q: missing total size", value)
+	}
+
+	var c ContentRange
+	if total == "*" {
+		c.UnknownTotal = true
+	} else if n, ok := parseUint(total); ok {
+		c.Total = bytez.Size(n)
+	} else {
+		return ContentRange{}, fmt.Errorf("invalid Content-Range %q: invalid total size", value)
+	}
+
+	if rng == "*" {
+		if c.UnknownTotal {
+			return ContentRange{}, fmt.Errorf("invalid Content-Range %q: no range or total size", value)
+		}
+		c.Unsatisfied = true
+		return c, nil
+	}
+	first, last, _ := strings.Cut(rng, "-")
+	start, startOK := parseUint(first)
+	end, endOK := parseUint(last)
+	if !startOK || !endOK || start > end {
+		return ContentRange{}, fmt.Errorf("invalid Content-Range %q: invalid range", value)
+	} else if !c.UnknownTotal && end >= uint64(c.Total) {
+		return ContentRange{}, fmt.Errorf("invalid Content-Range %q: range exceeds total size", value)
+	}
+	c.Range = ByteRange{Start: bytez.Size(start), End: bytez.Size(end)}
+	return c, nil
+}
+
+// parseUint parses a non-empty decimal number of digits only, as used in HTTP headers.
+func parseUint(str string) (uint64, bool) {
+	if str == "" || str[0] < '0' || str[0] > '9' {
+		return 0, false
+	}
+	n, err := strconv.ParseUint(str, 10, 64)
+	return n, err == nil
+}
@@ -0,0 +1,117 @@
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package httpsize
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/nexvium/bytez"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContentLength(t *testing.T) {
+	require.Equal(t, "1048576", FormatContentLength(bytez.Size(bytez.Mebibyte)))
+
+	size, err := ParseContentLength(" 1048576 ")
+	require.NoError(t, err)
+	require.Equal(t, bytez.Size(bytez.Mebibyte), size)
+
+	for _, in := range []string{"", "-1", "+1", "1MiB", "1,024", "1 024", "0x10",
+		"9223372036854775808"} {
+		_, err := ParseContentLength(in)
+		if testing.Verbose() {
+			fmt.Printf("%q ==> %v\n", in, err)
+		}
+		require.Error(t, err, in)
+	}
+}
+
+func TestParseRange(t *testing.T) {
+	const total = 10000
+
+	var tests = []struct {
+		in  string
+		out []ByteRange
+	}{
+		{"bytes=0-1048575", []ByteRange{{0, 9999}}},
+		{"bytes=0-499", []ByteRange{{0, 499}}},
+		{"Bytes=500-999", []ByteRange{{500, 999}}},
+		{"bytes=9500-", []ByteRange{{9500, 9999}}},
+		{"bytes=-500", []ByteRange{{9500, 9999}}},
+		{"bytes=-20000", []ByteRange{{0, 9999}}},
+		{"bytes=0-0,-1", []ByteRange{{0, 0}, {9999, 9999}}},
+		{"bytes=500-600, 601-999 ,", []ByteRange{{500, 600}, {601, 999}}},
+		{"bytes=20000-, 0-99", []ByteRange{{0, 99}}},
+	}
+
+	for _, test := range tests {
+		out, err := ParseRange(test.in, total)
+		if testing.Verbose() {
+			fmt.Printf("%q --> %v\n", test.in, out)
+		}
+		require.NoError(t, err, test.in)
+		require.Equal(t, test.out, out, test.in)
+	}
+
+	for _, in := range []string{"bytes=10000-", "bytes=10000-20000", "bytes=-0", "bytes=10000-,-0"} {
+		_, err := ParseRange(in, total)
+		require.Equal(t, ErrUnsatisfiable, err, in)
+	}
+	_, err := ParseRange("bytes=0-", 0)
+	require.Equal(t, ErrUnsatisfiable, err)
+
+	for _, in := range []string{"", "bytes", "bytes=", "bytes=,", "items=0-9", "bytes=-", "bytes=9-0",
+		"bytes=0-9-", "bytes=a-9", "bytes=0x0-9", "bytes=+0-9", "bytes=0 - 9",
+		"bytes=0-99999999999999999999"} {
+		_, err := ParseRange(in, total)
+		if testing.Verbose() {
+			fmt.Printf("%q ==> %v\n", in, err)
+		}
+		require.Error(t, err, in)
+		require.NotEqual(t, ErrUnsatisfiable, err, in)
+	}
+
+	ranges := []ByteRange{{0, 499}, {1000, 1999}}
+	require.Equal(t, "bytes=0-499,1000-1999", FormatRange(ranges...))
+	out, err := ParseRange(FormatRange(ranges...), total)
+	require.NoError(t, err)
+	require.Equal(t, ranges, out)
+	require.Equal(t, bytez.Size(1000), ranges[1].Len())
+}
+
+func TestContentRange(t *testing.T) {
+	var tests = []struct {
+		in  string
+		out ContentRange
+	}{
+		{"bytes 0-1048575/4194304", ContentRange{Range: ByteRange{0, 1048575}, Total: 4194304}},
+		{"bytes 42-42/43", ContentRange{Range: ByteRange{42, 42}, Total: 43}},
+		{"bytes 0-99/*", ContentRange{Range: ByteRange{0, 99}, UnknownTotal: true}},
+		{"bytes */4194304", ContentRange{Unsatisfied: true, Total: 4194304}},
+	}
+
+	for _, test := range tests {
+		out, err := ParseContentRange(test.in)
+		if testing.Verbose() {
+			fmt.Printf("%q --> %+v\n", test.in, out)
+		}
+		require.NoError(t, err, test.in)
+		require.Equal(t, test.out, out, test.in)
+		require.Equal(t, test.in, out.String())
+	}
+
+	for _, in := range []string{"", "bytes", "bytes 0-99", "bytes */*", "bytes 0-99/100x",
+		"bytes 99-0/100", "bytes 0-100/100", "bytes 0/100", "bytes -5/100", "items 0-9/10",
+		"bytes  0-9/10"} {
+		_, err := ParseContentRange(in)
+		if testing.Verbose() {
+			fmt.Printf("%q ==> %v\n", in, err)
+		}
+		require.Error(t, err, in)
+	}
+}
@@ -0,0 +1,195 @@
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+// Package httpsize measures the sizes of HTTP request and response bodies. Wrapping a handler
+// is enough to start recording them in histograms, which are published with humanized summaries
+// through expvar and can be served in the Prometheus text format:
+//
+//	http.Handle("/", httpsize.Wrap(handler))
+//	http.Handle("/metrics", httpsize.DefaultRecorder.Metrics())
+//
+// It also formats and parses the headers that give sizes and ranges of bodies in bytes:
+// Content-Length, Range, and Content-Range, and parses sizes given as request parameters, like
+// "?maxSize=100MiB".
+package httpsize
+
+import (
+	"bufio"
+	"expvar"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/nexvium/bytez"
+)
+
+// DefaultBuckets are the bucket bounds used by NewRecorder: 256, 1KiB, 4KiB, and so on, up to
+// 1GiB.
+var DefaultBuckets, _ = bytez.Buckets(256, bytez.Size(bytez.Gibibyte), 4)
+
+// A Recorder records the sizes of the request and response bodies handled by the handlers it
+// wraps.
+type Recorder struct {
+	// Requests and Responses hold the sizes of request and response bodies.
+	Requests, Responses *bytez.Histogram
+}
+
+// NewRecorder returns a Recorder with histograms using bounds, or DefaultBuckets if bounds is
+// nil.
+func NewRecorder(bounds []bytez.Size) (*Recorder, error) {
+	if bounds == nil {
+		bounds = DefaultBuckets
+	}
+	req, err := bytez.NewHistogram(bounds)
+	if err != nil {
+		return nil, err
+	}
+	resp, _ := bytez.NewHistogram(bounds)
+	return &Recorder{Requests: req, Responses: resp}, nil
+}
+
+// DefaultRecorder is the Recorder used by Wrap. It is published with expvar as "httpsize" the
+// first time Wrap is called.
+var DefaultRecorder, _ = NewRecorder(nil)
+
+var publishOnce sync.Once
+
+// Wrap returns a handler that records body sizes with DefaultRecorder and calls h.
+func Wrap(h http.Handler) http.Handler {
+	publishOnce.Do(func() {
+		expvar.Publish("httpsize", DefaultRecorder.Var())
+	})
+	return DefaultRecorder.Wrap(h)
+}
+
+// Wrap returns a handler that records body sizes with r and calls h.
+//
+// The size of a request body is its Content-Length if known, since the server discards unread
+// bodies, and otherwise the number of bytes read by h. The size of a response body is the
+// number of bytes written by h.
+func (r *Recorder) Wrap(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var body *countingBody
+		if req.Body != nil && req.Body != http.NoBody {
+			body = &countingBody{ReadCloser: req.Body}
+			req.Body = body
+		}
+		cw := &countingWriter{ResponseWriter: w}
+
+		h.ServeHTTP(cw, req)
+
+		switch {
+		case req.ContentLength >= 0:
+			r.Requests.Observe(bytez.Size(req.ContentLength))
+		case body != nil:
+			r.Requests.Observe(bytez.Size(body.n))
+		default:
+			r.Requests.Observe(0)
+		}
+		r.Responses.Observe(bytez.Size(cw.n))
+	})
+}
+
+// Var returns an expvar.Var whose value is a JSON object with humanized summaries of the
+// request and response sizes, suitable for expvar.Publish.
+func (r *Recorder) Var() expvar.Var {
+	return expvar.Func(func() interface{} {
+		return map[string]interface{}{
+			"requests":  summary(r.Requests.Snapshot()),
+			"responses": summary(r.Responses.Snapshot()),
+		}
+	})
+}
+
+// summary returns the statistics of s, with sizes formatted by bytez.AsCanonicalStr so that
+// collectors read the same text whatever the default format of the program is.
+func summary(s bytez.HistogramSnapshot) map[string]interface{} {
+	str := func(size bytez.Size) string {
+		return bytez.AsCanonicalStr(uint64(size))
+	}
+	return map[string]interface{}{
+		"count": s.Count,
+		"sum":   str(s.Sum),
+		"min":   str(s.Min),
+		"mean":  str(s.Mean()),
+		"p50":   str(s.Quantile(0.5)),
+		"p90":   str(s.Quantile(0.9)),
+		"p99":   str(s.Quantile(0.99)),
+		"max":   str(s.Max),
+	}
+}
+
+// Metrics returns a handler that serves the histograms in the Prometheus text exposition format
+// as http_request_size_bytes and http_response_size_bytes.
+func (r *Recorder) Metrics() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		bw := bufio.NewWriter(w)
+		writeHistogram(bw, "http_request_size_bytes", "Size of HTTP request bodies.",
+			r.Requests.Snapshot())
+		writeHistogram(bw, "http_response_size_bytes", "Size of HTTP response bodies.",
+			r.Responses.Snapshot())
+		bw.Flush()
+	})
+}
+
+func writeHistogram(w io.Writer, name, help string, s bytez.HistogramSnapshot) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s histogram\n", name, help, name)
+	var cum uint64
+	for i, bound := range s.Bounds {
+		cum += s.Counts[i]
+		fmt.Fprintf(w, "%s_bucket{le=\"%d\"} %d\n", name, uint64(bound), cum)
+	}
+	fmt.Fprintf(w, "%s_bucket{le=\"+Inf\"} %d\n", name, s.Count)
+	fmt.Fprintf(w, "%s_sum %s\n%s_count %d\n", name, strconv.FormatUint(uint64(s.Sum), 10),
+		name, s.Count)
+}
+
+type countingBody struct {
+	io.ReadCloser
+	n uint64
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.n += uint64(n)
+	return n, err
+}
+
+type countingWriter struct {
+	http.ResponseWriter
+	n uint64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(p)
+	w.n += uint64(n)
+	return n, err
+}
+
+// Flush implements http.Flusher so that streaming handlers keep working.
+func (w *countingWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack implements http.Hijacker so that handlers can take over the connection, in which case
+// the bytes they write to it are not counted.
+func (w *countingWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if h, ok := w.ResponseWriter.(http.Hijacker); ok {
+		return h.Hijack()
+	}
+	return nil, nil, http.ErrNotSupported
+}
+
+// Unwrap returns the original ResponseWriter, for use by http.ResponseController.
+func (w *countingWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
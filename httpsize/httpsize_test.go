@@ -0,0 +1,94 @@
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package httpsize
+
+import (
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/nexvium/bytez"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecorder(t *testing.T) {
+	_, err := NewRecorder([]bytez.Size{2, 1})
+	require.Error(t, err)
+
+	rec, err := NewRecorder([]bytez.Size{1024, 1 << 20})
+	require.NoError(t, err)
+
+	handler := rec.Wrap(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		n, _ := io.Copy(ioutil.Discard, req.Body)
+		w.(http.Flusher).Flush()
+		fmt.Fprint(w, strings.Repeat("x", int(2*n)))
+	}))
+
+	for _, body := range []string{"", strings.Repeat("a", 512), strings.Repeat("b", 4096)} {
+		req := httptest.NewRequest("POST", "/", strings.NewReader(body))
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	// A chunked request has no Content-Length, so the bytes read are counted.
+	req := httptest.NewRequest("POST", "/", ioutil.NopCloser(strings.NewReader("chunked")))
+	req.ContentLength = -1
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	reqs := rec.Requests.Snapshot()
+	resps := rec.Responses.Snapshot()
+	if testing.Verbose() {
+		fmt.Println("requests: ", reqs)
+		fmt.Println("responses:", resps)
+	}
+	require.Equal(t, uint64(4), reqs.Count)
+	require.Equal(t, bytez.Size(512+4096+7), reqs.Sum)
+	require.Equal(t, []uint64{3, 1, 0}, reqs.Counts)
+	require.Equal(t, bytez.Size(2*(512+4096+7)), resps.Sum)
+	require.Equal(t, bytez.Size(8192), resps.Max)
+
+	var vars map[string]map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(rec.Var().String()), &vars))
+	require.Equal(t, "4615", vars["requests"]["sum"])
+	require.Equal(t, "8KiB", vars["responses"]["max"])
+	require.Equal(t, float64(4), vars["responses"]["count"])
+
+	w := httptest.NewRecorder()
+	rec.Metrics().ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	if testing.Verbose() {
+		fmt.Print(w.Body.String())
+	}
+	require.Contains(t, w.Body.String(), `# TYPE http_request_size_bytes histogram
+http_request_size_bytes_bucket{le="1024"} 3
+http_request_size_bytes_bucket{le="1048576"} 4
+http_request_size_bytes_bucket{le="+Inf"} 4
+http_request_size_bytes_sum 4615
+http_request_size_bytes_count 4
+`)
+	require.Contains(t, w.Body.String(), "http_response_size_bytes_sum 9230\n")
+}
+
+func TestWrap(t *testing.T) {
+	server := httptest.NewServer(Wrap(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Write(make([]byte, 1536))
+	})))
+	defer server.Close()
+
+	resp, err := http.Post(server.URL, "text/plain", strings.NewReader("hello"))
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	require.Equal(t, bytez.Size(5), DefaultRecorder.Requests.Snapshot().Max)
+	require.Equal(t, bytez.Size(1536), DefaultRecorder.Responses.Snapshot().Max)
+	require.NotNil(t, expvar.Get("httpsize"))
+	require.Contains(t, expvar.Get("httpsize").String(), `"max":"1.5KiB"`)
+}
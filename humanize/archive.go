@@ -0,0 +1,127 @@
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package humanize
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+)
+
+// countingReader counts the bytes read through it.
+type countingReader struct {
+	r io.Reader
+	n uint64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += uint64(n)
+	return n, err
+}
+
+// Tar reads a tar archive from r, which may be gzipped, and writes a listing of the regular files
+// it contains with their sizes in human form, followed by the total. For gzipped archives the
+// total also shows the compressed size of the whole archive. Only headers are read; file contents
+// are skipped without being extracted.
+func Tar(w io.Writer, r io.Reader) error {
+	cr := &countingReader{r: r}
+	br := bufio.NewReader(cr)
+
+	var in io.Reader = br
+	magic, _ := br.Peek(2)
+	gzipped := len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b
+	if gzipped {
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			return err
+		}
+		in = zr
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "%10s  %s\n", "size", "name")
+
+	var total uint64
+	var files int
+	tr := tar.NewReader(in)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		fmt.Fprintf(bw, "%10s  %s\n", byteStr(uint64(hdr.Size)), hdr.Name)
+		total += uint64(hdr.Size)
+		files++
+	}
+
+	summary := "total (" + fileCount(files)
+	if gzipped {
+		// Read the rest of the archive so the count includes the gzip trailer and padding.
+		io.Copy(ioutil.Discard, in)
+		io.Copy(ioutil.Discard, br)
+		summary += ", " + byteStr(cr.n) + " compressed"
+	}
+	fmt.Fprintf(bw, "%10s  %s)\n", byteStr(total), summary)
+	return bw.Flush()
+}
+
+// Zip reads the central directory of the zip archive in r, which is size bytes long, and writes
+// a listing of the files it contains with their uncompressed and compressed sizes in human form
+// and the space saved by compression, followed by the totals. File contents are not read.
+func Zip(w io.Writer, r io.ReaderAt, size int64) error {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "%10s %11s %7s  %s\n", "size", "compressed", "saved", "name")
+
+	var total, compressed uint64
+	var files int
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		fmt.Fprintf(bw, "%10s %11s %7s  %s\n", byteStr(f.UncompressedSize64),
+			byteStr(f.CompressedSize64), saved(f.UncompressedSize64, f.CompressedSize64), f.Name)
+		total += f.UncompressedSize64
+		compressed += f.CompressedSize64
+		files++
+	}
+
+	fmt.Fprintf(bw, "%10s %11s %7s  total (%s)\n", byteStr(total), byteStr(compressed),
+		saved(total, compressed), fileCount(files))
+	return bw.Flush()
+}
+
+// saved returns the percentage of space saved by compressing size bytes to compressed bytes.
+func saved(size, compressed uint64) string {
+	if size == 0 {
+		return "-"
+	}
+	pct := 100 * (1 - float64(compressed)/float64(size))
+	return strconv.FormatFloat(pct, 'f', 2, 64) + "%"
+}
+
+func fileCount(n int) string {
+	if n == 1 {
+		return "1 file"
+	}
+	return strconv.Itoa(n) + " files"
+}
@@ -0,0 +1,101 @@
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package humanize
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var archiveFiles = []struct {
+	name string
+	size int
+}{
+	{"docs/", 0},
+	{"docs/README", 512},
+	{"data.bin", 1536 * 1024},
+}
+
+func testTar(t *testing.T, gzipped bool) []byte {
+	var buf bytes.Buffer
+	var zw *gzip.Writer
+	tw := tar.NewWriter(&buf)
+	if gzipped {
+		zw = gzip.NewWriter(&buf)
+		tw = tar.NewWriter(zw)
+	}
+
+	for _, f := range archiveFiles {
+		hdr := &tar.Header{Name: f.name, Size: int64(f.size), Mode: 0644, Typeflag: tar.TypeReg}
+		if f.size == 0 {
+			hdr.Typeflag = tar.TypeDir
+		}
+		require.NoError(t, tw.WriteHeader(hdr))
+		_, err := tw.Write(make([]byte, f.size))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	if gzipped {
+		require.NoError(t, zw.Close())
+	}
+	return buf.Bytes()
+}
+
+func TestTar(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, Tar(&out, bytes.NewReader(testTar(t, false))))
+	if testing.Verbose() {
+		fmt.Print(out.String())
+	}
+	require.Equal(t, `      size  name
+      512B  docs/README
+    1.5MiB  data.bin
+    1.5MiB  total (2 files)
+`, out.String())
+
+	archive := testTar(t, true)
+	out.Reset()
+	require.NoError(t, Tar(&out, bytes.NewReader(archive)))
+	if testing.Verbose() {
+		fmt.Print(out.String())
+	}
+	require.Contains(t, out.String(), fmt.Sprintf("total (2 files, %s compressed)\n",
+		byteStr(uint64(len(archive)))))
+
+	out.Reset()
+	require.Error(t, Tar(&out, bytes.NewReader([]byte{0x1f, 0x8b, 0})))
+}
+
+func TestZip(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, f := range archiveFiles {
+		w, err := zw.Create(f.name)
+		require.NoError(t, err)
+		_, err = w.Write(make([]byte, f.size))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+
+	var out bytes.Buffer
+	require.NoError(t, Zip(&out, bytes.NewReader(buf.Bytes()), int64(buf.Len())))
+	if testing.Verbose() {
+		fmt.Print(out.String())
+	}
+	require.Contains(t, out.String(), "      size  compressed   saved  name\n")
+	require.Contains(t, out.String(), "  docs/README\n")
+	require.Contains(t, out.String(), "  total (2 files)\n")
+	require.NotContains(t, out.String(), "docs/\n")
+
+	require.Error(t, Zip(&out, bytes.NewReader([]byte("nope")), 4))
+}
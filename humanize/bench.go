@@ -0,0 +1,98 @@
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package humanize
+
+import (
+	"bufio"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// BenchOptions controls how Bench rewrites benchmark results.
+type BenchOptions struct {
+	// Benchstat leaves benchmark result lines exactly as they are, so the output can still be
+	// processed by benchstat and similar tools, and writes the humanized values on a separate,
+	// indented line following each result instead.
+	Benchstat bool
+}
+
+var benchMetric = regexp.MustCompile(`^(\s*)(\d+(?:\.\d+)?) (B/op|MB/s)$`)
+
+// Bench reads the output of "go test -bench" from r and writes it to w with the B/op and MB/s
+// measurements of each benchmark result in human form, e.g. "1048576 B/op" becomes "1MiB/op" and
+// "419.43 MB/s" becomes "400MiB/s". Note that MB/s as reported by the testing package means
+// 1,000,000 bytes per second. All other lines are copied unchanged. opts may be nil.
+func Bench(w io.Writer, r io.Reader, opts *BenchOptions) error {
+	if opts == nil {
+		opts = &BenchOptions{}
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<20)
+	bw := bufio.NewWriter(w)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "Benchmark") {
+			bw.WriteString(line)
+			bw.WriteByte('\n')
+			continue
+		}
+
+		fields := strings.Split(line, "\t")
+		var humanized []string
+		for i, field := range fields {
+			sub := benchMetric.FindStringSubmatch(field)
+			if sub == nil {
+				continue
+			}
+			val, err := strconv.ParseFloat(sub[2], 64)
+			if err != nil {
+				continue
+			}
+
+			var str string
+			if sub[3] == "B/op" {
+				str = byteStr(uint64(math.Round(val))) + "/op"
+			} else {
+				str = byteStr(uint64(math.Round(val*1e6))) + "/s"
+			}
+			humanized = append(humanized, str)
+
+			// Keep the column width so results remain aligned.
+			if pad := len(field) - len(str); pad > 0 {
+				str = strings.Repeat(" ", pad) + str
+			}
+			fields[i] = str
+		}
+
+		if !opts.Benchstat {
+			line = strings.Join(fields, "\t")
+		} else if len(humanized) > 0 {
+			line += "\n\t" + strings.Join(humanized, "\t")
+		}
+		bw.WriteString(line)
+		bw.WriteByte('\n')
+	}
+
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// byteStr is like sizeStr but always includes units, so small values read as "512B".
+func byteStr(n uint64) string {
+	str := sizeStr(n)
+	if isDigits(str) {
+		str += "B"
+	}
+	return str
+}
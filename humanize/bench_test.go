@@ -0,0 +1,57 @@
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package humanize
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const benchOutput = `goos: linux
+goarch: amd64
+pkg: example.com/copy
+BenchmarkSmall-8   	 1000000	      1052 ns/op	     512 B/op	       2 allocs/op
+BenchmarkCopy-8    	   50000	     25000 ns/op	 419.43 MB/s	 1048576 B/op	       1 allocs/op
+PASS
+ok  	example.com/copy	2.345s
+`
+
+func TestBench(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, Bench(&out, strings.NewReader(benchOutput), nil))
+	if testing.Verbose() {
+		fmt.Print(out.String())
+	}
+	require.Equal(t, `goos: linux
+goarch: amd64
+pkg: example.com/copy
+BenchmarkSmall-8   	 1000000	      1052 ns/op	      512B/op	       2 allocs/op
+BenchmarkCopy-8    	   50000	     25000 ns/op	    400MiB/s	      1MiB/op	       1 allocs/op
+PASS
+ok  	example.com/copy	2.345s
+`, out.String())
+
+	out.Reset()
+	require.NoError(t, Bench(&out, strings.NewReader(benchOutput), &BenchOptions{Benchstat: true}))
+	if testing.Verbose() {
+		fmt.Print(out.String())
+	}
+	require.Equal(t, `goos: linux
+goarch: amd64
+pkg: example.com/copy
+BenchmarkSmall-8   	 1000000	      1052 ns/op	     512 B/op	       2 allocs/op
+	512B/op
+BenchmarkCopy-8    	   50000	     25000 ns/op	 419.43 MB/s	 1048576 B/op	       1 allocs/op
+	400MiB/s	1MiB/op
+PASS
+ok  	example.com/copy	2.345s
+`, out.String())
+}
@@ -0,0 +1,193 @@
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package humanize
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"strconv"
+)
+
+// FieldOptions selects the fields rewritten by NDJSON and Logfmt. Both functions write each record
+// as soon as it is read, so they can process unbounded streams, like the output of "tail -f".
+type FieldOptions struct {
+	// Fields lists the names of the fields holding byte counts, like "bytes_sent" or "resp_size".
+	Fields []string
+
+	// Suffix, if not empty, leaves the selected fields unchanged and adds a companion field with
+	// the humanized value after each, named by appending Suffix to the field name. For example,
+	// with a suffix of "_human", "bytes_sent" is followed by "bytes_sent_human".
+	Suffix string
+}
+
+func (o *FieldOptions) selected(name string) bool {
+	for _, field := range o.Fields {
+		if field == name {
+			return true
+		}
+	}
+	return false
+}
+
+// NDJSON reads newline-delimited JSON records from r and writes them to w with the values of the
+// selected top-level fields replaced by, or accompanied by, strings with the sizes in human form,
+// e.g. {"bytes_sent":1048576} becomes {"bytes_sent":"1MiB"}. Only fields holding non-negative
+// whole numbers are rewritten. Everything else, including the formatting of the records and any
+// lines that are not JSON objects, is copied unchanged.
+func NDJSON(w io.Writer, r io.Reader, opts *FieldOptions) error {
+	return rewriteLines(w, r, opts, rewriteJSON)
+}
+
+// Logfmt reads logfmt records, lines of key=value pairs like those written by many structured
+// loggers, from r and writes them to w with the values of the selected keys replaced by, or
+// accompanied by, sizes in human form, e.g. "bytes_sent=1048576" becomes "bytes_sent=1MiB". Only
+// unquoted non-negative whole numbers are rewritten and everything else is copied unchanged.
+func Logfmt(w io.Writer, r io.Reader, opts *FieldOptions) error {
+	return rewriteLines(w, r, opts, rewriteLogfmt)
+}
+
+func rewriteLines(w io.Writer, r io.Reader, opts *FieldOptions,
+	rewrite func(line []byte, opts *FieldOptions) []byte) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<20)
+	bw := bufio.NewWriter(w)
+
+	for scanner.Scan() {
+		bw.Write(rewrite(scanner.Bytes(), opts))
+		bw.WriteByte('\n')
+		if err := bw.Flush(); err != nil {
+			return err
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// fieldStr returns the humanized value of a field, or false if value is not a byte count.
+func fieldStr(value []byte) (string, bool) {
+	if len(value) == 0 || !isDigits(string(value)) {
+		return "", false
+	}
+	n, err := strconv.ParseUint(string(value), 10, 64)
+	if err != nil {
+		return "", false
+	}
+	return byteStr(n), true
+}
+
+func rewriteJSON(line []byte, opts *FieldOptions) []byte {
+	dec := json.NewDecoder(bytes.NewReader(line))
+	dec.UseNumber()
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return line
+	}
+
+	var out []byte
+	var last int
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return line
+		}
+		key, _ := tok.(string)
+
+		start := int(dec.InputOffset())
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return line
+		}
+		end := int(dec.InputOffset())
+		if !opts.selected(key) {
+			continue
+		}
+		str, ok := fieldStr(value)
+		if !ok {
+			continue
+		}
+
+		// The offset after the key precedes the colon and any spaces around the value.
+		start += bytes.Index(line[start:end], value)
+		out = append(out, line[last:start]...)
+		if opts.Suffix == "" {
+			out = strconv.AppendQuote(out, str)
+		} else {
+			out = append(out, value...)
+			out = append(out, ',')
+			out = strconv.AppendQuote(out, key+opts.Suffix)
+			out = append(out, ':')
+			out = strconv.AppendQuote(out, str)
+		}
+		last = start + len(value)
+	}
+	if out == nil {
+		return line
+	}
+	return append(out, line[last:]...)
+}
+
+func rewriteLogfmt(line []byte, opts *FieldOptions) []byte {
+	var out []byte
+	var last int
+
+	for pos := 0; pos < len(line); {
+		for pos < len(line) && line[pos] == ' ' {
+			pos++
+		}
+		keyStart := pos
+		for pos < len(line) && line[pos] != '=' && line[pos] != ' ' {
+			pos++
+		}
+		key := string(line[keyStart:pos])
+		if pos == len(line) || line[pos] != '=' {
+			continue
+		}
+		pos++
+
+		valStart := pos
+		if pos < len(line) && line[pos] == '"' {
+			// Skip quoted values, which may contain spaces and escaped quotes.
+			for pos++; pos < len(line) && line[pos] != '"'; pos++ {
+				if line[pos] == '\\' {
+					pos++
+				}
+			}
+			pos++
+			continue
+		}
+		for pos < len(line) && line[pos] != ' ' {
+			pos++
+		}
+		if !opts.selected(key) {
+			continue
+		}
+		value := line[valStart:pos]
+		str, ok := fieldStr(value)
+		if !ok {
+			continue
+		}
+
+		out = append(out, line[last:valStart]...)
+		if opts.Suffix == "" {
+			out = append(out, str...)
+		} else {
+			out = append(out, value...)
+			out = append(out, ' ')
+			out = append(out, key+opts.Suffix+"="...)
+			out = append(out, str...)
+		}
+		last = pos
+	}
+	if out == nil {
+		return line
+	}
+	return append(out, line[last:]...)
+}
@@ -0,0 +1,68 @@
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package humanize
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNDJSON(t *testing.T) {
+	in := `{"path":"/a","bytes_sent":1048576,"resp_size": 512, "status":200}
+{"path":"/b","bytes_sent":"1048576","nested":{"bytes_sent":1024},"resp_size":-1}
+{"bytes_sent":1.5e6}
+not json
+[1048576]
+
+`
+	opts := &FieldOptions{Fields: []string{"bytes_sent", "resp_size"}}
+
+	var out bytes.Buffer
+	require.NoError(t, NDJSON(&out, strings.NewReader(in), opts))
+	if testing.Verbose() {
+		fmt.Print(out.String())
+	}
+	require.Equal(t, `{"path":"/a","bytes_sent":"1MiB","resp_size": "512B", "status":200}
+{"path":"/b","bytes_sent":"1048576","nested":{"bytes_sent":1024},"resp_size":-1}
+{"bytes_sent":1.5e6}
+not json
+[1048576]
+
+`, out.String())
+
+	opts.Suffix = "_human"
+	out.Reset()
+	require.NoError(t, NDJSON(&out, strings.NewReader(`{"bytes_sent":1048576,"status":200}`), opts))
+	require.Equal(t, `{"bytes_sent":1048576,"bytes_sent_human":"1MiB","status":200}`+"\n", out.String())
+}
+
+func TestLogfmt(t *testing.T) {
+	in := `level=info msg="sent 1048576 bytes" bytes_sent=1048576 resp_size=2000000 status=200
+bytes_sent="1048576" resp_size=- flag bytes_sent=
+msg="unterminated bytes_sent=1024
+`
+	opts := &FieldOptions{Fields: []string{"bytes_sent", "resp_size"}}
+
+	var out bytes.Buffer
+	require.NoError(t, Logfmt(&out, strings.NewReader(in), opts))
+	if testing.Verbose() {
+		fmt.Print(out.String())
+	}
+	require.Equal(t, `level=info msg="sent 1048576 bytes" bytes_sent=1MiB resp_size=2mb status=200
+bytes_sent="1048576" resp_size=- flag bytes_sent=
+msg="unterminated bytes_sent=1024
+`, out.String())
+
+	opts.Suffix = "_human"
+	out.Reset()
+	require.NoError(t, Logfmt(&out, strings.NewReader("bytes_sent=1536 status=200"), opts))
+	require.Equal(t, "bytes_sent=1536 bytes_sent_human=1.5KiB status=200\n", out.String())
+}
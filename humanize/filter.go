@@ -0,0 +1,149 @@
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package humanize
+
+import (
+	"bufio"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// FilterOptions controls which numbers Filter rewrites.
+type FilterOptions struct {
+	// Columns restricts rewriting to the given whitespace-separated columns, numbered from 1. If
+	// empty, all columns are rewritten.
+	Columns []int
+
+	// Match selects the numbers to rewrite within each column. If it has a capturing group, the
+	// text matched by the first group is rewritten; otherwise the whole match is. Matches that
+	// are not whole numbers are left unchanged. If nil, columns consisting only of digits are
+	// rewritten.
+	Match *regexp.Regexp
+}
+
+// Filter reads lines of text from r, such as the output of kubectl, ps, or du, and writes them to
+// w with byte counts in human form, e.g. "1048576" becomes "1MiB". Numbers below 1000 are left
+// as they are since they are already easily read. All other text is copied unchanged, except that
+// runs of spaces between columns are adjusted so that columns following a rewritten value stay
+// aligned. opts may be nil.
+func Filter(w io.Writer, r io.Reader, opts *FilterOptions) error {
+	if opts == nil {
+		opts = &FilterOptions{}
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<20)
+	bw := bufio.NewWriter(w)
+
+	for scanner.Scan() {
+		bw.WriteString(filterLine(scanner.Text(), opts))
+		bw.WriteByte('\n')
+	}
+
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+func filterLine(line string, opts *FilterOptions) string {
+	var out strings.Builder
+	var shift, col int
+
+	for pos := 0; pos < len(line); {
+		// Copy the spaces before the next column, absorbing any change in the width of previous
+		// columns. Tabs are left alone since they realign the text on their own, and so are
+		// single spaces, which separate words rather than align columns.
+		start := pos
+		for pos < len(line) && isSpace(line[pos]) {
+			pos++
+		}
+		gap := line[start:pos]
+		if pos == len(line) {
+			out.WriteString(gap)
+			break
+		}
+		if len(gap) == 1 || strings.Contains(gap, "\t") {
+			shift = 0
+		} else if col > 0 {
+			n := len(gap) - shift
+			if n < 1 {
+				n = 1
+			}
+			shift -= len(gap) - n
+			gap = strings.Repeat(" ", n)
+		}
+		out.WriteString(gap)
+
+		start = pos
+		for pos < len(line) && !isSpace(line[pos]) {
+			pos++
+		}
+		col++
+		field := line[start:pos]
+		if selected(col, opts.Columns) {
+			str := filterField(field, opts.Match)
+			shift += len(str) - len(field)
+			field = str
+		}
+		out.WriteString(field)
+	}
+	return out.String()
+}
+
+func filterField(field string, match *regexp.Regexp) string {
+	if match == nil {
+		return numberStr(field)
+	}
+
+	var out strings.Builder
+	var last int
+	for _, loc := range match.FindAllStringSubmatchIndex(field, -1) {
+		start, end := loc[0], loc[1]
+		if len(loc) > 2 {
+			start, end = loc[2], loc[3]
+		}
+		if start < last {
+			continue
+		}
+		out.WriteString(field[last:start])
+		out.WriteString(numberStr(field[start:end]))
+		last = end
+	}
+	out.WriteString(field[last:])
+	return out.String()
+}
+
+// numberStr returns sizeStr of the number in str, or str if it is not a whole number.
+func numberStr(str string) string {
+	if str == "" || !isDigits(str) {
+		return str
+	}
+	n, err := strconv.ParseUint(str, 10, 64)
+	if err != nil {
+		return str
+	}
+	return sizeStr(n)
+}
+
+func selected(col int, columns []int) bool {
+	if len(columns) == 0 {
+		return true
+	}
+	for _, c := range columns {
+		if c == col {
+			return true
+		}
+	}
+	return false
+}
+
+func isSpace(b byte) bool {
+	return b == ' ' || b == '\t'
+}
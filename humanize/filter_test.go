@@ -0,0 +1,49 @@
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package humanize
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilter(t *testing.T) {
+	tests := []struct {
+		in   string
+		opts *FilterOptions
+		out  string
+	}{
+		{"1048576", nil, "1MiB"},
+		{"512 1536 v2 10.0.0.1", nil, "512 1.5KiB v2 10.0.0.1"},
+		{"  4194304  rss\t2000000", nil, "  4MiB     rss\t2mb"},
+		{"NAME    MEMORY    CPU", nil, "NAME    MEMORY    CPU"},
+		{"web-1   16777216  250", nil, "web-1   16MiB     250"},
+		{"web-1   1048577   250", nil, "web-1   1MiB      250"},
+		{"a 1536 b", nil, "a 1.5KiB b"},
+		{"1536      b", nil, "1.5KiB    b"},
+		{"3 1536 4096 5", &FilterOptions{Columns: []int{2, 4}}, "3 1.5KiB 4096 5"},
+		{"size=2097152 count=4096", &FilterOptions{Match: regexp.MustCompile(`size=(\d+)`)},
+			"size=2MiB count=4096"},
+		{"a=4096,b=8192", &FilterOptions{Match: regexp.MustCompile(`\d+`)}, "a=4KiB,b=8KiB"},
+		{"1024 99999999999999999999", nil, "1KiB 99999999999999999999"},
+		{"", nil, ""},
+	}
+
+	for _, test := range tests {
+		var out bytes.Buffer
+		require.NoError(t, Filter(&out, strings.NewReader(test.in+"\n"), test.opts))
+		if testing.Verbose() {
+			fmt.Printf("%q -> %q\n", test.in, out.String())
+		}
+		require.Equal(t, test.out+"\n", out.String(), test.in)
+	}
+}
@@ -0,0 +1,63 @@
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+// Package humanize rewrites the byte counts found in the output of other tools, like profilers
+// and benchmarks, into human-friendly sizes using the conventions of package bytez.
+package humanize
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/nexvium/bytez"
+)
+
+var approxUnits = []string{"", "KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}
+
+// sizeStr returns n formatted by bytez.AsCanonicalStr when that results in an exact value in the largest
+// units, like "1.5MiB", and an approximation in binary units with up to two decimals otherwise.
+// Sizes that are not whole multiples of some unit are common in tool output, and a close
+// approximation is more useful to a human reader than the exact number of bytes or a long number
+// in smaller units.
+func sizeStr(n uint64) string {
+	str := bytez.AsCanonicalStr(n)
+	if n < 1000 {
+		return str
+	} else if units := strings.TrimLeft(str, "0123456789."); units != str && units != "" {
+		if num, err := strconv.ParseFloat(str[:len(str)-len(units)], 64); err == nil && num < 1000 {
+			if val, err := bytez.AsInt(str); err == nil && val == n {
+				return str
+			}
+		}
+	}
+
+	val := float64(n)
+	var idx int
+	for val >= 1024 && idx < len(approxUnits)-1 {
+		val /= 1024
+		idx++
+	}
+
+	val = math.Round(val*100) / 100
+	if val >= 1024 && idx < len(approxUnits)-1 {
+		val /= 1024
+		idx++
+	}
+
+	str = strconv.FormatFloat(val, 'f', 2, 64)
+	str = strings.TrimRight(strings.TrimRight(str, "0"), ".")
+	return str + approxUnits[idx]
+}
+
+func isDigits(str string) bool {
+	for i := 0; i < len(str); i++ {
+		if str[i] < '0' || str[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,40 @@
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package humanize
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSizeStr(t *testing.T) {
+	var tests = []struct {
+		in  uint64
+		out string
+	}{
+		{0, "0"},
+		{999, "999"},
+		{1000, "1kb"},
+		{1001, "1001"},
+		{1536, "1.5KiB"},
+		{524339, "512.05KiB"},
+		{1573038, "1.5MiB"},
+		{1048575, "1MiB"},
+		{419430000, "400MiB"},
+		{314159265359, "292.58GiB"},
+	}
+
+	for _, test := range tests {
+		out := sizeStr(test.in)
+		if testing.Verbose() {
+			fmt.Printf("%v --> %v\n", test.in, out)
+		}
+		require.Equal(t, test.out, out)
+	}
+}
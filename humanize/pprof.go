@@ -0,0 +1,220 @@
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package humanize
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// PprofOptions controls the report written by Pprof for profiles in protobuf format.
+type PprofOptions struct {
+	// SampleType selects the sample type to report, like "alloc_space" or "inuse_space". If
+	// empty, the default sample type of the profile is used.
+	SampleType string
+
+	// NodeCount limits the report to the functions with the largest flat values. If zero, all
+	// functions are reported.
+	NodeCount int
+}
+
+// pprof labels memory values using base 2 multiples but SI-looking units, so "512kB" in its
+// output is 524288 bytes.
+var pprofValue = regexp.MustCompile(`\b(\d+(?:\.\d+)?)(B|kB|MB|GB|TB|PB)\b`)
+var pprofUnits = map[string]float64{
+	"B": 1, "kB": 1 << 10, "MB": 1 << 20, "GB": 1 << 30, "TB": 1 << 40, "PB": 1 << 50,
+}
+
+// Pprof reads a profile from r and writes it to w with byte values in human form.
+//
+// The input can be a profile in protobuf format, gzipped or not, as written by runtime/pprof or
+// served by net/http/pprof. In that case a report similar to that of "go tool pprof -top" is
+// written, listing the flat and cumulative values of each function. Otherwise the input is
+// assumed to be the text output of "go tool pprof" and is rewritten as by PprofText. opts may be
+// nil.
+func Pprof(w io.Writer, r io.Reader, opts *PprofOptions) error {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	if len(data) > 2 && data[0] == 0x1f && data[1] == 0x8b {
+		zr, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return err
+		}
+		if data, err = ioutil.ReadAll(zr); err != nil {
+			return err
+		}
+		prof, err := decodeProfile(data)
+		if err != nil {
+			return err
+		}
+		return writePprofReport(w, prof, opts)
+	}
+
+	if prof, err := decodeProfile(data); err == nil {
+		return writePprofReport(w, prof, opts)
+	}
+	return PprofText(w, bytes.NewReader(data))
+}
+
+// PprofText rewrites the text output of "go tool pprof", such as that of the -text, -top, -tree,
+// and -peek reports, replacing each memory value with its bytez equivalent. For example,
+// "1536.17kB" becomes "1.5MiB" and "512kB" becomes "512KiB". All other text is left unchanged.
+func PprofText(w io.Writer, r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<20)
+	bw := bufio.NewWriter(w)
+
+	for scanner.Scan() {
+		line := pprofValue.ReplaceAllStringFunc(scanner.Text(), func(match string) string {
+			sub := pprofValue.FindStringSubmatch(match)
+			val, err := strconv.ParseFloat(sub[1], 64)
+			if err != nil {
+				return match
+			}
+			return sizeStr(uint64(math.Round(val * pprofUnits[sub[2]])))
+		})
+		bw.WriteString(line)
+		bw.WriteByte('\n')
+	}
+
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+type pprofNode struct {
+	name      string
+	flat, cum int64
+}
+
+func writePprofReport(w io.Writer, prof *profile, opts *PprofOptions) error {
+	if opts == nil {
+		opts = &PprofOptions{}
+	}
+
+	idx := len(prof.sampleTypes) - 1
+	if opts.SampleType != "" {
+		idx = -1
+		for i, st := range prof.sampleTypes {
+			if prof.str(st.typ) == opts.SampleType {
+				idx = i
+				break
+			}
+		}
+		if idx < 0 {
+			return fmt.Errorf("sample type %q not found in profile", opts.SampleType)
+		}
+	} else if prof.defaultSampleType != 0 {
+		for i, st := range prof.sampleTypes {
+			if st.typ == prof.defaultSampleType {
+				idx = i
+				break
+			}
+		}
+	}
+	sampleType := prof.sampleTypes[idx]
+
+	var total int64
+	nodes := map[string]*pprofNode{}
+	for _, s := range prof.samples {
+		if idx >= len(s.values) {
+			continue
+		}
+		val := s.values[idx]
+		total += val
+
+		seen := map[string]bool{}
+		for i, locID := range s.locationIDs {
+			for j, fnID := range prof.locations[locID] {
+				name := prof.str(prof.functions[fnID])
+				node := nodes[name]
+				if node == nil {
+					node = &pprofNode{name: name}
+					nodes[name] = node
+				}
+				if i == 0 && j == 0 {
+					node.flat += val
+				}
+				if !seen[name] {
+					node.cum += val
+					seen[name] = true
+				}
+			}
+		}
+	}
+
+	list := make([]*pprofNode, 0, len(nodes))
+	for _, node := range nodes {
+		list = append(list, node)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].flat != list[j].flat {
+			return abs(list[i].flat) > abs(list[j].flat)
+		}
+		if list[i].cum != list[j].cum {
+			return abs(list[i].cum) > abs(list[j].cum)
+		}
+		return list[i].name < list[j].name
+	})
+	if opts.NodeCount > 0 && len(list) > opts.NodeCount {
+		list = list[:opts.NodeCount]
+	}
+
+	isBytes := prof.str(sampleType.unit) == "bytes"
+	value := func(v int64) string {
+		if !isBytes {
+			return strconv.FormatInt(v, 10)
+		} else if v < 0 {
+			return "-" + sizeStr(uint64(-v))
+		}
+		return sizeStr(uint64(v))
+	}
+	percent := func(v int64) string {
+		if total == 0 {
+			return "0%"
+		}
+		return strconv.FormatFloat(100*float64(v)/float64(total), 'f', 2, 64) + "%"
+	}
+
+	var shown int64
+	for _, node := range list {
+		shown += node.flat
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "Type: %s\n", prof.str(sampleType.typ))
+	fmt.Fprintf(bw, "Showing nodes accounting for %s, %s of %s total\n",
+		value(shown), percent(shown), value(total))
+	fmt.Fprintf(bw, "%10s %7s %7s %10s %7s\n", "flat", "flat%", "sum%", "cum", "cum%")
+
+	var sum int64
+	for _, node := range list {
+		sum += node.flat
+		fmt.Fprintf(bw, "%10s %7s %7s %10s %7s  %s\n", value(node.flat), percent(node.flat),
+			percent(sum), value(node.cum), percent(node.cum), node.name)
+	}
+	return bw.Flush()
+}
+
+func abs(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
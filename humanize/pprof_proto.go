@@ -0,0 +1,221 @@
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package humanize
+
+import (
+	"errors"
+)
+
+// This file contains a minimal decoder for the protobuf encoding of pprof profiles, as defined by
+// https://github.com/google/pprof/blob/main/proto/profile.proto. Only the fields needed to build
+// a flat/cumulative report by function are decoded; everything else is skipped.
+
+type profile struct {
+	sampleTypes       []valueType
+	samples           []sample
+	locations         map[uint64][]uint64 // location ID -> function IDs, innermost first
+	functions         map[uint64]int64    // function ID -> name string index
+	strings           []string
+	defaultSampleType int64
+}
+
+type valueType struct {
+	typ  int64
+	unit int64
+}
+
+type sample struct {
+	locationIDs []uint64
+	values      []int64
+}
+
+const (
+	wireVarint = 0
+	wire64Bit  = 1
+	wireBytes  = 2
+	wire32Bit  = 5
+)
+
+var errBadProto = errors.New("malformed profile")
+
+// decodeVarint returns the varint at the start of buf and its length in bytes.
+func decodeVarint(buf []byte) (uint64, int, error) {
+	var val uint64
+	for i := 0; i < len(buf) && i < 10; i++ {
+		val |= uint64(buf[i]&0x7f) << (7 * uint(i))
+		if buf[i] < 0x80 {
+			return val, i + 1, nil
+		}
+	}
+	return 0, 0, errBadProto
+}
+
+// decodeMessage calls fn for every field of the message in buf. For varint fields, val holds the
+// value; for length-delimited fields, data holds the payload. Fixed-size fields are skipped.
+func decodeMessage(buf []byte, fn func(field int, wire int, val uint64, data []byte) error) error {
+	for len(buf) > 0 {
+		key, n, err := decodeVarint(buf)
+		if err != nil {
+			return err
+		}
+		buf = buf[n:]
+
+		field, wire := int(key>>3), int(key&7)
+		var val uint64
+		var data []byte
+
+		switch wire {
+		case wireVarint:
+			if val, n, err = decodeVarint(buf); err != nil {
+				return err
+			}
+		case wireBytes:
+			var size uint64
+			if size, n, err = decodeVarint(buf); err != nil {
+				return err
+			}
+			if size > uint64(len(buf)-n) {
+				return errBadProto
+			}
+			data = buf[n : n+int(size)]
+			n += int(size)
+		case wire64Bit:
+			n = 8
+		case wire32Bit:
+			n = 4
+		default:
+			return errBadProto
+		}
+
+		if n > len(buf) {
+			return errBadProto
+		}
+		buf = buf[n:]
+
+		if wire == wireVarint || wire == wireBytes {
+			if err = fn(field, wire, val, data); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// decodeRepeated appends to dst the values of a repeated integer field, which may be encoded
+// either packed (a single length-delimited field) or as one varint field per value.
+func decodeRepeated(dst []uint64, wire int, val uint64, data []byte) ([]uint64, error) {
+	if wire == wireVarint {
+		return append(dst, val), nil
+	}
+	for len(data) > 0 {
+		v, n, err := decodeVarint(data)
+		if err != nil {
+			return nil, err
+		}
+		dst = append(dst, v)
+		data = data[n:]
+	}
+	return dst, nil
+}
+
+func decodeProfile(buf []byte) (*profile, error) {
+	p := &profile{locations: map[uint64][]uint64{}, functions: map[uint64]int64{}}
+
+	err := decodeMessage(buf, func(field int, wire int, val uint64, data []byte) error {
+		switch field {
+		case 1:
+			var vt valueType
+			err := decodeMessage(data, func(field int, wire int, val uint64, data []byte) error {
+				switch field {
+				case 1:
+					vt.typ = int64(val)
+				case 2:
+					vt.unit = int64(val)
+				}
+				return nil
+			})
+			p.sampleTypes = append(p.sampleTypes, vt)
+			return err
+		case 2:
+			var s sample
+			var values []uint64
+			err := decodeMessage(data, func(field int, wire int, val uint64, data []byte) error {
+				var err error
+				switch field {
+				case 1:
+					s.locationIDs, err = decodeRepeated(s.locationIDs, wire, val, data)
+				case 2:
+					values, err = decodeRepeated(values, wire, val, data)
+				}
+				return err
+			})
+			for _, v := range values {
+				s.values = append(s.values, int64(v))
+			}
+			p.samples = append(p.samples, s)
+			return err
+		case 4:
+			var id uint64
+			var funcs []uint64
+			err := decodeMessage(data, func(field int, wire int, val uint64, data []byte) error {
+				switch field {
+				case 1:
+					id = val
+				case 4:
+					return decodeMessage(data, func(field int, wire int, val uint64, data []byte) error {
+						if field == 1 {
+							funcs = append(funcs, val)
+						}
+						return nil
+					})
+				}
+				return nil
+			})
+			p.locations[id] = funcs
+			return err
+		case 5:
+			var id uint64
+			var name int64
+			err := decodeMessage(data, func(field int, wire int, val uint64, data []byte) error {
+				switch field {
+				case 1:
+					id = val
+				case 2:
+					name = int64(val)
+				}
+				return nil
+			})
+			p.functions[id] = name
+			return err
+		case 6:
+			if wire != wireBytes {
+				return errBadProto
+			}
+			p.strings = append(p.strings, string(data))
+		case 14:
+			p.defaultSampleType = int64(val)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The first entry of a valid string table is always the empty string.
+	if len(p.strings) == 0 || p.strings[0] != "" || len(p.sampleTypes) == 0 {
+		return nil, errBadProto
+	}
+	return p, nil
+}
+
+// str returns the string table entry at idx, or "" if idx is out of range.
+func (p *profile) str(idx int64) string {
+	if idx < 0 || idx >= int64(len(p.strings)) {
+		return ""
+	}
+	return p.strings[idx]
+}
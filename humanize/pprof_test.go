@@ -0,0 +1,113 @@
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package humanize
+
+import (
+	"bytes"
+	"fmt"
+	"runtime/pprof"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// The helpers below encode just enough protobuf to build test profiles.
+
+func appendVarint(buf []byte, v uint64) []byte {
+	for v >= 0x80 {
+		buf = append(buf, byte(v)|0x80)
+		v >>= 7
+	}
+	return append(buf, byte(v))
+}
+
+func appendVarintField(buf []byte, field int, v uint64) []byte {
+	return appendVarint(appendVarint(buf, uint64(field)<<3|wireVarint), v)
+}
+
+func appendBytesField(buf []byte, field int, data []byte) []byte {
+	buf = appendVarint(appendVarint(buf, uint64(field)<<3|wireBytes), uint64(len(data)))
+	return append(buf, data...)
+}
+
+func testProfile() []byte {
+	// String table: 0 "", 1 "alloc_space", 2 "bytes", 3 "main.main", 4 "main.alloc", 5 "count"
+	var buf []byte
+
+	vt := appendVarintField(appendVarintField(nil, 1, 5), 2, 5)
+	buf = appendBytesField(buf, 1, vt)
+	vt = appendVarintField(appendVarintField(nil, 1, 1), 2, 2)
+	buf = appendBytesField(buf, 1, vt)
+
+	// main.alloc called from main.main allocates 1.5MiB; main.main itself allocates 512KiB.
+	var packed []byte
+	packed = appendVarint(appendVarint(packed, 2), 1)
+	vals := appendVarint(appendVarint(nil, 3), 1572864)
+	buf = appendBytesField(buf, 2, appendBytesField(appendBytesField(nil, 1, packed), 2, vals))
+	vals = appendVarint(appendVarint(nil, 1), 524288)
+	buf = appendBytesField(buf, 2, appendBytesField(appendVarintField(nil, 1, 1), 2, vals))
+
+	for id, fn := range []uint64{3, 4} {
+		line := appendVarintField(nil, 1, uint64(id+1))
+		buf = appendBytesField(buf, 4, appendBytesField(appendVarintField(nil, 1, uint64(id+1)), 4, line))
+		buf = appendBytesField(buf, 5, appendVarintField(appendVarintField(nil, 1, uint64(id+1)), 2, fn))
+	}
+
+	for _, str := range []string{"", "alloc_space", "bytes", "main.main", "main.alloc", "count"} {
+		buf = appendBytesField(buf, 6, []byte(str))
+	}
+	return appendVarintField(buf, 14, 1)
+}
+
+func TestPprofProfile(t *testing.T) {
+	var out bytes.Buffer
+	err := Pprof(&out, bytes.NewReader(testProfile()), nil)
+	if testing.Verbose() {
+		fmt.Print(out.String())
+	}
+	require.NoError(t, err)
+	require.Equal(t, `Type: alloc_space
+Showing nodes accounting for 2MiB, 100.00% of 2MiB total
+      flat   flat%    sum%        cum    cum%
+    1.5MiB  75.00%  75.00%     1.5MiB  75.00%  main.alloc
+    512KiB  25.00% 100.00%       2MiB 100.00%  main.main
+`, out.String())
+
+	out.Reset()
+	err = Pprof(&out, bytes.NewReader(testProfile()), &PprofOptions{SampleType: "nope"})
+	require.Error(t, err)
+
+	// A real profile, gzipped, as written by the runtime.
+	var heap bytes.Buffer
+	require.NoError(t, pprof.Lookup("heap").WriteTo(&heap, 0))
+	out.Reset()
+	require.NoError(t, Pprof(&out, &heap, &PprofOptions{SampleType: "alloc_space", NodeCount: 5}))
+	require.True(t, strings.HasPrefix(out.String(), "Type: alloc_space\n"))
+}
+
+func TestPprofText(t *testing.T) {
+	in := `Type: inuse_space
+Showing nodes accounting for 1536.17kB, 100% of 1536.17kB total
+      flat  flat%   sum%        cum   cum%
+  512.05kB 33.33% 33.33%   512.05kB 33.33%  runtime.allocm
+     512kB 33.33% 66.67%      512kB 33.33%  main.main
+         0     0%   100%   512.05kB 33.33%  runtime.main
+`
+	var out bytes.Buffer
+	require.NoError(t, Pprof(&out, strings.NewReader(in), nil))
+	if testing.Verbose() {
+		fmt.Print(out.String())
+	}
+	require.Equal(t, `Type: inuse_space
+Showing nodes accounting for 1.5MiB, 100% of 1.5MiB total
+      flat  flat%   sum%        cum   cum%
+  512.05KiB 33.33% 33.33%   512.05KiB 33.33%  runtime.allocm
+     512KiB 33.33% 66.67%      512KiB 33.33%  main.main
+         0     0%   100%   512.05KiB 33.33%  runtime.main
+`, out.String())
+}
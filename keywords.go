@@ -0,0 +1,53 @@
+//go:build !bytez_minimal
+
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package bytez
+
+import (
+	"strings"
+)
+
+// Unlimited is the largest Size, used by WithKeywords for "unlimited" unless another value is
+// given.
+const Unlimited Size = 1<<64 - 1
+
+// WithKeywords accepts the keywords of quota-style configurations in any case: "unlimited" and
+// "max" for the given size, which is typically Unlimited, and "none" for zero. Keywords are not
+// subject to WithMax, so that a parser can accept "unlimited" while bounding explicit sizes.
+func WithKeywords(unlimited Size) Option {
+	return func(p *Parser) error {
+		p.unlimited, p.keywords = unlimited, true
+		return nil
+	}
+}
+
+// lookupKeyword returns the size named by the keyword str, if it is one.
+func lookupKeyword(str string, unlimited Size) (Size, bool) {
+	switch {
+	case strings.EqualFold(str, "unlimited"), strings.EqualFold(str, "max"):
+		return unlimited, true
+	case strings.EqualFold(str, "none"):
+		return 0, true
+	}
+	return 0, false
+}
+
+// appendKeyword appends the keyword for size to dst, if size has one according to opts.
+func appendKeyword(dst []byte, size Size, opts FormatOptions) ([]byte, bool) {
+	unlimited := opts.Unlimited
+	if unlimited == 0 {
+		unlimited = Unlimited
+	}
+	switch size {
+	case 0:
+		return append(dst, "none"...), true
+	case unlimited:
+		return append(dst, "unlimited"...), true
+	}
+	return dst, false
+}
@@ -0,0 +1,88 @@
+//go:build !bytez_minimal
+
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package bytez
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeywords(t *testing.T) {
+	var tests = []struct {
+		opts []Option
+		in   string
+		out  Size
+		err  bool
+	}{
+		{[]Option{WithKeywords(Unlimited)}, "unlimited", Unlimited, false},
+		{[]Option{WithKeywords(Unlimited)}, " Unlimited ", Unlimited, false},
+		{[]Option{WithKeywords(Unlimited)}, "MAX", Unlimited, false},
+		{[]Option{WithKeywords(Unlimited)}, "none", 0, false},
+		{[]Option{WithKeywords(Unlimited)}, "0", 0, false},
+		{[]Option{WithKeywords(Unlimited)}, "4GiB", Size(4 * Gibibyte), false},
+		{[]Option{WithKeywords(Unlimited)}, "infinity", 0, true},
+		{[]Option{WithKeywords(Unlimited)}, "unlimited GiB", 0, true},
+		{[]Option{WithKeywords(Size(Tebibyte))}, "max", Size(Tebibyte), false},
+		{[]Option{WithKeywords(Unlimited), WithMax(Size(Gibibyte))}, "unlimited", Unlimited, false},
+		{[]Option{WithKeywords(Unlimited), WithMax(Size(Gibibyte))}, "2GiB", 0, true},
+		{[]Option{WithKeywords(Unlimited), WithStrict()}, " none", 0, true},
+		{nil, "unlimited", 0, true},
+		{nil, "none", 0, true},
+	}
+
+	for _, test := range tests {
+		out, err := ParseSize(test.in, test.opts...)
+		if testing.Verbose() {
+			fmt.Printf("%q --> %v, %v\n", test.in, out, err)
+		}
+		if test.err {
+			require.Error(t, err, test.in)
+		} else {
+			require.NoError(t, err, test.in)
+			require.Equal(t, test.out, out, test.in)
+		}
+	}
+}
+
+func TestFormatKeywords(t *testing.T) {
+	var tests = []struct {
+		opts FormatOptions
+		in   Size
+		out  string
+	}{
+		{FormatOptions{Keywords: true}, 0, "none"},
+		{FormatOptions{Keywords: true}, Unlimited, "unlimited"},
+		{FormatOptions{Keywords: true}, Size(4 * Gibibyte), "4GiB"},
+		{FormatOptions{Keywords: true, Unlimited: Size(Tebibyte)}, Size(Tebibyte), "unlimited"},
+		{FormatOptions{Keywords: true, Unlimited: Size(Tebibyte)}, Unlimited, "18446744073709551615"},
+		{FormatOptions{}, 0, "0"},
+		{FormatOptions{}, Unlimited, "18446744073709551615"},
+	}
+
+	for _, test := range tests {
+		out := NewFormatter(test.opts).Format(test.in)
+		if testing.Verbose() {
+			fmt.Printf("%+v %v --> %v\n", test.opts, uint64(test.in), out)
+		}
+		require.Equal(t, test.out, out)
+
+		// Formatted keywords parse back to the same size.
+		unlimited := test.opts.Unlimited
+		if unlimited == 0 {
+			unlimited = Unlimited
+		}
+		if test.opts.Keywords {
+			size, err := ParseSize(out, WithKeywords(unlimited))
+			require.NoError(t, err, out)
+			require.Equal(t, test.in, size, out)
+		}
+	}
+}
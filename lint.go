@@ -0,0 +1,116 @@
+//go:build !bytez_minimal
+
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package bytez
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// WarningKind classifies the warnings of Lint.
+type WarningKind int
+
+const (
+	// WarnInvalid is for sizes that AsInt rejects.
+	WarnInvalid WarningKind = iota
+
+	// WarnAmbiguous is for units whose base depends on a convention, like "KB" and "kb", which
+	// this package reads as powers of 2 and 10 by the case of their first letter but which others
+	// may read differently.
+	WarnAmbiguous
+
+	// WarnNonstandard is for binary units other than the standard ones, like "Ki" and "KIB",
+	// which are unambiguous but deprecated in favor of "KiB".
+	WarnNonstandard
+
+	// WarnLossy is for sizes that are not a whole number of bytes, like "1.1KiB", which are
+	// rounded.
+	WarnLossy
+)
+
+// String returns the name of the kind, like "ambiguous".
+func (k WarningKind) String() string {
+	switch k {
+	case WarnInvalid:
+		return "invalid"
+	case WarnAmbiguous:
+		return "ambiguous"
+	case WarnNonstandard:
+		return "nonstandard"
+	case WarnLossy:
+		return "lossy"
+	}
+	return "WarningKind(" + strconv.Itoa(int(k)) + ")"
+}
+
+// A Warning is a problem with the notation of a size found by Lint.
+type Warning struct {
+	Kind WarningKind
+
+	// Offset is the byte offset in the linted text of the number or units with the problem.
+	Offset int
+
+	// Message explains the problem, like `ambiguous units "KB": use "KiB" for powers of 2 or
+	// "kB" for powers of 10`.
+	Message string
+}
+
+// Lint returns warnings about the notation of a size in the syntax accepted by AsInt, for
+// configuration validation tools: units that are ambiguous or nonstandard, which are rejected by
+// WithStrictUnits, and fractions of a byte that are rounded. If the size cannot be parsed, the
+// only warning is of kind WarnInvalid. Sizes without problems have no warnings.
+func Lint(str string) []Warning {
+	val, offset, err := asInt(str)
+	if err != nil {
+		return []Warning{{Kind: WarnInvalid, Offset: offset,
+			Message: newParseError(str, offset, err).Error()}}
+	}
+
+	trimmed := strings.TrimLeft(str, " \t\r\n")
+	lead := len(str) - len(trimmed)
+	trimmed = strings.TrimRight(trimmed, " \t\r\n")
+	num, idx, _ := scanNumber(trimmed, ',', '.')
+	if idx < len(trimmed) && trimmed[idx] == ' ' {
+		idx++
+	}
+	units := trimmed[idx:]
+	if units == "" {
+		return nil
+	}
+
+	var warnings []Warning
+	unit, _ := lookupUnit(units)
+	var ambiguous string
+	if err, ok := siErrors[units]; ok && explicitBase(units) {
+		warnings = append(warnings, Warning{Kind: WarnNonstandard, Offset: lead + idx,
+			Message: err.Error()})
+	} else if ok {
+		ambiguous = err.Error()
+	} else if si, ok := siUnits[units]; ok && si != unit {
+		// Units like "MB" are valid SI units, but are read as powers of 2 without
+		// WithStrictUnits.
+		ambiguous = fmt.Sprintf("ambiguous units %q: use %q for powers of 2 or %q for powers of 10",
+			units, units[:1]+"iB", units)
+	}
+	if ambiguous != "" {
+		powers := "; read here as powers of 10"
+		if baseOf(unit) == Base2 {
+			powers = "; read here as powers of 2"
+		}
+		warnings = append(warnings, Warning{Kind: WarnAmbiguous, Offset: lead + idx,
+			Message: ambiguous + powers})
+	}
+	if !num.exact(unit) {
+		warnings = append(warnings, Warning{Kind: WarnLossy, Offset: lead,
+			Message: strconv.Quote(trimmed) + " is not a whole number of bytes: rounded to " +
+				strconv.FormatUint(val, 10)})
+	}
+	return warnings
+}
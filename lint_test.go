@@ -0,0 +1,64 @@
+//go:build !bytez_minimal
+
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package bytez
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLint(t *testing.T) {
+	var tests = []struct {
+		in       string
+		warnings []Warning
+	}{
+		{"4KiB", nil},
+		{"4 kB", nil},
+		{"1.5 GiB", nil},
+		{"4096", nil},
+		{"2 gigabytes", nil},
+		{"100Mbit", nil},
+		{"4KB", []Warning{{WarnAmbiguous, 1,
+			`ambiguous units "KB": use "KiB" for powers of 2 or "kB" for powers of 10; ` +
+				`read here as powers of 2`}}},
+		{" 4 k", []Warning{{WarnAmbiguous, 3,
+			`ambiguous units "k": use "KiB" for powers of 2 or "kB" for powers of 10; ` +
+				`read here as powers of 10`}}},
+		{"4MB", []Warning{{WarnAmbiguous, 1,
+			`ambiguous units "MB": use "MiB" for powers of 2 or "MB" for powers of 10; ` +
+				`read here as powers of 2`}}},
+		{"1GB", []Warning{{WarnAmbiguous, 1,
+			`ambiguous units "GB": use "GiB" for powers of 2 or "GB" for powers of 10; ` +
+				`read here as powers of 2`}}},
+		{"4Mi", []Warning{{WarnNonstandard, 1, `nonstandard units "Mi": use "MiB"`}}},
+		{"1.1KiB", []Warning{{WarnLossy, 0,
+			`"1.1KiB" is not a whole number of bytes: rounded to 1126`}}},
+		{"1.1K", []Warning{
+			{WarnAmbiguous, 3,
+				`ambiguous units "K": use "KiB" for powers of 2 or "kB" for powers of 10; ` +
+					`read here as powers of 2`},
+			{WarnLossy, 0, `"1.1K" is not a whole number of bytes: rounded to 1126`},
+		}},
+		{"4MiBs", []Warning{{WarnInvalid, 1, `invalid units: did you mean "MiB"?`}}},
+		{"", []Warning{{WarnInvalid, 0, "no number in string"}}},
+	}
+
+	for _, test := range tests {
+		warnings := Lint(test.in)
+		if testing.Verbose() {
+			fmt.Printf("%q --> %v\n", test.in, warnings)
+		}
+		require.Equal(t, test.warnings, warnings, test.in)
+	}
+
+	require.Equal(t, "ambiguous", WarnAmbiguous.String())
+	require.Equal(t, "WarningKind(9)", WarningKind(9).String())
+}
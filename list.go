@@ -0,0 +1,57 @@
+//go:build !bytez_minimal
+
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package bytez
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseList parses a comma-separated list of sizes, like "64KiB, 1MiB, 16MiB", as used by flags
+// and configuration settings for tiered buffer sizes or bucket boundaries. Each size has the
+// syntax accepted by AsInt, except that commas cannot separate digit groups; a comma followed by
+// exactly three digits after a digit, as in "1,000", is rejected as ambiguous. An empty string
+// results in an empty list.
+func ParseList(str string) ([]Size, error) {
+	if strings.TrimSpace(str) == "" {
+		return []Size{}, nil
+	}
+
+	items := strings.Split(str, ",")
+	sizes := make([]Size, len(items))
+	for i, item := range items {
+		if i > 0 && isDigitGroup(items[i-1], item) {
+			return nil, fmt.Errorf("item %d: ambiguous comma in %q: digit groups are not allowed "+
+				"in lists", i+1, items[i-1]+","+item)
+		}
+		if strings.TrimSpace(item) == "" {
+			return nil, fmt.Errorf("item %d: empty size", i+1)
+		}
+		val, err := AsInt(item)
+		if err != nil {
+			return nil, fmt.Errorf("item %d: %q: %v", i+1, strings.TrimSpace(item), err)
+		}
+		sizes[i] = Size(val)
+	}
+	return sizes, nil
+}
+
+// isDigitGroup reports whether a comma between prev and next looks like a digit group
+// separator: prev ends with a digit and next starts with exactly three digits.
+func isDigitGroup(prev, next string) bool {
+	if prev == "" || !isDigit(prev[len(prev)-1]) || len(next) < 3 {
+		return false
+	}
+	for i := 0; i < 3; i++ {
+		if !isDigit(next[i]) {
+			return false
+		}
+	}
+	return len(next) == 3 || !isDigit(next[3])
+}
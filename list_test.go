@@ -0,0 +1,58 @@
+//go:build !bytez_minimal
+
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package bytez
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseList(t *testing.T) {
+	var positive = []struct {
+		in  string
+		out []Size
+	}{
+		{"64KiB, 1MiB, 16MiB", []Size{64 << 10, 1 << 20, 16 << 20}},
+		{"64KiB,1MiB", []Size{64 << 10, 1 << 20}},
+		{" 4096 ", []Size{4096}},
+		{"1, 2,3", []Size{1, 2, 3}},
+		{"1,2000", []Size{1, 2000}},
+		{"1kb,000", []Size{1000, 0}},
+		{"", []Size{}},
+	}
+
+	for _, test := range positive {
+		out, err := ParseList(test.in)
+		if testing.Verbose() {
+			fmt.Printf("%q --> %v\n", test.in, out)
+		}
+		require.NoError(t, err, test.in)
+		require.Equal(t, test.out, out, test.in)
+	}
+
+	var negative = []struct {
+		in  string
+		err string
+	}{
+		{"64KiB,, 1MiB", "item 2: empty size"},
+		{"64KiB, ", "item 2: empty size"},
+		{"64KiB, 1MiBs", `item 2: "1MiBs": invalid units: did you mean "MiB"?`},
+		{"1,000, 2,000", `item 2: ambiguous comma in "1,000": digit groups are not allowed in lists`},
+	}
+
+	for _, test := range negative {
+		_, err := ParseList(test.in)
+		if testing.Verbose() {
+			fmt.Printf("%q ==> %v\n", test.in, err)
+		}
+		require.EqualError(t, err, test.err, test.in)
+	}
+}
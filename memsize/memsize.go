@@ -0,0 +1,71 @@
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+// Package memsize helps services inspect and tune their memory usage with bytez sizes. It reads
+// and sets the soft memory limit of the Go runtime, normally configured with the GOMEMLIMIT
+// environment variable, using the same syntax as package bytez.
+package memsize
+
+import (
+	"errors"
+	"math"
+	"runtime/debug"
+	"strings"
+
+	"github.com/nexvium/bytez"
+)
+
+// Unlimited is the memory limit that disables limiting, as set by GOMEMLIMIT=off.
+const Unlimited = math.MaxInt64
+
+// ParseLimit parses a memory limit as given in the GOMEMLIMIT environment variable and returns
+// it in bytes. In addition to the syntax accepted by the runtime, like "off", "512MiB", and
+// "1048576B", any size accepted by bytez.AsInt, like "1.5 GiB" or "2gb", is allowed.
+func ParseLimit(str string) (int64, error) {
+	str = strings.TrimSpace(str)
+	if str == "off" {
+		return Unlimited, nil
+	}
+
+	val, err := bytez.AsInt(str)
+	if err != nil {
+		return 0, err
+	} else if val > math.MaxInt64 {
+		return 0, errors.New("memory limit is too large")
+	}
+	return int64(val), nil
+}
+
+// FormatLimit returns limit as a human-friendly size, like "512MiB", or "off" if it is
+// Unlimited.
+func FormatLimit(limit int64) string {
+	if limit == Unlimited {
+		return "off"
+	} else if limit < 0 {
+		return "invalid"
+	}
+	return bytez.AsStr(uint64(limit))
+}
+
+// Limit returns the current soft memory limit of the runtime in bytes.
+func Limit() int64 {
+	return debug.SetMemoryLimit(-1)
+}
+
+// LimitStr returns the current soft memory limit of the runtime formatted by FormatLimit.
+func LimitStr() string {
+	return FormatLimit(Limit())
+}
+
+// SetLimit parses str with ParseLimit and sets the result as the soft memory limit of the
+// runtime with debug.SetMemoryLimit. It returns the previous limit.
+func SetLimit(str string) (int64, error) {
+	limit, err := ParseLimit(str)
+	if err != nil {
+		return Limit(), err
+	}
+	return debug.SetMemoryLimit(limit), nil
+}
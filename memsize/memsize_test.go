@@ -0,0 +1,80 @@
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package memsize
+
+import (
+	"fmt"
+	"runtime/debug"
+	"testing"
+
+	"github.com/nexvium/bytez"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLimit(t *testing.T) {
+	var positive = []struct {
+		in  string
+		out int64
+	}{
+		{"off", Unlimited},
+		{" off\n", Unlimited},
+		{"0", 0},
+		{"1048576", 1 << 20},
+		{"1048576B", 1 << 20},
+		{"1024 B", 1 << 10},
+		{"512MiB", 512 << 20},
+		{"1GiB", 1 << 30},
+		{"1.5 GiB", 3 << 29},
+		{"1.25GiB", 5 << 28},
+		{"2gb", 2e9},
+		{"4KB", 4 << 10},
+	}
+
+	for _, test := range positive {
+		out, err := ParseLimit(test.in)
+		if testing.Verbose() {
+			fmt.Printf("%q --> %d\n", test.in, out)
+		}
+		require.NoError(t, err, test.in)
+		require.Equal(t, test.out, out, test.in)
+	}
+
+	for _, in := range []string{"", "on", "B", "-1", "1.25", "1 MiBB", "9223372036854775808", "15EiB"} {
+		_, err := ParseLimit(in)
+		if testing.Verbose() {
+			fmt.Printf("%q ==> %v\n", in, err)
+		}
+		require.Error(t, err, in)
+	}
+}
+
+func TestFormatLimit(t *testing.T) {
+	require.Equal(t, "off", FormatLimit(Unlimited))
+	require.Equal(t, "512MiB", FormatLimit(512<<20))
+	require.Equal(t, "0", FormatLimit(0))
+	require.Equal(t, "invalid", FormatLimit(-1))
+}
+
+func TestSetLimit(t *testing.T) {
+	orig := debug.SetMemoryLimit(-1)
+	defer debug.SetMemoryLimit(orig)
+
+	prev, err := SetLimit("3GiB")
+	require.NoError(t, err)
+	require.Equal(t, orig, prev)
+	require.Equal(t, int64(3*bytez.Gibibyte), Limit())
+	require.Equal(t, "3GiB", LimitStr())
+
+	prev, err = SetLimit("3 GiBs")
+	require.Error(t, err)
+	require.Equal(t, int64(3*bytez.Gibibyte), prev)
+	require.Equal(t, "3GiB", LimitStr())
+
+	_, err = SetLimit("off")
+	require.NoError(t, err)
+	require.Equal(t, "off", LimitStr())
+}
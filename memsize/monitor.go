@@ -0,0 +1,246 @@
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package memsize
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"runtime/metrics"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nexvium/bytez"
+)
+
+// Metric is a measure of the memory usage of the process.
+type Metric int
+
+const (
+	// Heap is the memory occupied by heap objects, including objects that are no longer live
+	// but have not been freed by the garbage collector yet.
+	Heap Metric = iota
+
+	// Runtime is the memory mapped by the Go runtime and not released to the operating system,
+	// which is what the soft memory limit bounds.
+	Runtime
+
+	// RSS is the resident set size of the process, including memory not managed by the Go
+	// runtime. On systems other than Linux it is the same as Runtime.
+	RSS
+)
+
+// String returns the name of the metric, like "heap".
+func (m Metric) String() string {
+	switch m {
+	case Heap:
+		return "heap"
+	case Runtime:
+		return "runtime"
+	case RSS:
+		return "rss"
+	}
+	return "Metric(" + strconv.Itoa(int(m)) + ")"
+}
+
+// Usage is a sample of the memory usage of the process.
+type Usage struct {
+	Heap, Runtime, RSS bytez.Size
+}
+
+// Get returns the value of metric m in the sample.
+func (u Usage) Get(m Metric) bytez.Size {
+	switch m {
+	case Heap:
+		return u.Heap
+	case Runtime:
+		return u.Runtime
+	case RSS:
+		return u.RSS
+	}
+	return 0
+}
+
+var usageMetrics = []string{
+	"/memory/classes/heap/objects:bytes",
+	"/memory/classes/total:bytes",
+	"/memory/classes/heap/released:bytes",
+}
+
+// ReadUsage samples the current memory usage of the process.
+func ReadUsage() Usage {
+	samples := make([]metrics.Sample, len(usageMetrics))
+	for i, name := range usageMetrics {
+		samples[i].Name = name
+	}
+	metrics.Read(samples)
+
+	var vals [3]uint64
+	for i, s := range samples {
+		if s.Value.Kind() == metrics.KindUint64 {
+			vals[i] = s.Value.Uint64()
+		}
+	}
+
+	u := Usage{Heap: bytez.Size(vals[0]), Runtime: bytez.Size(vals[1] - vals[2])}
+	if u.RSS = readRSS(); u.RSS == 0 {
+		u.RSS = u.Runtime
+	}
+	return u
+}
+
+// Event reports that the memory usage of the process crossed a watermark.
+type Event struct {
+	// Metric is the memory usage that crossed the watermark, and Usage its value.
+	Metric Metric
+	Usage  bytez.Size
+
+	// Watermark is the threshold that was crossed. If it was given as a percentage, Percent is
+	// that percentage and Limit the limit it was applied to; otherwise both are zero.
+	Watermark bytez.Size
+	Percent   float64
+	Limit     int64
+
+	// Above is true when the usage rose to or above the watermark and false when it fell below.
+	Above bool
+}
+
+var eventFormatter = bytez.NewFormatter(bytez.FormatOptions{Base: bytez.Base2, Precision: 1})
+
+// String returns a description of the event, like "rss 1.2GiB above 1GiB watermark" or "heap
+// 700MiB below 80% of 1GiB limit".
+func (e Event) String() string {
+	dir := "below"
+	if e.Above {
+		dir = "above"
+	}
+	usage := eventFormatter.Format(e.Usage)
+	if e.Percent != 0 {
+		return fmt.Sprintf("%v %s %s %g%% of %s limit", e.Metric, usage, dir, e.Percent,
+			eventFormatter.Format(bytez.Size(e.Limit)))
+	}
+	return fmt.Sprintf("%v %s %s %s watermark", e.Metric, usage, dir, eventFormatter.Format(e.Watermark))
+}
+
+// A Monitor samples the memory usage of the process and calls functions when it crosses
+// watermarks, so services can protect themselves, for example by shedding load or dropping
+// caches, before running out of memory. A Monitor is safe for concurrent use.
+type Monitor struct {
+	limit int64
+	read  func() Usage
+
+	mu         sync.Mutex
+	watermarks []*watermark
+}
+
+type watermark struct {
+	metric  Metric
+	size    bytez.Size
+	percent float64
+	fn      func(Event)
+	above   bool
+}
+
+// NewMonitor returns a monitor with no watermarks. Watermarks given as percentages are applied
+// to limit, or to the soft memory limit of the runtime at the time of each sample if limit is
+// zero. Percentages of Unlimited are never crossed.
+func NewMonitor(limit int64) *Monitor {
+	return &Monitor{limit: limit, read: ReadUsage}
+}
+
+// Watch adds a watermark for metric. The threshold is a size accepted by bytez.AsInt, like
+// "1.5GiB", or a percentage of the limit of the monitor, like "80%". The function fn is called
+// with an event when the usage rises to or above the threshold, and again when it falls back
+// below it. If the usage is already above the threshold, fn is called on the next sample.
+func (m *Monitor) Watch(metric Metric, threshold string, fn func(Event)) error {
+	if metric < Heap || metric > RSS {
+		return fmt.Errorf("invalid metric %v", metric)
+	} else if fn == nil {
+		return errors.New("nil watermark function")
+	}
+
+	w := &watermark{metric: metric, fn: fn}
+	threshold = strings.TrimSpace(threshold)
+	if pct, ok := strings.CutSuffix(threshold, "%"); ok {
+		val, err := strconv.ParseFloat(strings.TrimSpace(pct), 64)
+		if err != nil || !(val > 0) || math.IsInf(val, 1) {
+			return fmt.Errorf("invalid percentage %q", threshold)
+		}
+		w.percent = val
+	} else {
+		val, err := bytez.AsInt(threshold)
+		if err != nil {
+			return fmt.Errorf("invalid watermark %q: %v", threshold, err)
+		}
+		w.size = bytez.Size(val)
+	}
+
+	m.mu.Lock()
+	m.watermarks = append(m.watermarks, w)
+	m.mu.Unlock()
+	return nil
+}
+
+// Check samples the memory usage once, calls the functions of the watermarks crossed since the
+// previous sample, and returns the sample.
+func (m *Monitor) Check() Usage {
+	usage := m.read()
+	limit := m.limit
+	if limit == 0 {
+		limit = Limit()
+	}
+
+	// Functions are called without holding the lock so they can add watermarks.
+	var events []Event
+	var fns []func(Event)
+
+	m.mu.Lock()
+	for _, w := range m.watermarks {
+		e := Event{Metric: w.metric, Usage: usage.Get(w.metric), Watermark: w.size}
+		if w.percent != 0 {
+			if limit <= 0 || limit == Unlimited {
+				continue
+			}
+			e.Watermark = bytez.Size(float64(limit) * w.percent / 100)
+			e.Percent, e.Limit = w.percent, limit
+		}
+
+		if e.Above = e.Usage >= e.Watermark; e.Above != w.above {
+			w.above = e.Above
+			events = append(events, e)
+			fns = append(fns, w.fn)
+		}
+	}
+	m.mu.Unlock()
+
+	for i, e := range events {
+		fns[i](e)
+	}
+	return usage
+}
+
+// Run calls Check every interval until ctx is done, and returns the error of ctx. The interval
+// must be positive.
+func (m *Monitor) Run(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("invalid interval %v", interval)
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		m.Check()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
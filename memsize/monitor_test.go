@@ -0,0 +1,106 @@
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package memsize
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/nexvium/bytez"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadUsage(t *testing.T) {
+	u := ReadUsage()
+	if testing.Verbose() {
+		fmt.Printf("heap %v, runtime %v, rss %v\n", u.Heap.AsStr(), u.Runtime.AsStr(), u.RSS.AsStr())
+	}
+	require.NotZero(t, u.Heap)
+	require.GreaterOrEqual(t, uint64(u.Runtime), uint64(u.Heap))
+	require.NotZero(t, u.RSS)
+	require.Equal(t, u.RSS, u.Get(RSS))
+}
+
+func TestMonitor(t *testing.T) {
+	var usage Usage
+	var events []string
+
+	m := NewMonitor(int64(bytez.Gibibyte))
+	m.read = func() Usage { return usage }
+	record := func(e Event) { events = append(events, e.String()) }
+
+	require.NoError(t, m.Watch(Heap, "512MiB", record))
+	require.NoError(t, m.Watch(RSS, " 80% ", record))
+
+	steps := []struct {
+		usage  Usage
+		events []string
+	}{
+		{Usage{Heap: 100 << 20, RSS: 200 << 20}, nil},
+		{Usage{Heap: 512 << 20, RSS: 600 << 20}, []string{"heap 512MiB above 512MiB watermark"}},
+		{Usage{Heap: 600 << 20, RSS: 900 << 20}, []string{"rss 900MiB above 80% of 1GiB limit"}},
+		{Usage{Heap: 600 << 20, RSS: 950 << 20}, nil},
+		{Usage{Heap: 400 << 20, RSS: 700 << 20}, []string{
+			"heap 400MiB below 512MiB watermark",
+			"rss 700MiB below 80% of 1GiB limit",
+		}},
+	}
+
+	for _, step := range steps {
+		usage, events = step.usage, nil
+		require.Equal(t, usage, m.Check())
+		if testing.Verbose() {
+			fmt.Printf("%+v --> %q\n", step.usage, events)
+		}
+		require.Equal(t, step.events, events)
+	}
+}
+
+func TestMonitorUnlimited(t *testing.T) {
+	var called bool
+	m := NewMonitor(Unlimited)
+	m.read = func() Usage { return Usage{Heap: 1 << 40} }
+	require.NoError(t, m.Watch(Heap, "1%", func(Event) { called = true }))
+	m.Check()
+	require.False(t, called)
+}
+
+func TestMonitorWatch(t *testing.T) {
+	m := NewMonitor(0)
+	fn := func(Event) {}
+	for _, threshold := range []string{"", "80 MiBs", "-5%", "0%", "x%", "%"} {
+		err := m.Watch(Heap, threshold, fn)
+		if testing.Verbose() {
+			fmt.Printf("%q ==> %v\n", threshold, err)
+		}
+		require.Error(t, err)
+	}
+	require.Error(t, m.Watch(Metric(7), "1GiB", fn))
+	require.Error(t, m.Watch(Heap, "1GiB", nil))
+	require.Equal(t, "Metric(7)", Metric(7).String())
+}
+
+func TestMonitorRun(t *testing.T) {
+	fired := make(chan Event, 1)
+	m := NewMonitor(0)
+	require.NoError(t, m.Watch(Runtime, "1", func(e Event) { fired <- e }))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- m.Run(ctx, time.Millisecond) }()
+
+	e := <-fired
+	require.True(t, e.Above)
+	require.Equal(t, Runtime, e.Metric)
+	cancel()
+	require.Equal(t, context.Canceled, <-done)
+
+	require.Error(t, m.Run(context.Background(), 0))
+	require.Error(t, m.Run(context.Background(), -time.Second))
+}
@@ -0,0 +1,36 @@
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package memsize
+
+import (
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/nexvium/bytez"
+)
+
+var statm = "/proc/self/statm"
+
+// readRSS returns the resident set size of the process, or 0 if it cannot be read.
+func readRSS() bytez.Size {
+	data, err := ioutil.ReadFile(statm)
+	if err != nil {
+		return 0
+	}
+	// The second field is the number of resident pages.
+	fields := strings.Fields(string(data))
+	if len(fields) < 2 {
+		return 0
+	}
+	pages, err := strconv.ParseUint(fields[1], 10, 64)
+	if err != nil {
+		return 0
+	}
+	return bytez.Size(pages * uint64(os.Getpagesize()))
+}
@@ -0,0 +1,16 @@
+//go:build !linux
+
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package memsize
+
+import "github.com/nexvium/bytez"
+
+// The resident set size is only read on Linux; elsewhere ReadUsage uses the runtime usage.
+func readRSS() bytez.Size {
+	return 0
+}
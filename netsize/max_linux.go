@@ -0,0 +1,42 @@
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package netsize
+
+import (
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"github.com/nexvium/bytez"
+)
+
+// Linux silently limits the buffer sizes set by unprivileged processes to these sysctls.
+var (
+	rmemMax = "/proc/sys/net/core/rmem_max"
+	wmemMax = "/proc/sys/net/core/wmem_max"
+)
+
+func readMax() (bytez.Size, string) {
+	return sysctlSize(rmemMax), "net.core.rmem_max"
+}
+
+func writeMax() (bytez.Size, string) {
+	return sysctlSize(wmemMax), "net.core.wmem_max"
+}
+
+// sysctlSize returns the value of the sysctl in path, or 0 if it cannot be read.
+func sysctlSize(path string) bytez.Size {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return 0
+	}
+	val, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return bytez.Size(val)
+}
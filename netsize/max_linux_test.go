@@ -0,0 +1,30 @@
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package netsize
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/nexvium/bytez"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSysctlMax(t *testing.T) {
+	dir := t.TempDir()
+	defer func(r, w string) { rmemMax, wmemMax = r, w }(rmemMax, wmemMax)
+	rmemMax = filepath.Join(dir, "rmem_max")
+	wmemMax = filepath.Join(dir, "missing")
+	require.NoError(t, ioutil.WriteFile(rmemMax, []byte("212992\n"), 0644))
+
+	size, name := readMax()
+	require.Equal(t, bytez.Size(212992), size)
+	require.Equal(t, "net.core.rmem_max", name)
+	size, _ = writeMax()
+	require.Zero(t, size)
+}
@@ -0,0 +1,22 @@
+//go:build !linux
+
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package netsize
+
+import "github.com/nexvium/bytez"
+
+// The maxima of other systems are not known, so sizes are only limited to what fits in the
+// socket option.
+
+func readMax() (bytez.Size, string) {
+	return 0, ""
+}
+
+func writeMax() (bytez.Size, string) {
+	return 0, ""
+}
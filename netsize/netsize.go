@@ -0,0 +1,123 @@
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+// Package netsize applies socket buffer sizes, typically read from configuration as bytez
+// sizes, to network connections and listeners. Sizes larger than the operating system allows
+// are clamped to its maximum with a humanized warning, instead of being silently reduced by the
+// kernel.
+package netsize
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net"
+	"syscall"
+
+	"github.com/nexvium/bytez"
+)
+
+// Buffers holds the sizes of the socket receive and send buffers to use for connections.
+type Buffers struct {
+	// Read and Write are the sizes of the receive and send buffers. Zero leaves the default
+	// size of the operating system.
+	Read, Write bytez.Size
+
+	// Warn is called with a message like "netsize: read buffer of 64MiB clamped to 4MiB
+	// (net.core.rmem_max)" each time a size is clamped because it is larger than the operating
+	// system allows. If nil, the message is written with log.Print.
+	Warn func(msg string)
+}
+
+// A bufferConn is a connection whose socket buffers can be set, like *net.TCPConn,
+// *net.UDPConn, *net.UnixConn, and *net.IPConn.
+type bufferConn interface {
+	SetReadBuffer(bytes int) error
+	SetWriteBuffer(bytes int) error
+}
+
+// Apply sets the socket buffer sizes of conn, which must be a *net.TCPConn, *net.UDPConn, or
+// other connection with SetReadBuffer and SetWriteBuffer methods.
+func (b Buffers) Apply(conn net.Conn) error {
+	bc, ok := conn.(bufferConn)
+	if !ok {
+		return fmt.Errorf("netsize: cannot set buffer sizes of %T", conn)
+	}
+	if b.Read != 0 {
+		if err := bc.SetReadBuffer(b.clamp("read", b.Read, readMax)); err != nil {
+			return err
+		}
+	}
+	if b.Write != 0 {
+		if err := bc.SetWriteBuffer(b.clamp("write", b.Write, writeMax)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ApplyPacket is like Apply for packet connections, like the *net.UDPConn returned by
+// net.ListenPacket.
+func (b Buffers) ApplyPacket(conn net.PacketConn) error {
+	if c, ok := conn.(net.Conn); ok {
+		return b.Apply(c)
+	}
+	return fmt.Errorf("netsize: cannot set buffer sizes of %T", conn)
+}
+
+// Control sets the socket buffer sizes of c. It has the signature of the Control hooks of
+// net.ListenConfig and net.Dialer, so that buffers are set before a socket listens or
+// connects, which matters for TCP since the window scale is negotiated during the handshake and
+// accepted connections inherit the buffers of the listener.
+func (b Buffers) Control(network, address string, c syscall.RawConn) error {
+	var err error
+	cerr := c.Control(func(fd uintptr) {
+		if b.Read != 0 {
+			err = setsockoptBuffer(fd, false, b.clamp("read", b.Read, readMax))
+		}
+		if err == nil && b.Write != 0 {
+			err = setsockoptBuffer(fd, true, b.clamp("write", b.Write, writeMax))
+		}
+	})
+	if cerr != nil {
+		return cerr
+	}
+	return err
+}
+
+// ListenConfig returns a net.ListenConfig that sets the buffer sizes of the sockets it creates.
+func (b Buffers) ListenConfig() *net.ListenConfig {
+	return &net.ListenConfig{Control: b.Control}
+}
+
+// Dialer returns a net.Dialer that sets the buffer sizes of the sockets it creates.
+func (b Buffers) Dialer() *net.Dialer {
+	return &net.Dialer{Control: b.Control}
+}
+
+// clamp returns size as an int, limited to the maximum returned by osMax, if known, and warning
+// when it is.
+func (b Buffers) clamp(kind string, size bytez.Size, osMax func() (bytez.Size, string)) int {
+	max, name := osMax()
+	if max == 0 || max > math.MaxInt32 {
+		max, name = math.MaxInt32, "maximum socket option value"
+	}
+	if size <= max {
+		return int(size)
+	}
+
+	msg := fmt.Sprintf("netsize: %s buffer of %s clamped to %s (%s)", kind, size.AsStr(),
+		max.AsStr(), name)
+	if b.Warn != nil {
+		b.Warn(msg)
+	} else {
+		log.Print(msg)
+	}
+	return int(max)
+}
+
+var errNotSupported = errors.New("netsize: setting socket options is not supported on this platform")
@@ -0,0 +1,67 @@
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package netsize
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"testing"
+
+	"github.com/nexvium/bytez"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApply(t *testing.T) {
+	var warnings []string
+	b := Buffers{
+		Read:  bytez.Size(64 * bytez.Kibibyte),
+		Write: bytez.Size(8 * bytez.Exbibyte),
+		Warn:  func(msg string) { warnings = append(warnings, msg) },
+	}
+
+	ln, err := b.ListenConfig().Listen(context.Background(), "tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	conn, err := b.Dialer().Dial("tcp", ln.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+	require.NoError(t, b.Apply(conn))
+
+	pc, err := b.ListenConfig().ListenPacket(context.Background(), "udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer pc.Close()
+	require.NoError(t, b.ApplyPacket(pc))
+
+	if testing.Verbose() {
+		fmt.Println(warnings)
+	}
+	require.NotEmpty(t, warnings)
+	require.Regexp(t, `^netsize: write buffer of 8EiB clamped to \S+ \(.+\)$`, warnings[0])
+
+	client, server := net.Pipe()
+	defer client.Close()
+	defer server.Close()
+	require.Error(t, b.Apply(client))
+}
+
+func TestClamp(t *testing.T) {
+	var warning string
+	b := Buffers{Warn: func(msg string) { warning = msg }}
+	noMax := func() (bytez.Size, string) { return 0, "" }
+	osMax := func() (bytez.Size, string) { return bytez.Size(208 * bytez.Kibibyte), "net.core.rmem_max" }
+
+	require.Equal(t, 4096, b.clamp("read", 4096, osMax))
+	require.Empty(t, warning)
+	require.Equal(t, 208*1024, b.clamp("read", bytez.Size(bytez.Mebibyte), osMax))
+	require.Equal(t, "netsize: read buffer of 1MiB clamped to 208KiB (net.core.rmem_max)", warning)
+	require.Equal(t, math.MaxInt32, b.clamp("write", bytez.Size(4*bytez.Gibibyte), noMax))
+	require.Equal(t, "netsize: write buffer of 4GiB clamped to 2147483647 (maximum socket option value)",
+		warning)
+}
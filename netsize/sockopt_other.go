@@ -0,0 +1,13 @@
+//go:build !unix && !windows
+
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package netsize
+
+func setsockoptBuffer(fd uintptr, send bool, size int) error {
+	return errNotSupported
+}
@@ -0,0 +1,19 @@
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package netsize
+
+import "syscall"
+
+// setsockoptBuffer sets the size of the receive buffer, or of the send buffer if send is true, of
+// the socket fd.
+func setsockoptBuffer(fd uintptr, send bool, size int) error {
+	opt := syscall.SO_RCVBUF
+	if send {
+		opt = syscall.SO_SNDBUF
+	}
+	return syscall.SetsockoptInt(syscall.Handle(fd), syscall.SOL_SOCKET, opt, size)
+}
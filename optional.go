@@ -0,0 +1,38 @@
+//go:build !bytez_minimal
+
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package bytez
+
+import "strings"
+
+// OptionalSize is a Size for optional fields of configuration files: empty text, or text of only
+// whitespace, unmarshals to zero instead of failing with ErrNoNumber, so that documents with ""
+// for the field behave as if it was omitted. Other text is unmarshaled like Size, and sizes are
+// marshaled like Size, with zero as "0".
+type OptionalSize Size
+
+// AsStr returns the size formatted like Size.AsStr.
+func (o OptionalSize) AsStr() string {
+	return AsStr(uint64(o))
+}
+
+// MarshalText implements the encoding.TextMarshaler interface, formatting the size like
+// Size.MarshalText. Returned error is always nil.
+func (o OptionalSize) MarshalText() ([]byte, error) {
+	return Size(o).MarshalText()
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface, setting the size to zero if
+// text is empty.
+func (o *OptionalSize) UnmarshalText(text []byte) error {
+	if strings.TrimLeft(string(text), " \t\r\n") == "" {
+		*o = 0
+		return nil
+	}
+	return (*Size)(o).UnmarshalText(text)
+}
@@ -0,0 +1,56 @@
+//go:build !bytez_minimal
+
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package bytez
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptionalSize(t *testing.T) {
+	var tests = []struct {
+		in  string
+		out OptionalSize
+		err bool
+	}{
+		{`{"cache": ""}`, 0, false},
+		{`{"cache": " "}`, 0, false},
+		{`{}`, OptionalSize(Kibibyte), false},
+		{`{"cache": "256MiB"}`, OptionalSize(256 * Mebibyte), false},
+		{`{"cache": "0"}`, 0, false},
+		{`{"cache": "lots"}`, 0, true},
+	}
+
+	for _, test := range tests {
+		var cfg = struct {
+			Cache OptionalSize `json:"cache"`
+		}{OptionalSize(Kibibyte)}
+		err := json.Unmarshal([]byte(test.in), &cfg)
+		if testing.Verbose() {
+			fmt.Printf("%s --> %v, %v\n", test.in, cfg.Cache, err)
+		}
+		if test.err {
+			require.Error(t, err, test.in)
+		} else {
+			require.NoError(t, err, test.in)
+			require.Equal(t, test.out, cfg.Cache, test.in)
+		}
+	}
+
+	var sz Size
+	require.Error(t, json.Unmarshal([]byte(`""`), &sz))
+
+	out, err := json.Marshal([]OptionalSize{0, OptionalSize(3 * Mebibyte / 2)})
+	require.NoError(t, err)
+	require.Equal(t, `["0","1.5MiB"]`, string(out))
+	require.Equal(t, "4GiB", OptionalSize(4*Gibibyte).AsStr())
+}
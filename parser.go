@@ -0,0 +1,767 @@
+//go:build !bytez_minimal
+
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package bytez
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unsafe"
+)
+
+// Errors returned by parsers with options that restrict the accepted sizes, in addition to the
+// errors returned by AsInt.
+var (
+	// ErrWhitespace is returned by WithStrict for whitespace around the size.
+	ErrWhitespace = errors.New("surrounding whitespace")
+
+	// ErrSeparator is returned by WithStrict for underscores and digit group separators.
+	ErrSeparator = errors.New("digit separators not allowed")
+
+	// ErrInexact is returned by WithStrict for sizes that are not a whole number of bytes.
+	ErrInexact = errors.New("not a whole number of bytes")
+
+	// ErrUnitsNotAllowed is returned by WithUnits for valid units not in the list.
+	ErrUnitsNotAllowed = errors.New("units not allowed")
+
+	// ErrOutOfRange is wrapped by the errors of WithMax and WithMin for sizes outside the bounds.
+	ErrOutOfRange = errors.New("size out of range")
+
+	// ErrTooLarge is returned by WithMax for sizes above the maximum. It wraps ErrOutOfRange.
+	ErrTooLarge error = rangeError("size exceeds maximum")
+
+	// ErrTooSmall is returned by WithMin for sizes below the minimum. It wraps ErrOutOfRange.
+	ErrTooSmall error = rangeError("size below minimum")
+
+	// ErrNonstandardUnits is wrapped by the errors of WithStrictUnits for valid units that are
+	// ambiguous or nonstandard, like "MB" or "Mi", which suggest standard units instead.
+	ErrNonstandardUnits = errors.New("nonstandard units")
+)
+
+// unitsError is an error of WithStrictUnits, which wraps ErrNonstandardUnits.
+type unitsError string
+
+func (e unitsError) Error() string {
+	return string(e)
+}
+
+func (e unitsError) Unwrap() error {
+	return ErrNonstandardUnits
+}
+
+// rangeError is an error of WithMax or WithMin, which wraps ErrOutOfRange.
+type rangeError string
+
+func (e rangeError) Error() string {
+	return string(e)
+}
+
+func (e rangeError) Unwrap() error {
+	return ErrOutOfRange
+}
+
+// A Parser converts byte size specifications, like "4MiB", to Sizes. The zero value is ready to
+// use and parses sizes exactly like AsInt; NewParser returns parsers with other options.
+//
+// Parsers are immutable and safe for concurrent use by multiple goroutines, and parsing does not
+// allocate memory unless it fails, when it allocates only the returned error. A single Parser can
+// therefore be shared by all requests of a server or all records of a log pipeline.
+type Parser struct {
+	base   Base
+	strict bool
+	si     bool
+	fold   bool
+	units  map[string]bool
+	max    Size
+	hasMax bool
+	min    Size
+	hasMin bool
+
+	// lenient allows any whitespace between the number and the units.
+	lenient bool
+
+	// compound accepts sizes of several components, like "1GiB 512MiB", and noise skips the text
+	// around sizes.
+	compound bool
+	noise    bool
+
+	// rounding rounds fractions of a byte if hasRounding is set, instead of the rounding of
+	// AsInt or of the profile.
+	rounding    RoundingMode
+	hasRounding bool
+
+	// keywords enables WithKeywords, with unlimited as the value of "unlimited".
+	keywords  bool
+	unlimited Size
+
+	// profile, if set, replaces the units of this package, and custom adds to them.
+	profile *Profile
+	custom  map[string]uint64
+
+	// catalog translates localized units to those of this package.
+	catalog map[string]string
+
+	// defaultUnits are the units of numbers without units, if hasDefault is set, and defaultUnit
+	// their number of bytes.
+	defaultUnits string
+	defaultUnit  uint64
+	hasDefault   bool
+
+	// group is the separator of digit groups, used if hasGroup is set; AsInt uses commas, or
+	// periods if comma is set to make commas decimal points.
+	group    byte
+	hasGroup bool
+	comma    bool
+}
+
+// An Option changes how a Parser parses sizes.
+type Option func(*Parser) error
+
+// WithBase makes units that do not name their base explicitly, that is, all units other than
+// binary ones like "Ki" and "KiB" and full words like "kilobytes", use the given base regardless
+// of the case of their first letter. For example, with Base2, "4kb" is 4096 bytes. BaseAuto, the
+// default, uses the case of the first letter as described in the package documentation.
+func WithBase(base Base) Option {
+	return func(p *Parser) error {
+		if base < BaseAuto || base > Base10 {
+			return fmt.Errorf("invalid base %d", base)
+		}
+		p.base = base
+		return nil
+	}
+}
+
+// WithStrict rejects the variations that AsInt tolerates: whitespace around the size, a space
+// between the number and the units, underscores and group separators between digits, and
+// fractions that do not result in a whole number of bytes, like "0.0015kb".
+func WithStrict() Option {
+	return func(p *Parser) error {
+		p.strict = true
+		return nil
+	}
+}
+
+// WithLenient skips the text around a size, like "about " and "." in "about 4 GiB.", or the quotes
+// in "\"4GiB\"", where parsers reject any text other than the size by default, including a leading
+// "+". The size starts at the first digit and ends after the letters of its units, if it has any,
+// so that "4 GiB per user" is 4 GiB. A "-" before the first digit is still rejected, so that
+// negative sizes are not mistaken for positive ones. It cannot be combined with WithStrict or
+// WithCompound, or with profiles that parse sizes themselves, like Systemd.
+func WithLenient() Option {
+	return func(p *Parser) error {
+		p.noise = true
+		return nil
+	}
+}
+
+// trimNoise returns the start and end of the size in str, skipping the text around it as
+// described in WithLenient, or the whole of str if it has no digits.
+func (p *Parser) trimNoise(str string) (start, end int) {
+	start = 0
+	for start < len(str) && !isDigit(str[start]) {
+		start++
+	}
+	if start == len(str) {
+		return 0, len(str)
+	} else if start > 0 && str[start-1] == '-' {
+		return start - 1, len(str)
+	}
+
+	group, point := p.separators()
+	_, n, err := scanNumber(str[start:], group, point)
+	if err != nil {
+		return start, len(str)
+	}
+	end = start + n
+	units := end + spaces(str[end:])
+	for idx := units; idx < len(str) && isASCIILetter(str[idx]); idx++ {
+		end = idx + 1
+	}
+	return start, end
+}
+
+// isASCIILetter reports whether b is an ASCII letter, as are all the letters of units.
+func isASCIILetter(b byte) bool {
+	return (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z')
+}
+
+// WithLenientSpace allows any number of spaces, tabs, and line breaks between the number and the
+// units, where by default only a single space is allowed, so that sizes copied from tables
+// aligned with spaces or tabs, like "4\t\tGiB", can be parsed. It cannot be combined with
+// WithStrict, which allows no space at all.
+func WithLenientSpace() Option {
+	return func(p *Parser) error {
+		p.lenient = true
+		return nil
+	}
+}
+
+// WithRounding rounds sizes that are not a whole number of bytes, like "0.0015kb" or "1.3KiB",
+// according to mode, instead of to the nearest whole number with halves rounded to even as AsInt
+// does or as the profile of the parser does. It has no effect with WithStrict, which rejects such
+// sizes, or with profiles that parse sizes themselves, like Systemd.
+func WithRounding(mode RoundingMode) Option {
+	return func(p *Parser) error {
+		if mode < RoundHalfEven || mode > RoundCeil {
+			return fmt.Errorf("invalid rounding mode %d", mode)
+		}
+		p.rounding, p.hasRounding = mode, true
+		return nil
+	}
+}
+
+// WithStrictUnits accepts only units that are unambiguous according to the SI and ISO/IEC
+// standards: the binary units "KiB", "MiB", "GiB", "TiB", "PiB", and "EiB", the decimal units
+// "kB", "MB", "GB", "TB", "PB", and "EB", and "B", as well as full words like "kibibytes" and bit
+// units like "Mbit" and "Mibit". Other units, like "K", "KB", and "kb", are rejected with an
+// error suggesting the unambiguous alternatives. It is meant for sizes written by users who may
+// not know the convention of this package.
+//
+// Note that, following the SI standard, "MB" and larger decimal units are powers of 10 in this
+// mode, while they are powers of 2 by default. WithBase does not apply to the strict units.
+func WithStrictUnits() Option {
+	return func(p *Parser) error {
+		p.si = true
+		return nil
+	}
+}
+
+// siUnits maps the units accepted by WithStrictUnits to their values.
+var siUnits = func() map[string]uint64 {
+	units := map[string]uint64{
+		"B": 1, "kB": Kilobyte, "MB": Megabyte, "GB": Gigabyte, "TB": Terabyte, "PB": Petabyte, "EB": Exabyte,
+		"KiB": Kibibyte, "MiB": Mebibyte, "GiB": Gibibyte, "TiB": Tebibyte, "PiB": Pebibyte, "EiB": Exbibyte,
+	}
+	for i := range wordsBase10 {
+		units[wordsBase10[i]], units[wordsBase10[i]+"s"] = valuesBase10[i], valuesBase10[i]
+		units[wordsBase2[i]], units[wordsBase2[i]+"s"] = valuesBase2[i], valuesBase2[i]
+	}
+	for i := 0; i < len(bitPrefixes); i++ {
+		units[bitPrefixes[i:i+1]+"bit"] = valuesBase10[i+1] / 8
+		units[strings.ToUpper(bitPrefixes[i:i+1])+"ibit"] = valuesBase2[i+1] / 8
+	}
+	return units
+}()
+
+// siErrors maps the other valid units to the errors returned for them by WithStrictUnits. The
+// errors are preallocated so that failing to parse allocates only the ParseError.
+var siErrors = func() map[string]error {
+	errs := make(map[string]error, len(unitMap))
+	for units := range unitMap {
+		if _, ok := siUnits[units]; ok {
+			continue
+		}
+		prefix := strings.ToUpper(units[:1])
+		decimal := prefix + "B"
+		if prefix == "K" {
+			decimal = "kB"
+		}
+		if explicitBase(units) {
+			errs[units] = unitsError(fmt.Sprintf("nonstandard units %q: use %q", units, prefix+"iB"))
+		} else {
+			errs[units] = unitsError(fmt.Sprintf(
+				"ambiguous units %q: use %q for powers of 2 or %q for powers of 10",
+				units, prefix+"iB", decimal))
+		}
+	}
+	return errs
+}()
+
+// WithIgnoreCase matches units regardless of case, for sizes typed by users who write "4gb" and
+// "4GB" interchangeably. Since the case then no longer determines the base, units other than
+// binary ones like "KiB" and "kib" and full words use the given base, which must be Base2 or
+// Base10. It cannot be combined with WithStrictUnits, and overrides WithBase. WithUnits still
+// matches the exact spellings.
+func WithIgnoreCase(base Base) Option {
+	return func(p *Parser) error {
+		if base != Base2 && base != Base10 {
+			return fmt.Errorf("invalid base %d for case-insensitive units", base)
+		}
+		p.base, p.fold = base, true
+		return nil
+	}
+}
+
+// foldedUnit is the value of units matched regardless of case: the number of bytes for units
+// that name their base explicitly, or else their prefix, as an index into valuesBase2 and
+// valuesBase10.
+type foldedUnit struct {
+	index int
+	value uint64
+}
+
+// foldedUnits maps the lowercase spellings of the valid units to their values.
+var foldedUnits = func() map[string]foldedUnit {
+	folded := make(map[string]foldedUnit, len(unitMap))
+	for units, val := range unitMap {
+		if explicitBase(units) {
+			folded[strings.ToLower(units)] = foldedUnit{value: val}
+			continue
+		}
+		for i := range valuesBase10 {
+			if val == valuesBase2[i] || val == valuesBase10[i] {
+				folded[strings.ToLower(units)] = foldedUnit{index: i}
+			}
+		}
+	}
+	return folded
+}()
+
+// lookupFolded returns the number of bytes in the given units, matched regardless of case, using
+// base for units that do not name it explicitly.
+func lookupFolded(units string, base Base) (uint64, bool) {
+	var buf [len("kilobytes")]byte
+	if len(units) > len(buf) {
+		return 0, false
+	}
+	for i := 0; i < len(units); i++ {
+		b := units[i]
+		if b >= 'A' && b <= 'Z' {
+			b += 'a' - 'A'
+		}
+		buf[i] = b
+	}
+
+	u, ok := foldedUnits[string(buf[:len(units)])]
+	if !ok {
+		return 0, false
+	} else if u.value != 0 {
+		return u.value, true
+	} else if base == Base2 {
+		return valuesBase2[u.index], true
+	}
+	return valuesBase10[u.index], true
+}
+
+// WithGrouping sets the separator of groups of three digits in the whole number, which is a
+// comma by default, as in "1,048,576". For example, with an apostrophe sizes like "1'048'576"
+// are accepted, and with a space sizes like "12 288 KiB". The separator cannot be a letter, a
+// digit, the decimal point, or an underscore; 0 disallows digit groups.
+func WithGrouping(sep byte) Option {
+	return func(p *Parser) error {
+		if sep != 0 && (sep < ' ' || sep > '~' || isDigit(sep) || isLetter(sep) || sep == '_') {
+			return fmt.Errorf("invalid digit group separator %q", sep)
+		}
+		p.group, p.hasGroup = sep, true
+		return nil
+	}
+}
+
+// WithDecimalComma makes a comma the decimal point, as written in most of Europe and South
+// America, like "1,5 GiB". Digit groups are then separated by periods by default, like
+// "1.048.576", and a period is not accepted as the decimal point, so that "1.5 GiB" is rejected
+// rather than misread. WithGrouping can select another separator, like a space.
+func WithDecimalComma() Option {
+	return func(p *Parser) error {
+		p.comma = true
+		return nil
+	}
+}
+
+// WithUnits restricts the accepted units to the given spellings, like "KiB" and "MiB", which
+// must be valid units of this package or custom units. Numbers without units are always accepted.
+func WithUnits(units ...string) Option {
+	return func(p *Parser) error {
+		p.units = make(map[string]bool, len(units))
+		for _, u := range units {
+			p.units[u] = true
+		}
+		return nil
+	}
+}
+
+// WithDefaultUnit makes numbers without units count the given units instead of bytes, as many
+// tools do, so that with "MiB" "512" is 512 mebibytes and "1.5" is 1.5 mebibytes. The units are
+// interpreted according to the other options, and must be accepted by WithUnits if it is also
+// given. Sizes with units are not affected.
+func WithDefaultUnit(units string) Option {
+	return func(p *Parser) error {
+		p.defaultUnits, p.hasDefault = units, true
+		return nil
+	}
+}
+
+// WithMax rejects sizes greater than max with ErrTooLarge.
+func WithMax(max Size) Option {
+	return func(p *Parser) error {
+		p.max, p.hasMax = max, true
+		return nil
+	}
+}
+
+// WithMin rejects sizes less than min with ErrTooSmall. Like WithMax, it does not apply to
+// keywords, and with WithCompound it applies to the sum of the components.
+func WithMin(min Size) Option {
+	return func(p *Parser) error {
+		p.min, p.hasMin = min, true
+		return nil
+	}
+}
+
+// bound returns the error for a size outside the bounds of the parser, if any. The minimum is
+// checked later for compound sizes, whose components may be smaller.
+func (p *Parser) bound(val Size) error {
+	if p.hasMax && val > p.max {
+		return ErrTooLarge
+	} else if p.hasMin && val < p.min && !p.compound {
+		return ErrTooSmall
+	}
+	return nil
+}
+
+// NewParser returns a Parser with the given options. It returns an error if an option is not
+// valid.
+func NewParser(opts ...Option) (*Parser, error) {
+	p := &Parser{}
+	for _, opt := range opts {
+		if err := opt(p); err != nil {
+			return nil, err
+		}
+	}
+	if p.hasMin && p.hasMax && p.min > p.max {
+		return nil, fmt.Errorf("minimum %s exceeds maximum %s", p.min.AsStr(), p.max.AsStr())
+	} else if p.strict && p.lenient {
+		return nil, errors.New("lenient whitespace cannot be combined with strict syntax")
+	} else if p.fold && p.si {
+		return nil, errors.New("strict units cannot be matched regardless of case")
+	} else if p.fold && p.base == BaseAuto {
+		return nil, errors.New("case-insensitive units need a base")
+	} else if p.profile != nil && (p.si || p.fold || p.base != BaseAuto) {
+		return nil, fmt.Errorf("%s profile cannot be combined with other unit options", p.profile.name)
+	} else if p.profile != nil && p.profile.parse != nil && p.custom != nil {
+		return nil, fmt.Errorf("%s profile does not accept custom units", p.profile.name)
+	} else if p.profile != nil && p.profile.parse != nil && p.catalog != nil {
+		return nil, fmt.Errorf("%s profile does not accept catalogs", p.profile.name)
+	} else if p.profile != nil && p.profile.parse != nil && (p.compound || p.noise) {
+		return nil, fmt.Errorf("%s profile cannot be combined with WithCompound or WithLenient",
+			p.profile.name)
+	} else if p.noise && (p.strict || p.compound) {
+		return nil, errors.New("lenient parsing cannot be combined with strict syntax or compound sizes")
+	}
+	if p.fold && p.catalog != nil {
+		folded := make(map[string]string, len(p.catalog))
+		for local, units := range p.catalog {
+			folded[strings.ToLower(local)] = units
+		}
+		p.catalog = folded
+	}
+	for u := range p.units {
+		if _, ok := lookupUnit(u); !ok && p.custom[u] == 0 && p.catalog[u] == "" {
+			return nil, fmt.Errorf("unknown units %q", u)
+		}
+	}
+
+	point := byte('.')
+	if p.comma {
+		point = ','
+	}
+	if p.hasGroup && p.group == point {
+		return nil, fmt.Errorf("digit group separator %q is the decimal point", p.group)
+	}
+
+	// The default units are looked up once all the options that affect them are known.
+	if p.hasDefault {
+		unit, err := p.lookup(p.defaultUnits)
+		if err != nil {
+			return nil, fmt.Errorf("default units %q: %v", p.defaultUnits, err)
+		}
+		p.defaultUnit = unit
+	}
+	return p, nil
+}
+
+// ParseSize returns the size specified by str, parsed with the given options. Programs that
+// parse many sizes with the same options should use NewParser instead.
+func ParseSize(str string, opts ...Option) (Size, error) {
+	p, err := NewParser(opts...)
+	if err != nil {
+		return 0, err
+	}
+	return p.Parse(str)
+}
+
+// MustParse is like AsInt but returns a Size and panics if str cannot be parsed. It simplifies
+// the initialization of package-level variables holding sizes, like
+//
+//	var DefaultCacheSize = bytez.MustParse("256MiB")
+func MustParse(str string) Size {
+	val, err := AsInt(str)
+	if err != nil {
+		panic(`bytez: MustParse(` + strconv.Quote(str) + `): ` + err.Error())
+	}
+	return Size(val)
+}
+
+// MustParseSize is like ParseSize but panics if str cannot be parsed or an option is not valid.
+func MustParseSize(str string, opts ...Option) Size {
+	size, err := ParseSize(str, opts...)
+	if err != nil {
+		panic(`bytez: MustParseSize(` + strconv.Quote(str) + `): ` + err.Error())
+	}
+	return size
+}
+
+// Parse returns the size specified by str. See AsInt for the accepted syntax, which the options
+// of the Parser may change or restrict. Errors are of type *ParseError.
+func (p *Parser) Parse(str string) (Size, error) {
+	size, offset, err := p.parse(str, nil)
+	if err != nil {
+		return 0, p.error(str, offset, err)
+	}
+	return size, nil
+}
+
+// ParseBytes is like Parse but parses a byte slice, without converting it to a string, so that
+// parsing does not allocate memory unless it fails. The slice is not retained.
+func (p *Parser) ParseBytes(b []byte) (Size, error) {
+	size, offset, err := p.parse(unsafe.String(unsafe.SliceData(b), len(b)), nil)
+	if err != nil {
+		return 0, p.error(string(b), offset, err)
+	}
+	return size, nil
+}
+
+// parse returns the size specified by str, or the error and its offset in str. If res is not nil,
+// the units of the size are stored in it.
+func (p *Parser) parse(str string, res *ParseResult) (Size, int, error) {
+	if p.compound {
+		return p.parseCompound(str, res)
+	} else if p.noise {
+		start, end := p.trimNoise(str)
+		size, offset, err := p.parseSingle(str[start:end], res)
+		return size, start + offset, err
+	}
+	return p.parseSingle(str, res)
+}
+
+// separators returns the digit group separator and the decimal point of the parser.
+func (p *Parser) separators() (group, point byte) {
+	group, point = ',', '.'
+	if p.comma {
+		group, point = '.', ','
+	}
+	if p.hasGroup {
+		group = p.group
+	}
+	return group, point
+}
+
+// parseSingle returns the size specified by str, which has a single component, as parse does.
+func (p *Parser) parseSingle(str string, res *ParseResult) (Size, int, error) {
+	// Profiles may impose the syntax of WithStrict, but round fractions instead of rejecting them.
+	strict, space, rounding, fractions := p.strict, !p.strict, RoundHalfEven, false
+	if p.profile != nil {
+		strict = strict || p.profile.strict
+		space = space && (!p.profile.strict || p.profile.space)
+		rounding, fractions = p.profile.rounding, p.profile.fractions
+	}
+	if p.hasRounding {
+		rounding = p.rounding
+	}
+
+	group, point, separators := byte(','), byte('.'), "_,"
+	if p.comma {
+		group, point, separators = '.', ',', "_."
+	}
+
+	trimmed := strings.TrimLeft(str, " \t\r\n")
+	lead := len(str) - len(trimmed)
+	trimmed = strings.TrimRight(trimmed, " \t\r\n")
+	if strict {
+		if trimmed != str {
+			if lead == 0 {
+				return 0, len(trimmed), ErrWhitespace
+			}
+			return 0, 0, ErrWhitespace
+		} else if idx := strings.IndexAny(str, separators); idx >= 0 {
+			return 0, idx, ErrSeparator
+		}
+	}
+	str = trimmed
+	if p.keywords {
+		if size, ok := lookupKeyword(str, p.unlimited); ok {
+			return size, 0, nil
+		}
+	}
+
+	if p.profile != nil && p.profile.parse != nil {
+		unit := uint64(1)
+		if p.hasDefault {
+			unit = p.defaultUnit
+		}
+		val, idx, err := p.profile.parse(str, unit)
+		if err != nil {
+			return 0, lead + idx, err
+		} else if err = p.bound(Size(val)); err != nil {
+			return 0, lead, err
+		}
+		return Size(val), 0, nil
+	}
+
+	if strict {
+		group = 0
+	} else if p.hasGroup {
+		group = p.group
+	}
+	num, idx, err := scanNumber(str, group, point)
+	if err != nil {
+		return 0, lead + idx, err
+	}
+
+	var val uint64
+	if idx == len(str) && !p.hasDefault && !fractions {
+		if val, err = num.integer(); err == ErrMissingUnits {
+			return 0, lead + idx, err
+		} else if err != nil {
+			return 0, lead, err
+		}
+		if res != nil {
+			res.Unit = 1
+		}
+	} else {
+		unit, units := uint64(1), ""
+		if p.hasDefault {
+			unit, units = p.defaultUnit, p.defaultUnits
+		}
+		if idx < len(str) {
+			if space && p.lenient {
+				idx += spaces(str[idx:])
+			} else if space && str[idx] == ' ' {
+				idx++
+			}
+			if str[idx:] == "" {
+				return 0, lead + idx, ErrMissingUnits
+			} else if !isLetter(str[idx]) {
+				return 0, lead + idx, ErrInvalidDelimiter
+			}
+			units = str[idx:]
+			if unit, err = p.lookup(units); err != nil {
+				return 0, lead + idx, err
+			}
+		}
+		if res != nil {
+			res.Units, res.Unit = units, unit
+		}
+
+		if p.strict && !num.exact(unit) {
+			return 0, lead, ErrInexact
+		}
+		if val, err = num.round(unit, rounding); err != nil {
+			return 0, lead, err
+		}
+	}
+
+	if err := p.bound(Size(val)); err != nil {
+		return 0, lead, err
+	}
+	return Size(val), 0, nil
+}
+
+// error returns the ParseError for err at the given offset in str, suggesting units of the
+// parser if err is ErrInvalidUnits.
+func (p *Parser) error(str string, offset int, err error) error {
+	e := &ParseError{Input: str, Offset: offset, Err: err}
+	if err == ErrInvalidUnits {
+		candidates := suggestedUnits
+		if p.profile != nil {
+			candidates = p.profile.names[1:]
+		}
+		e.Suggestion = suggestUnits(strings.TrimRight(str[offset:], " \t\r\n"), candidates)
+	}
+	return e
+}
+
+// lookup returns the number of bytes in the given units according to the options of the parser.
+func (p *Parser) lookup(units string) (uint64, error) {
+	name := units
+	if translated, ok := p.translate(units); ok {
+		units = translated
+	}
+
+	unit, ok := p.custom[units]
+	if ok {
+		if p.units != nil && !p.units[units] {
+			return 0, ErrUnitsNotAllowed
+		}
+		return unit, nil
+	}
+
+	if p.profile != nil {
+		unit, ok = p.profile.lookup(units)
+	} else if p.fold {
+		unit, ok = lookupFolded(units, p.base)
+	} else {
+		unit, ok = lookupUnit(units)
+	}
+	if !ok {
+		return 0, ErrInvalidUnits
+	} else if p.units != nil && !p.units[name] {
+		return 0, ErrUnitsNotAllowed
+	}
+	if p.si {
+		if unit, ok = siUnits[units]; !ok {
+			return 0, siErrors[units]
+		}
+	} else if p.base != BaseAuto && !p.fold && !explicitBase(units) {
+		unit = convertUnit(unit, p.base)
+	}
+	return unit, nil
+}
+
+// explicitBase reports whether units name their base explicitly, like "Ki", "KiB", and full
+// words like "kilobytes".
+func explicitBase(units string) bool {
+	return len(units) > len("KiB") || (len(units) >= 2 && units[1] == 'i')
+}
+
+// convertUnit returns the unit of the given base with the same prefix as unit, like Kibibyte
+// for Kilobyte and Base2.
+func convertUnit(unit uint64, base Base) uint64 {
+	for i := range valuesBase10 {
+		if unit == valuesBase10[i] || unit == valuesBase2[i] {
+			if base == Base2 {
+				return valuesBase2[i]
+			}
+			return valuesBase10[i]
+		}
+	}
+	return unit
+}
+
+// round returns the number of bytes in num units of the given size, rounded to a whole number
+// according to mode.
+func (num number) round(unit uint64, mode RoundingMode) (uint64, error) {
+	val, frac, err := num.mul(unit)
+	if err != nil {
+		return 0, err
+	}
+
+	var up bool
+	switch mode {
+	case RoundHalfEven:
+		up = frac == fracAboveHalf || (frac == fracHalf && val%2 == 1)
+	case RoundHalfUp:
+		up = frac == fracAboveHalf || frac == fracHalf
+	case RoundCeil:
+		up = frac != fracZero
+	}
+	if up {
+		if val++; val == 0 {
+			return 0, ErrOverflow
+		}
+	}
+	return val, nil
+}
+
+// exact reports whether num units of the given size is a whole number of bytes.
+func (num number) exact(unit uint64) bool {
+	_, frac, err := num.mul(unit)
+	return err == nil && frac == fracZero
+}
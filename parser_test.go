@@ -0,0 +1,391 @@
+//go:build !bytez_minimal
+
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package bytez
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParser(t *testing.T) {
+	var p Parser
+
+	sz, err := p.Parse("4.5 GiB")
+	require.NoError(t, err)
+	require.Equal(t, Size(4*Gibibyte+Gibibyte/2), sz)
+
+	_, err = p.Parse("4.5 GiBs")
+	require.Error(t, err)
+
+	// A single parser must be usable from many goroutines.
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				sz, err := p.Parse("64MiB")
+				require.NoError(t, err)
+				require.Equal(t, Size(64*Mebibyte), sz)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestParseSize(t *testing.T) {
+	var tests = []struct {
+		opts []Option
+		in   string
+		out  uint64
+		err  bool
+	}{
+		{nil, " 4.5 GiB ", 4*Gibibyte + Gibibyte/2, false},
+		{[]Option{WithBase(Base2)}, "4kb", 4 * Kibibyte, false},
+		{[]Option{WithBase(Base2)}, "4KiB", 4 * Kibibyte, false},
+		{[]Option{WithBase(Base2)}, "4000", 4000, false},
+		{[]Option{WithBase(Base10)}, "4K", 4 * Kilobyte, false},
+		{[]Option{WithBase(Base10)}, "1.5GB", 3 * Gigabyte / 2, false},
+		{[]Option{WithBase(Base10)}, "4KiB", 4 * Kibibyte, false},
+		{[]Option{WithStrict()}, "4.5GiB", 4*Gibibyte + Gibibyte/2, false},
+		{[]Option{WithStrict()}, "1.001kb", 1001, false},
+		{[]Option{WithStrict()}, " 4GiB", 0, true},
+		{[]Option{WithStrict()}, "4GiB\n", 0, true},
+		{[]Option{WithStrict()}, "4 GiB", 0, true},
+		{[]Option{WithStrict()}, "1_024", 0, true},
+		{[]Option{WithStrict()}, "0.0015kb", 0, true},
+		{[]Option{WithStrict()}, "0.1KiB", 0, true},
+		{nil, "4\tGiB", 0, true},
+		{nil, "4  GiB", 0, true},
+		{[]Option{WithLenientSpace()}, "4\tGiB", 4 * Gibibyte, false},
+		{[]Option{WithLenientSpace()}, "4 \t  GiB", 4 * Gibibyte, false},
+		{[]Option{WithLenientSpace()}, " 4.5GiB\t", 4*Gibibyte + Gibibyte/2, false},
+		{[]Option{WithLenientSpace()}, "4  ", 4, false},
+		{[]Option{WithLenientSpace()}, "4 \t", 4, false},
+		{[]Option{WithLenientSpace()}, "4  4 GiB", 0, true},
+		{[]Option{WithLenientSpace(), WithProfile(Docker)}, "512\t\tm", 512 * Mebibyte, false},
+		{[]Option{WithLenientSpace(), WithProfile(Kubernetes)}, "512 Mi", 0, true},
+		{[]Option{WithLenientSpace(), WithStrict()}, "4GiB", 0, true},
+		{nil, "1.3KiB", 1331, false},
+		{[]Option{WithRounding(RoundFloor)}, "1.3KiB", 1331, false},
+		{[]Option{WithRounding(RoundCeil)}, "1.3KiB", 1332, false},
+		{[]Option{WithRounding(RoundCeil)}, "1.5KiB", 1536, false},
+		{[]Option{WithRounding(RoundHalfUp)}, "0.0025kb", 3, false},
+		{[]Option{WithRounding(RoundHalfEven)}, "0.0025kb", 2, false},
+		{[]Option{WithRounding(RoundFloor)}, "0.0035kb", 3, false},
+		{[]Option{WithRounding(RoundCeil)}, "1e-1000kb", 1, false},
+		{[]Option{WithRounding(RoundCeil), WithProfile(Docker)}, "1.5", 2, false},
+		{[]Option{WithRounding(RoundFloor), WithProfile(Kubernetes)}, "1.5", 1, false},
+		{[]Option{WithRounding(RoundCeil), WithStrict()}, "1.3KiB", 0, true},
+		{[]Option{WithRounding(RoundingMode(4))}, "1", 0, true},
+		{nil, "+4GiB", 0, true},
+		{nil, "4GiB.", 0, true},
+		{[]Option{WithLenient()}, "+4GiB", 4 * Gibibyte, false},
+		{[]Option{WithLenient()}, "about 4 GiB.", 4 * Gibibyte, false},
+		{[]Option{WithLenient()}, `"4GiB",`, 4 * Gibibyte, false},
+		{[]Option{WithLenient()}, "size=1,024KiB;", Mebibyte, false},
+		{[]Option{WithLenient()}, "4 GiB per user", 4 * Gibibyte, false},
+		{[]Option{WithLenient()}, "(4096)", 4096, false},
+		{[]Option{WithLenient()}, "1.5GiB\u2014", 3 * Gibibyte / 2, false},
+		{[]Option{WithLenient()}, "4 GiBs.", 0, true},
+		{[]Option{WithLenient()}, "-4GiB", 0, true},
+		{[]Option{WithLenient()}, "no size", 0, true},
+		{[]Option{WithLenient(), WithKeywords(Unlimited)}, "unlimited", uint64(Unlimited), false},
+		{[]Option{WithLenient(), WithDefaultUnit("MiB")}, "limit: 512;", 512 * Mebibyte, false},
+		{[]Option{WithLenient(), WithStrict()}, "4GiB", 0, true},
+		{[]Option{WithLenient(), WithCompound()}, "4GiB", 0, true},
+		{[]Option{WithLenient(), WithProfile(Systemd)}, "4G", 0, true},
+		{[]Option{WithUnits("KiB", "MiB")}, "4 MiB", 4 * Mebibyte, false},
+		{[]Option{WithUnits("KiB", "MiB")}, "4096", 4096, false},
+		{[]Option{WithUnits("KiB", "MiB")}, "4M", 0, true},
+		{[]Option{WithUnits("KiB", "MiB")}, "4GiB", 0, true},
+		{[]Option{WithMax(Size(Gibibyte))}, "1GiB", Gibibyte, false},
+		{[]Option{WithMax(Size(Gibibyte))}, "1073741825", 0, true},
+		{[]Option{WithMax(0)}, "1", 0, true},
+		{[]Option{WithMin(Size(4 * Kibibyte))}, "4KiB", 4 * Kibibyte, false},
+		{[]Option{WithMin(Size(4 * Kibibyte))}, "4095", 0, true},
+		{[]Option{WithMin(Size(4 * Kibibyte)), WithMax(Size(Kibibyte))}, "2KiB", 0, true},
+		{[]Option{WithMin(Size(Kibibyte)), WithKeywords(Unlimited)}, "none", 0, false},
+		{[]Option{WithBase(Base2), WithMax(Size(4 * Kibibyte))}, "4k", 4 * Kibibyte, false},
+		{[]Option{WithStrictUnits()}, "4KiB", 4 * Kibibyte, false},
+		{[]Option{WithStrictUnits()}, "4 kB", 4 * Kilobyte, false},
+		{[]Option{WithStrictUnits()}, "4MB", 4 * Megabyte, false},
+		{[]Option{WithStrictUnits()}, "2.5 EiB", 5 * Exbibyte / 2, false},
+		{[]Option{WithStrictUnits()}, "4096", 4096, false},
+		{[]Option{WithStrictUnits(), WithBase(Base2)}, "4MB", 4 * Megabyte, false},
+		{[]Option{WithStrictUnits()}, "4kb", 0, true},
+		{[]Option{WithStrictUnits()}, "4Mb", 0, true},
+		{[]Option{WithStrictUnits()}, "4m", 0, true},
+		{[]Option{WithIgnoreCase(Base10)}, "4GB", 4 * Gigabyte, false},
+		{[]Option{WithIgnoreCase(Base10)}, "4gb", 4 * Gigabyte, false},
+		{[]Option{WithIgnoreCase(Base10)}, "4 KIB", 4 * Kibibyte, false},
+		{[]Option{WithIgnoreCase(Base10)}, "4kib", 4 * Kibibyte, false},
+		{[]Option{WithIgnoreCase(Base2)}, "4kb", 4 * Kibibyte, false},
+		{[]Option{WithIgnoreCase(Base2)}, "1.5 gB", 3 * Gibibyte / 2, false},
+		{[]Option{WithIgnoreCase(Base2)}, "4mi", 4 * Mebibyte, false},
+		{[]Option{WithIgnoreCase(Base2)}, "4kibs", 0, true},
+		{[]Option{WithIgnoreCase(Base2)}, "4x", 0, true},
+		{[]Option{WithIgnoreCase(Base2), WithBase(BaseAuto)}, "4k", 0, true},
+		{[]Option{WithIgnoreCase(Base2), WithStrictUnits()}, "4KiB", 0, true},
+		{[]Option{WithIgnoreCase(BaseAuto)}, "4k", 0, true},
+		{[]Option{WithBase(Base2)}, "4 kilobytes", 4 * Kilobyte, false},
+		{[]Option{WithBase(Base10)}, "4 kibibytes", 4 * Kibibyte, false},
+		{[]Option{WithStrictUnits()}, "4 megabytes", 4 * Megabyte, false},
+		{[]Option{WithStrictUnits()}, "4 mebibytes", 4 * Mebibyte, false},
+		{[]Option{WithIgnoreCase(Base2)}, "4 Megabytes", 4 * Megabyte, false},
+		{[]Option{WithIgnoreCase(Base10)}, "4 KibiBytes", 4 * Kibibyte, false},
+		{[]Option{WithIgnoreCase(Base10)}, "512 BYTES", 512, false},
+		{nil, "1,048,576", Mebibyte, false},
+		{[]Option{WithStrict()}, "1,048,576", 0, true},
+		{[]Option{WithGrouping('\'')}, "1'048'576", Mebibyte, false},
+		{[]Option{WithGrouping('\'')}, "1,048,576", 0, true},
+		{[]Option{WithGrouping(' ')}, "12 288 KiB", 12288 * Kibibyte, false},
+		{[]Option{WithGrouping(' ')}, "12 KiB", 12 * Kibibyte, false},
+		{[]Option{WithGrouping(' ')}, "12 28 KiB", 0, true},
+		{[]Option{WithGrouping(0)}, "1,048,576", 0, true},
+		{[]Option{WithGrouping('.')}, "1", 0, true},
+		{[]Option{WithDecimalComma()}, "1,5 GiB", 3 * Gibibyte / 2, false},
+		{[]Option{WithDecimalComma()}, "0,5", 0, true},
+		{[]Option{WithDecimalComma()}, "1.048.576", Mebibyte, false},
+		{[]Option{WithDecimalComma()}, "1.024,5 KiB", 1049088, false},
+		{[]Option{WithDecimalComma()}, "1.5 GiB", 0, true},
+		{[]Option{WithDecimalComma()}, "1,048,576", 0, true},
+		{[]Option{WithDecimalComma()}, "1,5e3", 1500, false},
+		{[]Option{WithDecimalComma(), WithGrouping(' ')}, "1 024,5 KiB", 1049088, false},
+		{[]Option{WithDecimalComma(), WithGrouping(' ')}, "1.024 KiB", 0, true},
+		{[]Option{WithDecimalComma(), WithGrouping('.')}, "1.024", 1024, false},
+		{[]Option{WithDecimalComma(), WithGrouping(',')}, "1", 0, true},
+		{[]Option{WithDecimalComma(), WithStrict()}, "1,5GiB", 3 * Gibibyte / 2, false},
+		{[]Option{WithDecimalComma(), WithStrict()}, "1.024", 0, true},
+		{[]Option{WithGrouping('x')}, "1", 0, true},
+		{[]Option{WithBase(Base(7))}, "4k", 0, true},
+		{[]Option{WithUnits("KiB", "kib")}, "4KiB", 0, true},
+		{[]Option{WithDefaultUnit("MiB")}, "512", 512 * Mebibyte, false},
+		{[]Option{WithDefaultUnit("MiB")}, "1.5", 3 * Mebibyte / 2, false},
+		{[]Option{WithDefaultUnit("MiB")}, "512 KiB", 512 * Kibibyte, false},
+		{[]Option{WithDefaultUnit("K"), WithBase(Base10)}, "4", 4 * Kilobyte, false},
+		{[]Option{WithDefaultUnit("K"), WithStrict()}, "0.5", 512, false},
+		{[]Option{WithDefaultUnit("K"), WithStrict()}, "0.1", 0, true},
+		{[]Option{WithDefaultUnit("kb"), WithMax(Size(Megabyte))}, "1001", 0, true},
+		{[]Option{WithDefaultUnit("MiB"), WithUnits("GiB")}, "1", 0, true},
+		{[]Option{WithDefaultUnit("MB"), WithStrictUnits()}, "1", Megabyte, false},
+		{[]Option{WithDefaultUnit("M"), WithStrictUnits()}, "1", 0, true},
+		{[]Option{WithDefaultUnit("Mb"), WithProfile(Network)}, "100", 12500000, false},
+		{[]Option{WithDefaultUnit("bytes")}, "1.5", 2, false},
+		{[]Option{WithDefaultUnit("x")}, "1", 0, true},
+		{[]Option{WithDefaultUnit("")}, "1", 0, true},
+	}
+
+	for _, test := range tests {
+		out, err := ParseSize(test.in, test.opts...)
+		if testing.Verbose() {
+			fmt.Printf("%q --> %v, %v\n", test.in, out, err)
+		}
+		if test.err {
+			require.Error(t, err, test.in)
+		} else {
+			require.NoError(t, err, test.in)
+			require.Equal(t, Size(test.out), out, test.in)
+		}
+	}
+}
+
+func TestStrictUnits(t *testing.T) {
+	var tests = []struct {
+		in  string
+		err string
+	}{
+		{"4K", `ambiguous units "K": use "KiB" for powers of 2 or "kB" for powers of 10`},
+		{"4KB", `ambiguous units "KB": use "KiB" for powers of 2 or "kB" for powers of 10`},
+		{"4Kb", `ambiguous units "Kb": use "KiB" for powers of 2 or "kB" for powers of 10`},
+		{"4 gb", `ambiguous units "gb": use "GiB" for powers of 2 or "GB" for powers of 10`},
+		{"4Ti", `nonstandard units "Ti": use "TiB"`},
+		{"4KiBs", `invalid units: did you mean "KiB"?`},
+	}
+
+	p, err := NewParser(WithStrictUnits())
+	require.NoError(t, err)
+	for _, test := range tests {
+		_, err := p.Parse(test.in)
+		if testing.Verbose() {
+			fmt.Printf("%q ==> %v\n", test.in, err)
+		}
+		require.EqualError(t, err, test.err)
+		require.Equal(t, test.err != "invalid units: did you mean \"KiB\"?",
+			errors.Is(err, ErrNonstandardUnits), test.in)
+		require.LessOrEqual(t, testing.AllocsPerRun(10, func() { p.Parse(test.in) }), 1.0, test.in)
+	}
+
+	// Every valid unit is either accepted or has an error.
+	for units := range unitMap {
+		_, ok := siUnits[units]
+		require.Equal(t, !ok, siErrors[units] != nil, units)
+	}
+}
+
+func TestParseError(t *testing.T) {
+	var tests = []struct {
+		opts       []Option
+		in         string
+		offset     int
+		err        error
+		suggestion string
+	}{
+		{nil, "4 MIB", 2, ErrInvalidUnits, "MiB"},
+		{nil, "1.", 2, ErrInvalidFraction, ""},
+		{nil, "1,00", 4, ErrGrouping, ""},
+		{[]Option{WithStrict()}, "0.1KiB", 0, ErrInexact, ""},
+		{[]Option{WithUnits("KiB")}, "4MiB", 1, ErrUnitsNotAllowed, ""},
+		{nil, "4MBi", 1, ErrInvalidUnits, "MiB"},
+		{nil, "4 megabyts", 2, ErrInvalidUnits, "megabytes"},
+		{nil, "4 quux", 2, ErrInvalidUnits, ""},
+		{nil, "  1.5", 5, ErrMissingUnits, ""},
+		{nil, "1__0", 1, ErrUnderscore, ""},
+		{nil, " x", 1, ErrNoNumber, ""},
+		{nil, "4/GiB", 1, ErrInvalidDelimiter, ""},
+		{nil, " 20EiB", 1, ErrOverflow, ""},
+		{[]Option{WithStrict()}, " 4GiB", 0, ErrWhitespace, ""},
+		{[]Option{WithStrict()}, "4GiB ", 4, ErrWhitespace, ""},
+		{[]Option{WithStrict()}, "1_024", 1, ErrSeparator, ""},
+		{[]Option{WithMax(Size(Gibibyte))}, "2GiB", 0, ErrTooLarge, ""},
+		{[]Option{WithProfile(Kubernetes)}, "4GiB", 1, ErrInvalidUnits, "Gi"},
+		{[]Option{WithProfile(JEDEC)}, "4 mb", 2, ErrInvalidUnits, "MB"},
+		{[]Option{WithProfile(Systemd)}, "1G x", 3, ErrNoNumber, ""},
+		{[]Option{WithProfile(Nginx)}, "10x", 2, ErrInvalidUnits, ""},
+	}
+
+	for _, test := range tests {
+		_, err := ParseSize(test.in, test.opts...)
+		if testing.Verbose() {
+			fmt.Printf("%q ==> %v\n", test.in, err)
+		}
+		var pe *ParseError
+		require.True(t, errors.As(err, &pe), test.in)
+		require.Equal(t, test.in, pe.Input)
+		require.Equal(t, test.offset, pe.Offset, test.in)
+		require.Equal(t, test.err, pe.Err, test.in)
+		require.Equal(t, test.suggestion, pe.Suggestion, test.in)
+		require.True(t, errors.Is(err, test.err), test.in)
+
+		// AsInt reports errors like the default parser.
+		if test.opts == nil {
+			_, asIntErr := AsInt(test.in)
+			require.Equal(t, err, asIntErr, test.in)
+		}
+	}
+
+	_, _, err := ParsePrefix("  x")
+	require.Equal(t, &ParseError{Input: "  x", Offset: 2, Err: ErrNoNumber}, err)
+}
+
+func TestMustParse(t *testing.T) {
+	require.Equal(t, Size(256*Mebibyte), MustParse("256MiB"))
+	require.Equal(t, Size(4*Kibibyte), MustParseSize("4kb", WithBase(Base2)))
+
+	require.PanicsWithValue(t, `bytez: MustParse("256MiBs"): invalid units: did you mean "MiB"?`, func() {
+		MustParse("256MiBs")
+	})
+	require.PanicsWithValue(t, `bytez: MustParseSize("2GiB"): size exceeds maximum`, func() {
+		MustParseSize("2GiB", WithMax(Size(Gibibyte)))
+	})
+}
+
+func TestParseBounds(t *testing.T) {
+	p, err := NewParser(WithMin(Size(4*Kibibyte)), WithMax(Size(10*Gibibyte)))
+	require.NoError(t, err)
+
+	size, err := p.Parse("10GiB")
+	require.NoError(t, err)
+	require.Equal(t, Size(10*Gibibyte), size)
+
+	_, err = p.Parse(" 1KiB")
+	require.EqualError(t, err, "size below minimum")
+	require.True(t, errors.Is(err, ErrTooSmall))
+	require.True(t, errors.Is(err, ErrOutOfRange))
+	require.Equal(t, 1, err.(*ParseError).Offset)
+
+	_, err = p.Parse("11GiB")
+	require.EqualError(t, err, "size exceeds maximum")
+	require.True(t, errors.Is(err, ErrTooLarge))
+	require.True(t, errors.Is(err, ErrOutOfRange))
+
+	_, err = NewParser(WithMin(Size(2*Kibibyte)), WithMax(Size(Kibibyte)))
+	require.EqualError(t, err, "minimum 2KiB exceeds maximum 1KiB")
+}
+
+func TestParserAllocs(t *testing.T) {
+	// Parsing allocates nothing when it succeeds and only the returned error when it fails.
+	requireAllocs := func(p *Parser, in string) {
+		_, err := p.Parse(in)
+		allocs := testing.AllocsPerRun(100, func() {
+			p.Parse(in)
+		})
+		if err == nil {
+			require.Zero(t, allocs, in)
+		} else {
+			require.Equal(t, 1.0, allocs, in)
+		}
+	}
+
+	var p Parser
+	for _, in := range []string{"4321", " 1_048_576 ", "4.5 GiB", "4.5 GiBs", "", "1.5e-3 KiB"} {
+		requireAllocs(&p, in)
+	}
+
+	strict, err := NewParser(WithStrict(), WithUnits("KiB", "MiB"), WithMax(Size(Gibibyte)))
+	require.NoError(t, err)
+	for _, in := range []string{"4.5MiB", " 4.5MiB", "4GiB", "2048MiB", "0.1KiB"} {
+		requireAllocs(strict, in)
+	}
+
+	folded, err := NewParser(WithIgnoreCase(Base2))
+	require.NoError(t, err)
+	for _, in := range []string{"4.5MIB", "4 gb", "4GiBs"} {
+		requireAllocs(folded, in)
+	}
+}
+
+func TestParseBytes(t *testing.T) {
+	strict, err := NewParser(WithStrict(), WithUnits("KiB", "MiB"), WithMax(Size(Gibibyte)))
+	require.NoError(t, err)
+	for _, p := range []*Parser{{}, strict} {
+		for _, in := range []string{"4321", "4.5MiB", " 4.5MiB", "4GiB", "2048MiB", "0.1KiB", ""} {
+			size, err := p.Parse(in)
+			out, errBytes := p.ParseBytes([]byte(in))
+			require.Equal(t, size, out, in)
+			require.Equal(t, err, errBytes, in)
+		}
+	}
+
+	// Parsing a byte slice allocates nothing when it succeeds.
+	in := []byte("4.5MiB")
+	require.Zero(t, testing.AllocsPerRun(100, func() { strict.ParseBytes(in) }))
+}
+
+func BenchmarkParser(b *testing.B) {
+	var p Parser
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		p.Parse("4.5 GiB")
+	}
+}
+
+func BenchmarkParserParallel(b *testing.B) {
+	var p Parser
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			p.Parse("4.5 GiB")
+		}
+	})
+}
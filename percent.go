@@ -0,0 +1,61 @@
+//go:build !bytez_minimal
+
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package bytez
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// ParsePercentOf parses a size that may be given as a percentage of total, like "25%" or
+// "12.5 %", so that configurations can say "cache = 10%" and have it resolved against, for
+// example, the memory of the machine when they are loaded. Other sizes have the syntax accepted by
+// AsInt and do not depend on total. Percentages are decimal numbers without exponents or digit
+// separators, may exceed 100%, and are rounded to the nearest whole number of bytes, with halves
+// rounded to even.
+func ParsePercentOf(str string, total Size) (Size, error) {
+	trimmed := strings.Trim(str, " \t\r\n")
+	pct, ok := strings.CutSuffix(trimmed, "%")
+	if !ok {
+		val, err := AsInt(str)
+		return Size(val), err
+	}
+
+	pct = strings.TrimSuffix(pct, " ")
+	if !isPercent(pct) {
+		return 0, fmt.Errorf("invalid percentage %q", str)
+	}
+	val, _ := new(big.Rat).SetString(pct)
+	val.Mul(val, new(big.Rat).SetUint64(uint64(total)))
+	val.Quo(val, big.NewRat(100, 1))
+	num, err := roundRat(val, RoundHalfEven)
+	if err != nil {
+		return 0, err
+	} else if !num.IsUint64() {
+		return 0, fmt.Errorf("percentage %q of %s is too large", str, AsStr(uint64(total)))
+	}
+	return Size(num.Uint64()), nil
+}
+
+// isPercent reports whether str is a decimal number without an exponent or digit separators, like
+// "25" or "12.5".
+func isPercent(str string) bool {
+	digits, point := 0, false
+	for i := 0; i < len(str); i++ {
+		if isDigit(str[i]) {
+			digits++
+		} else if str[i] == '.' && !point && digits > 0 {
+			point = true
+		} else {
+			return false
+		}
+	}
+	return digits > 0 && str[len(str)-1] != '.'
+}
@@ -0,0 +1,61 @@
+//go:build !bytez_minimal
+
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package bytez
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePercentOf(t *testing.T) {
+	total := Size(16 * Gibibyte)
+
+	var positive = []struct {
+		in  string
+		out uint64
+	}{
+		{"25%", 4 * Gibibyte},
+		{"12.5 %", 2 * Gibibyte},
+		{" 100% ", 16 * Gibibyte},
+		{"150%", 24 * Gibibyte},
+		{"0%", 0},
+		{"0.5%", 85899346},
+		{"0.0000000001%", 0},
+		{"512MiB", 512 * Mebibyte},
+		{" 1,024 ", 1024},
+	}
+
+	for _, test := range positive {
+		out, err := ParsePercentOf(test.in, total)
+		if testing.Verbose() {
+			fmt.Printf("%q of %v --> %v\n", test.in, total.AsStr(), out)
+		}
+		require.NoError(t, err, test.in)
+		require.Equal(t, Size(test.out), out, test.in)
+	}
+
+	// Halves are rounded to even.
+	out, err := ParsePercentOf("50%", 5)
+	require.NoError(t, err)
+	require.Equal(t, Size(2), out)
+	out, err = ParsePercentOf("50%", Size(1<<64-1))
+	require.NoError(t, err)
+	require.Equal(t, Size(1<<63), out)
+
+	for _, in := range []string{"%", "25%%", "-25%", "+25%", "25.%", ".5%", "1e2%", "1,000%", "25  %",
+		"25 percent", "200%", "512MiBs"} {
+		_, err := ParsePercentOf(in, Size(1<<64-1))
+		if testing.Verbose() {
+			fmt.Printf("%q ==> %v\n", in, err)
+		}
+		require.Error(t, err, in)
+	}
+}
@@ -0,0 +1,17 @@
+module github.com/nexvium/bytez/pgxsize
+
+go 1.25.0
+
+require (
+	github.com/jackc/pgx/v5 v5.11.0
+	github.com/nexvium/bytez v0.0.0-00010101000000-000000000000
+	github.com/stretchr/testify v1.11.1
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/nexvium/bytez => ../
@@ -0,0 +1,164 @@
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+// Package pgxsize adds support for bytez.Size to github.com/jackc/pgx/v5, so that sizes can be
+// used directly as query arguments and scan targets for bigint, numeric, and text columns, as
+// well as arrays of them, without going through the database/sql interfaces.
+//
+// Integer columns hold the exact number of bytes, while text columns hold the size as formatted
+// by bytez.AsCanonicalStr, like "64MiB". To use it, register the types with the connection's type map:
+//
+//	config.AfterConnect = func(ctx context.Context, conn *pgx.Conn) error {
+//		pgxsize.Register(conn.TypeMap())
+//		return nil
+//	}
+//
+// This package is a separate module so that package bytez itself does not depend on pgx.
+package pgxsize
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/nexvium/bytez"
+)
+
+// Size is a bytez.Size that implements the pgtype scanner and valuer interfaces. It is used
+// internally by the plans installed by Register but can also be used directly.
+type Size bytez.Size
+
+var errNull = errors.New("cannot scan NULL into bytez.Size")
+
+// ScanInt64 implements the pgtype.Int64Scanner interface.
+func (sz *Size) ScanInt64(v pgtype.Int8) error {
+	if !v.Valid {
+		return errNull
+	} else if v.Int64 < 0 {
+		return fmt.Errorf("%d is negative and not a valid size", v.Int64)
+	}
+
+	*sz = Size(v.Int64)
+	return nil
+}
+
+// Int64Value implements the pgtype.Int64Valuer interface. An error is returned if the size is
+// too large for a bigint.
+func (sz Size) Int64Value() (pgtype.Int8, error) {
+	if uint64(sz) > math.MaxInt64 {
+		return pgtype.Int8{}, fmt.Errorf("%d is greater than maximum value for bigint", uint64(sz))
+	}
+	return pgtype.Int8{Int64: int64(sz), Valid: true}, nil
+}
+
+// ScanNumeric implements the pgtype.NumericScanner interface. The value must be a whole,
+// non-negative number that fits in 64 bits.
+func (sz *Size) ScanNumeric(v pgtype.Numeric) error {
+	if !v.Valid {
+		return errNull
+	} else if v.NaN || v.InfinityModifier != pgtype.Finite {
+		return errors.New("cannot scan NaN or infinity into bytez.Size")
+	}
+
+	num := new(big.Int).Set(v.Int)
+	if v.Exp > 0 {
+		num.Mul(num, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(v.Exp)), nil))
+	} else if v.Exp < 0 {
+		var rem big.Int
+		num.QuoRem(num, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(-v.Exp)), nil), &rem)
+		if rem.Sign() != 0 {
+			return errors.New("cannot scan fractional number into bytez.Size")
+		}
+	}
+	if num.Sign() < 0 || !num.IsUint64() {
+		return fmt.Errorf("%s is not a valid size", num)
+	}
+
+	*sz = Size(num.Uint64())
+	return nil
+}
+
+// NumericValue implements the pgtype.NumericValuer interface.
+func (sz Size) NumericValue() (pgtype.Numeric, error) {
+	return pgtype.Numeric{Int: new(big.Int).SetUint64(uint64(sz)), Valid: true}, nil
+}
+
+// ScanText implements the pgtype.TextScanner interface. The text can be any size accepted by
+// bytez.AsInt, or the text form of a whole number, like "1024.000", since pgx also scans integer
+// and numeric columns in text format through this method.
+func (sz *Size) ScanText(v pgtype.Text) error {
+	if !v.Valid {
+		return errNull
+	}
+
+	val, err := bytez.AsInt(v.String)
+	if err != nil {
+		num, ok := new(big.Rat).SetString(v.String)
+		if !ok || !num.IsInt() || num.Sign() < 0 || !num.Num().IsUint64() {
+			return err
+		}
+		val = num.Num().Uint64()
+	}
+
+	*sz = Size(val)
+	return nil
+}
+
+// String returns the size formatted by bytez.AsCanonicalStr, which does not depend on the
+// default format of bytez.AsStr. It is used by pgx to encode sizes for text columns. (Size does
+// not implement pgtype.TextValuer because pgx would then use the formatted size for integer
+// columns too when using the text format.)
+func (sz Size) String() string {
+	return bytez.AsCanonicalStr(uint64(sz))
+}
+
+// Register registers bytez.Size with m. Values of type bytez.Size and []bytez.Size default to
+// the bigint and bigint[] types when their type cannot be inferred from the query.
+func Register(m *pgtype.Map) {
+	m.TryWrapEncodePlanFuncs = append([]pgtype.TryWrapEncodePlanFunc{TryWrapEncodePlan}, m.TryWrapEncodePlanFuncs...)
+	m.TryWrapScanPlanFuncs = append([]pgtype.TryWrapScanPlanFunc{TryWrapScanPlan}, m.TryWrapScanPlanFuncs...)
+
+	m.RegisterDefaultPgType(bytez.Size(0), "int8")
+	m.RegisterDefaultPgType([]bytez.Size(nil), "_int8")
+}
+
+// TryWrapEncodePlan is a pgtype.TryWrapEncodePlanFunc that wraps bytez.Size values in Size.
+func TryWrapEncodePlan(value any) (plan pgtype.WrappedEncodePlanNextSetter, nextValue any, ok bool) {
+	if value, ok := value.(bytez.Size); ok {
+		return &wrapEncodePlan{}, Size(value), true
+	}
+	return nil, nil, false
+}
+
+type wrapEncodePlan struct {
+	next pgtype.EncodePlan
+}
+
+func (plan *wrapEncodePlan) SetNext(next pgtype.EncodePlan) { plan.next = next }
+
+func (plan *wrapEncodePlan) Encode(value any, buf []byte) (newBuf []byte, err error) {
+	return plan.next.Encode(Size(value.(bytez.Size)), buf)
+}
+
+// TryWrapScanPlan is a pgtype.TryWrapScanPlanFunc that wraps *bytez.Size targets in *Size.
+func TryWrapScanPlan(target any) (plan pgtype.WrappedScanPlanNextSetter, nextDst any, ok bool) {
+	if target, ok := target.(*bytez.Size); ok {
+		return &wrapScanPlan{}, (*Size)(target), true
+	}
+	return nil, nil, false
+}
+
+type wrapScanPlan struct {
+	next pgtype.ScanPlan
+}
+
+func (plan *wrapScanPlan) SetNext(next pgtype.ScanPlan) { plan.next = next }
+
+func (plan *wrapScanPlan) Scan(src []byte, dst any) error {
+	return plan.next.Scan(src, (*Size)(dst.(*bytez.Size)))
+}
@@ -0,0 +1,119 @@
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package pgxsize
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/nexvium/bytez"
+	"github.com/stretchr/testify/require"
+)
+
+func newMap() *pgtype.Map {
+	m := pgtype.NewMap()
+	Register(m)
+	return m
+}
+
+func TestEncode(t *testing.T) {
+	m := newMap()
+
+	var tests = []struct {
+		oid    uint32
+		format int16
+		in     interface{}
+		out    string
+	}{
+		{pgtype.Int8OID, pgtype.TextFormatCode, bytez.Size(64 * bytez.Mebibyte), "67108864"},
+		{pgtype.NumericOID, pgtype.TextFormatCode, bytez.Size(1<<64 - 1), "18446744073709551615"},
+		{pgtype.TextOID, pgtype.TextFormatCode, bytez.Size(64 * bytez.Mebibyte), "64MiB"},
+		{pgtype.Int8ArrayOID, pgtype.TextFormatCode, []bytez.Size{1024, 2048}, "{1024,2048}"},
+		{pgtype.TextArrayOID, pgtype.TextFormatCode, []bytez.Size{1024, 2000}, "{1KiB,2kb}"},
+	}
+
+	for _, test := range tests {
+		buf, err := m.Encode(test.oid, test.format, test.in, nil)
+		if testing.Verbose() {
+			fmt.Printf("%v --> %s\n", test.in, buf)
+		}
+		require.NoError(t, err)
+		require.Equal(t, test.out, string(buf))
+	}
+
+	_, err := m.Encode(pgtype.Int8OID, pgtype.BinaryFormatCode, bytez.Size(1<<64-1), nil)
+	require.Error(t, err)
+}
+
+func TestScan(t *testing.T) {
+	m := newMap()
+
+	var tests = []struct {
+		oid uint32
+		in  string
+		out bytez.Size
+	}{
+		{pgtype.Int8OID, "67108864", bytez.Size(64 * bytez.Mebibyte)},
+		{pgtype.NumericOID, "18446744073709551615", bytez.Size(1<<64 - 1)},
+		{pgtype.NumericOID, "1024.000", bytez.Size(1024)},
+		{pgtype.TextOID, "64MiB", bytez.Size(64 * bytez.Mebibyte)},
+	}
+
+	for _, test := range tests {
+		var out bytez.Size
+		err := m.Scan(test.oid, pgtype.TextFormatCode, []byte(test.in), &out)
+		if testing.Verbose() {
+			fmt.Printf("%s --> %v\n", test.in, out)
+		}
+		require.NoError(t, err)
+		require.Equal(t, test.out, out)
+	}
+
+	var negative = []struct {
+		oid uint32
+		in  []byte
+	}{
+		{pgtype.Int8OID, []byte("-1")},
+		{pgtype.Int8OID, nil},
+		{pgtype.NumericOID, []byte("1.5")},
+		{pgtype.TextOID, []byte("64 MiBs")},
+	}
+
+	for _, test := range negative {
+		var out bytez.Size
+		err := m.Scan(test.oid, pgtype.TextFormatCode, test.in, &out)
+		if testing.Verbose() {
+			fmt.Printf("%q ==> %v\n", test.in, err)
+		}
+		require.Error(t, err)
+	}
+
+	var list []bytez.Size
+	err := m.Scan(pgtype.Int8ArrayOID, pgtype.TextFormatCode, []byte("{1024,2048}"), &list)
+	require.NoError(t, err)
+	require.Equal(t, []bytez.Size{1024, 2048}, list)
+
+	var ptr *bytez.Size
+	err = m.Scan(pgtype.Int8OID, pgtype.TextFormatCode, nil, &ptr)
+	require.NoError(t, err)
+	require.Nil(t, ptr)
+}
+
+func TestBinaryRoundTrip(t *testing.T) {
+	m := newMap()
+
+	for _, oid := range []uint32{pgtype.Int8OID, pgtype.NumericOID, pgtype.TextOID} {
+		in := bytez.Size(3*bytez.Gibibyte + bytez.Gibibyte/2)
+		buf, err := m.Encode(oid, pgtype.BinaryFormatCode, in, nil)
+		require.NoError(t, err)
+
+		var out bytez.Size
+		require.NoError(t, m.Scan(oid, pgtype.BinaryFormatCode, buf, &out))
+		require.Equal(t, in, out)
+	}
+}
@@ -0,0 +1,58 @@
+//go:build !bytez_minimal
+
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package bytez
+
+// ParsePrefix parses the size at the start of s, like "64MiB" in "64MiB or less", and returns it
+// along with the rest of s, so that sizes can be embedded in larger grammars, like query
+// languages or command-line mini-languages, without tokenizing them first. Leading whitespace is
+// skipped.
+//
+// The size has the syntax accepted by AsInt, except that commas do not separate digit groups, so
+// that "1,200" parses as 1 with the rest ",200" in comma-separated lists. Units, optionally
+// preceded by a single space, are consumed only if the whole run of letters that follows the
+// number is a valid unit, so "10 items" parses as 10 bytes with the rest " items", and "10kbps"
+// as 10 bytes with the rest "kbps"; callers should check that the rest starts where they expect.
+func ParsePrefix(s string) (Size, string, error) {
+	idx := 0
+	for idx < len(s) && (s[idx] == ' ' || s[idx] == '\t' || s[idx] == '\r' || s[idx] == '\n') {
+		idx++
+	}
+
+	start := idx
+	num, end, err := scanNumber(s[idx:], 0, '.')
+	if err != nil {
+		return 0, s, &ParseError{Input: s, Offset: idx + end, Err: err}
+	}
+	idx += end
+
+	unitStart := idx
+	if unitStart < len(s) && s[unitStart] == ' ' {
+		unitStart++
+	}
+	unitEnd := unitStart
+	for unitEnd < len(s) && isLetter(s[unitEnd]) {
+		unitEnd++
+	}
+
+	if val, ok := lookupUnit(s[unitStart:unitEnd]); ok && unitEnd > unitStart {
+		size, err := num.bytes(val)
+		if err != nil {
+			return 0, s, &ParseError{Input: s, Offset: start, Err: err}
+		}
+		return Size(size), s[unitEnd:], nil
+	}
+
+	size, err := num.integer()
+	if err == ErrMissingUnits {
+		return 0, s, &ParseError{Input: s, Offset: idx, Err: err}
+	} else if err != nil {
+		return 0, s, &ParseError{Input: s, Offset: start, Err: err}
+	}
+	return Size(size), s[idx:], nil
+}
@@ -0,0 +1,69 @@
+//go:build !bytez_minimal
+
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package bytez
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePrefix(t *testing.T) {
+	var positive = []struct {
+		in   string
+		size uint64
+		rest string
+	}{
+		{"64MiB", 64 * Mebibyte, ""},
+		{"64MiB or less", 64 * Mebibyte, " or less"},
+		{"  1.5 GiB)", 3 * Gibibyte / 2, ")"},
+		{"4kb,8kb", 4 * Kilobyte, ",8kb"},
+		{"1_024+1", 1024, "+1"},
+		{"10 items", 10, " items"},
+		{"10kbps", 10, "kbps"},
+		{"10  MiB", 10, "  MiB"},
+		{"2.0mb/s", 2 * Megabyte, "/s"},
+		{"7", 7, ""},
+		{"1,200", 1, ",200"},
+		{"1.5e3 KiB!", 1536000, "!"},
+		{"2.75GiB.", 11 * Gibibyte / 4, "."},
+	}
+
+	for _, test := range positive {
+		size, rest, err := ParsePrefix(test.in)
+		if testing.Verbose() {
+			fmt.Printf("%q --> %v, %q\n", test.in, size, rest)
+		}
+		require.NoError(t, err, test.in)
+		require.Equal(t, Size(test.size), size, test.in)
+		require.Equal(t, test.rest, rest, test.in)
+	}
+
+	var negative = []string{"", "  ", "MiB", "_1kb", "1_ kb", "1.", "1.25", "16EiB", "1.5 items", "1.5"}
+
+	for _, in := range negative {
+		_, rest, err := ParsePrefix(in)
+		if testing.Verbose() {
+			fmt.Printf("%q ==> %v\n", in, err)
+		}
+		require.Error(t, err, in)
+		require.Equal(t, in, rest)
+	}
+
+	// Every size accepted by AsInt is consumed entirely.
+	for _, in := range []string{"1k", "4_096 KiB", "15EiB", "2.5 GiB", "1.0mb", "1.25kb"} {
+		want, err := AsInt(in)
+		require.NoError(t, err)
+		size, rest, err := ParsePrefix(in)
+		require.NoError(t, err)
+		require.Equal(t, Size(want), size)
+		require.Empty(t, rest)
+	}
+}
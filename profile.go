@@ -0,0 +1,373 @@
+//go:build !bytez_minimal
+
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package bytez
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// A Profile is a set of units and their meanings, following the conventions of a tool or field
+// instead of those of this package. WithProfile makes a Parser use the units of a profile, so that
+// sizes copied from other configuration files keep their meaning, and FormatOptions.Profile makes
+// a Formatter write sizes in them.
+type Profile struct {
+	name  string
+	units map[string]uint64
+
+	// fold matches units regardless of case; units holds their lowercase spellings.
+	fold bool
+
+	// strict rejects the variations of syntax that WithStrict rejects, other than a space before
+	// the units if space is set, and fractions of a byte, which are rounded according to
+	// rounding. If fractions is set, numbers without units may also have fractions of a byte.
+	strict    bool
+	space     bool
+	rounding  RoundingMode
+	fractions bool
+
+	// names are the names of the binary units used to format sizes, as in unitsBase2. If whole is
+	// set, exact sizes are formatted as whole numbers of units, like "1536k" instead of "1.5m".
+	names []string
+	whole bool
+
+	// parse, if set, parses sizes instead of the Parser, with unit as the value of numbers
+	// without units, and returns the offset of errors.
+	parse func(str string, unit uint64) (uint64, int, error)
+}
+
+// String returns the name of the profile, like "network".
+func (p *Profile) String() string {
+	return p.name
+}
+
+// lookup returns the number of bytes in the given units of the profile.
+func (p *Profile) lookup(units string) (uint64, bool) {
+	if !p.fold {
+		val, ok := p.units[units]
+		return val, ok
+	}
+
+	var buf [8]byte
+	if len(units) > len(buf) {
+		return 0, false
+	}
+	for i := 0; i < len(units); i++ {
+		b := units[i]
+		if b >= 'A' && b <= 'Z' {
+			b += 'a' - 'A'
+		}
+		buf[i] = b
+	}
+	val, ok := p.units[string(buf[:len(units)])]
+	return val, ok
+}
+
+// Network is the profile of networking, where bandwidths and link speeds are given in bits: units
+// ending in a lowercase "b" are bits with decimal prefixes regardless of case, so "100Mb" and
+// "100mb" are 100 megabits, or 12,500,000 bytes, and "10Gb" is 1,250,000,000 bytes. Other units
+// have their usual meanings, so "1.5MB" is still 1.5 mebibytes.
+var Network = &Profile{name: "network", names: unitsBase2, units: func() map[string]uint64 {
+	units := make(map[string]uint64, len(unitMap))
+	for u, val := range unitMap {
+		units[u] = val
+	}
+	for i := 0; i < len(bitPrefixes); i++ {
+		prefix := bitPrefixes[i : i+1]
+		units[strings.ToLower(prefix)+"b"] = valuesBase10[i+1] / 8
+		units[strings.ToUpper(prefix)+"b"] = valuesBase10[i+1] / 8
+	}
+	return units
+}()}
+
+// Kubernetes is the profile of the quantities of Kubernetes resources, like memory requests and
+// limits: the binary suffixes "Ki", "Mi", "Gi", "Ti", "Pi", and "Ei", the decimal suffixes "k",
+// "M", "G", "T", "P", and "E", and exponents, like "128974848", "129e6", "129M", and "123Mi".
+// As in Kubernetes, there is no space between the number and the suffix, digits cannot be
+// separated, and fractions of a byte are rounded up, so "1.5" is 2 bytes. The suffix "m" for
+// thousandths is not accepted, as it makes no sense for bytes.
+var Kubernetes = &Profile{name: "kubernetes", strict: true, rounding: RoundCeil, fractions: true,
+	names: []string{"", "Ki", "Mi", "Gi", "Ti", "Pi", "Ei"}, units: map[string]uint64{
+		"Ki": Kibibyte, "Mi": Mebibyte, "Gi": Gibibyte, "Ti": Tebibyte, "Pi": Pebibyte, "Ei": Exbibyte,
+		"k": Kilobyte, "M": Megabyte, "G": Gigabyte, "T": Terabyte, "P": Petabyte, "E": Exabyte,
+	},
+}
+
+// Docker is the profile of the memory sizes of Docker and Docker Compose, like "512m" and "2g",
+// as parsed by the go-units package: the units "k", "m", "g", "t", and "p" are binary regardless
+// of case, optionally followed by "b" or "ib", as in "512mb" or "2GiB", and "b" is bytes. A single
+// space is allowed before the units, digits cannot be separated, and fractions of a byte are
+// rounded down, so "1.5" is 1 byte.
+var Docker = &Profile{name: "docker", fold: true, strict: true, space: true, rounding: RoundFloor,
+	fractions: true, names: unitsBase2, units: func() map[string]uint64 {
+		units := map[string]uint64{"b": 1}
+		for i, prefix := range "kmgtp" {
+			units[string(prefix)] = valuesBase2[i+1]
+			units[string(prefix)+"b"] = valuesBase2[i+1]
+			units[string(prefix)+"ib"] = valuesBase2[i+1]
+		}
+		return units
+	}(),
+}
+
+// Systemd is the profile of the sizes in systemd unit files, like "MemoryMax=512M", as parsed by
+// its parse_size function: the units "K", "M", "G", "T", "P", and "E" are binary and must be
+// uppercase, and "B" is bytes. Whitespace is allowed before the units, and a size may have several
+// components in decreasing units, like "1G 512M". Fractions of a byte are rounded down, and
+// digits cannot be separated.
+var Systemd = &Profile{name: "systemd", parse: parseSystemd,
+	names: []string{"", "K", "M", "G", "T", "P", "E"}, units: func() map[string]uint64 {
+		units := make(map[string]uint64, len(systemdUnits))
+		for _, u := range systemdUnits {
+			if u.suffix != "" {
+				units[u.suffix] = u.value
+			}
+		}
+		return units
+	}()}
+
+// systemdUnits are the units of the Systemd profile, in the order in which the components of a
+// size must use them. The last one is for numbers without units.
+var systemdUnits = []struct {
+	suffix string
+	value  uint64
+}{
+	{"E", Exbibyte}, {"P", Pebibyte}, {"T", Tebibyte}, {"G", Gibibyte}, {"M", Mebibyte},
+	{"K", Kibibyte}, {"B", 1}, {"", 1},
+}
+
+// parseSystemd parses str as systemd does, using unit for numbers without units.
+func parseSystemd(str string, unit uint64) (uint64, int, error) {
+	var total uint64
+	for idx, next := 0, 0; ; {
+		idx += spaces(str[idx:])
+		start := idx
+		for idx < len(str) && isDigit(str[idx]) {
+			idx++
+		}
+		if idx == start {
+			return 0, idx, ErrNoNumber
+		}
+		num := number{point: idx - start}
+		if idx < len(str) && str[idx] == '.' {
+			for idx++; idx < len(str) && isDigit(str[idx]); idx++ {
+			}
+		}
+		num.text = str[start:idx]
+		idx += spaces(str[idx:])
+
+		// The empty suffix of numbers without units matches anything left, as in systemd.
+		i := next
+		for i < len(systemdUnits) && !strings.HasPrefix(str[idx:], systemdUnits[i].suffix) {
+			i++
+		}
+		if i == len(systemdUnits) {
+			return 0, idx, ErrInvalidUnits
+		}
+		value := systemdUnits[i].value
+		if systemdUnits[i].suffix == "" {
+			value = unit
+		}
+
+		val, err := num.round(value, RoundFloor)
+		if err != nil {
+			return 0, start, err
+		}
+		var carry uint64
+		if total, carry = bits.Add64(total, val, 0); carry != 0 {
+			return 0, start, ErrOverflow
+		}
+
+		idx += len(systemdUnits[i].suffix)
+		next = i + 1
+		if idx == len(str) {
+			return total, 0, nil
+		}
+	}
+}
+
+// spaces returns the number of whitespace characters at the start of str.
+func spaces(str string) int {
+	n := 0
+	for n < len(str) && (str[n] == ' ' || str[n] == '\t' || str[n] == '\n' || str[n] == '\r') {
+		n++
+	}
+	return n
+}
+
+// JEDEC is the profile of the JEDEC memory standards, followed by Windows and much legacy
+// software, in which "KB", "MB", and "GB" are binary units, as are "TB", "PB", and "EB" by
+// extension, and "B" is bytes. Formatters with this profile use the same units, like "1.5GB"
+// for 1.5 gibibytes.
+var JEDEC = &Profile{name: "jedec", names: []string{"", "KB", "MB", "GB", "TB", "PB", "EB"},
+	units: map[string]uint64{
+		"B": 1, "KB": Kibibyte, "MB": Mebibyte, "GB": Gibibyte, "TB": Tebibyte, "PB": Pebibyte,
+		"EB": Exbibyte,
+	},
+}
+
+// IEC80000 is the profile of the units of ISO/IEC 80000-13, for organizations whose policies
+// require standard symbols: "B" for bytes, the decimal units "kB", "MB", "GB", "TB", "PB", and
+// "EB", and the binary units "KiB", "MiB", "GiB", "TiB", "PiB", and "EiB", in exactly that case.
+// Other units, including full words, are rejected. A single space is allowed between the number
+// and the units, as the standard recommends, but no other whitespace, and digits cannot be
+// separated.
+var IEC80000 = &Profile{name: "iec80000", strict: true, space: true, names: unitsBase2,
+	units: map[string]uint64{
+		"B": 1, "kB": Kilobyte, "MB": Megabyte, "GB": Gigabyte, "TB": Terabyte, "PB": Petabyte,
+		"EB": Exabyte, "KiB": Kibibyte, "MiB": Mebibyte, "GiB": Gibibyte, "TiB": Tebibyte,
+		"PiB": Pebibyte, "EiB": Exbibyte,
+	},
+}
+
+// Nginx is the profile of the sizes in nginx configuration files, like "client_max_body_size
+// 10m", as parsed by nginx: a whole number of bytes optionally followed by "k", "m", or "g" in
+// either case, all binary, with no space or other characters. The same sizes are accepted by the
+// Apache HTTP Server, whose sizes are plain numbers of bytes.
+var Nginx = &Profile{name: "nginx", parse: parseNginx, names: []string{"", "k", "m", "g"}, whole: true,
+	units: nginxUnits}
+
+// nginxUnits are the units of the Nginx profile.
+var nginxUnits = map[string]uint64{
+	"k": Kibibyte, "K": Kibibyte, "m": Mebibyte, "M": Mebibyte, "g": Gibibyte, "G": Gibibyte,
+}
+
+// parseNginx parses str as nginx does, using unit for numbers without units.
+func parseNginx(str string, unit uint64) (uint64, int, error) {
+	end := len(str)
+	if end > 0 && !isDigit(str[end-1]) {
+		var ok bool
+		if unit, ok = nginxUnits[str[end-1:]]; !ok {
+			return 0, end - 1, ErrInvalidUnits
+		}
+		end--
+	}
+
+	var val uint64
+	for i := 0; i < end; i++ {
+		if !isDigit(str[i]) {
+			return 0, i, ErrInvalidDelimiter
+		}
+		hi, lo := bits.Mul64(val, 10)
+		lo, carry := bits.Add64(lo, uint64(str[i]-'0'), 0)
+		if hi != 0 || carry != 0 {
+			return 0, 0, ErrOverflow
+		}
+		val = lo
+	}
+	if end == 0 {
+		return 0, 0, ErrNoNumber
+	}
+
+	hi, val := bits.Mul64(val, unit)
+	if hi != 0 {
+		return 0, 0, ErrOverflow
+	}
+	return val, 0, nil
+}
+
+// appendWhole appends size to dst in the largest of the binary units with the given names in
+// which it is a whole number.
+func appendWhole(dst []byte, size uint64, sep string, names []string) []byte {
+	idx := len(names) - 1
+	for idx > 0 && (size < valuesBase2[idx] || size%valuesBase2[idx] != 0) {
+		idx--
+	}
+	dst = strconv.AppendUint(dst, size/valuesBase2[idx], 10)
+	if idx == 0 {
+		return dst
+	}
+	dst = append(dst, sep...)
+	return append(dst, names[idx]...)
+}
+
+// WithProfile makes the parser accept the units of the given profile instead of the units of
+// this package. It cannot be combined with WithBase, WithStrictUnits, or WithIgnoreCase.
+func WithProfile(profile *Profile) Option {
+	return func(p *Parser) error {
+		if profile == nil {
+			return errors.New("nil profile")
+		}
+		p.profile = profile
+		return nil
+	}
+}
+
+// Default is the profile of the units of this package, as accepted by AsInt, for selecting them
+// by name from Profiles. Sizes are formatted in binary units, like "1.5MiB".
+var Default = &Profile{name: "default", units: unitMap, names: unitsBase2}
+
+// SIStrict is the profile of the standard units accepted by WithStrictUnits, like "kB" and "KiB",
+// for selecting them by name from Profiles. Sizes are formatted in binary units, like "1.5MiB".
+var SIStrict = &Profile{name: "si-strict", units: siUnits, names: unitsBase2}
+
+// A ProfileRegistry maps names to profiles, so that applications can select how sizes are parsed
+// and formatted from their own configuration. Names are case insensitive. A ProfileRegistry is
+// safe for concurrent use.
+type ProfileRegistry struct {
+	mu       sync.RWMutex
+	profiles map[string]*Profile
+}
+
+// Profiles holds the profiles of this package under their names, like "docker" and "jedec", and
+// "k8s" for Kubernetes. Applications may register other names.
+var Profiles = &ProfileRegistry{profiles: map[string]*Profile{
+	"default": Default, "si-strict": SIStrict, "network": Network, "kubernetes": Kubernetes,
+	"k8s": Kubernetes, "docker": Docker, "systemd": Systemd, "jedec": JEDEC,
+	"iec80000": IEC80000, "nginx": Nginx, "coreutils": Coreutils,
+}}
+
+// Get returns the profile registered under the given name, like "docker".
+func (r *ProfileRegistry) Get(name string) (*Profile, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if profile, ok := r.profiles[strings.ToLower(name)]; ok {
+		return profile, nil
+	}
+	return nil, fmt.Errorf("unknown profile %q", name)
+}
+
+// Register adds a name for a profile, like "containers" for Docker. The name must not be empty or
+// already registered.
+func (r *ProfileRegistry) Register(name string, profile *Profile) error {
+	if name == "" {
+		return errors.New("empty profile name")
+	} else if profile == nil {
+		return errors.New("nil profile")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	name = strings.ToLower(name)
+	if _, ok := r.profiles[name]; ok {
+		return fmt.Errorf("profile %q already registered", name)
+	}
+	if r.profiles == nil {
+		r.profiles = make(map[string]*Profile)
+	}
+	r.profiles[name] = profile
+	return nil
+}
+
+// Names returns the registered names in sorted order.
+func (r *ProfileRegistry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.profiles))
+	for name := range r.profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,469 @@
+//go:build !bytez_minimal
+
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package bytez
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBitUnits(t *testing.T) {
+	var tests = []struct {
+		in  string
+		out uint64
+	}{
+		{"100Mbit", 12500000},
+		{"10 Gbit", 1250000000},
+		{"1kbit", 125},
+		{"1.5kbit", 188},
+		{"64 Kibit", 8192},
+		{"1Eibit", Exbibyte / 8},
+		{"8Ebit", Exabyte},
+	}
+
+	for _, test := range tests {
+		out, err := AsInt(test.in)
+		if testing.Verbose() {
+			fmt.Printf("%q --> %v %v\n", test.in, out, err)
+		}
+		require.NoError(t, err, test.in)
+		require.Equal(t, test.out, out, test.in)
+	}
+
+	// Bit units name their base explicitly.
+	size, err := ParseSize("1Mibit", WithBase(Base10))
+	require.NoError(t, err)
+	require.Equal(t, Size(Mebibyte/8), size)
+
+	size, err = ParseSize("1 MBIT", WithIgnoreCase(Base2))
+	require.NoError(t, err)
+	require.Equal(t, Size(Megabyte/8), size)
+
+	size, err = ParseSize("1Gbit", WithStrictUnits())
+	require.NoError(t, err)
+	require.Equal(t, Size(Gigabyte/8), size)
+
+	_, err = ParseSize("1.5kbit", WithStrict())
+	require.Error(t, err)
+}
+
+func TestNetworkProfile(t *testing.T) {
+	var tests = []struct {
+		in  string
+		out uint64
+	}{
+		{"10Gb", 1250000000},
+		{"100Mb", 12500000},
+		{"100mb", 12500000},
+		{"56 kb", 7000},
+		{"56Kb", 7000},
+		{"1.5MB", Mebibyte + Mebibyte/2},
+		{"1kB", Kilobyte},
+		{"1 Mbit", 125000},
+		{"4 megabytes", 4 * Megabyte},
+		{"1024", 1024},
+	}
+
+	p, err := NewParser(WithProfile(Network))
+	require.NoError(t, err)
+	require.Equal(t, "network", Network.String())
+
+	for _, test := range tests {
+		out, err := p.Parse(test.in)
+		if testing.Verbose() {
+			fmt.Printf("%q --> %v %v\n", test.in, out, err)
+		}
+		require.NoError(t, err, test.in)
+		require.Equal(t, Size(test.out), out, test.in)
+	}
+
+	_, err = p.Parse("1 b")
+	require.Error(t, err)
+
+	for _, opt := range []Option{WithBase(Base2), WithStrictUnits(), WithIgnoreCase(Base10)} {
+		_, err = NewParser(WithProfile(Network), opt)
+		require.EqualError(t, err, "network profile cannot be combined with other unit options")
+	}
+	_, err = NewParser(WithProfile(nil))
+	require.Error(t, err)
+}
+
+func TestKubernetesProfile(t *testing.T) {
+	var tests = []struct {
+		in  string
+		out uint64
+		err bool
+	}{
+		{"128974848", 128974848, false},
+		{"129e6", 129000000, false},
+		{"129M", 129000000, false},
+		{"123Mi", 123 * Mebibyte, false},
+		{"512Mi", 512 * Mebibyte, false},
+		{"4Gi", 4 * Gibibyte, false},
+		{"100k", 100000, false},
+		{"2G", 2000000000, false},
+		{"1E", Exabyte, false},
+		{"1E3", 1000, false},
+		{"1.5", 2, false},
+		{"0.1Ki", 103, false},
+		{"1.5e-3", 1, false},
+		{"4 Gi", 0, true},
+		{" 4Gi", 0, true},
+		{"4GiB", 0, true},
+		{"4K", 0, true},
+		{"4m", 0, true},
+		{"1,000", 0, true},
+		{"1_000", 0, true},
+	}
+
+	p, err := NewParser(WithProfile(Kubernetes))
+	require.NoError(t, err)
+
+	for _, test := range tests {
+		out, err := p.Parse(test.in)
+		if testing.Verbose() {
+			fmt.Printf("%q --> %v, %v\n", test.in, out, err)
+		}
+		if test.err {
+			require.Error(t, err, test.in)
+		} else {
+			require.NoError(t, err, test.in)
+			require.Equal(t, Size(test.out), out, test.in)
+		}
+	}
+}
+
+func TestDockerProfile(t *testing.T) {
+	var tests = []struct {
+		in  string
+		out uint64
+		err bool
+	}{
+		{"512m", 512 * Mebibyte, false},
+		{"2g", 2 * Gibibyte, false},
+		{"2G", 2 * Gibibyte, false},
+		{"100k", 100 * Kibibyte, false},
+		{"512mb", 512 * Mebibyte, false},
+		{"512 MB", 512 * Mebibyte, false},
+		{"2GiB", 2 * Gibibyte, false},
+		{"1t", Tebibyte, false},
+		{"1P", Pebibyte, false},
+		{"10b", 10, false},
+		{"10B", 10, false},
+		{"1024", 1024, false},
+		{"1.5", 1, false},
+		{"1.5k", 1536, false},
+		{"0.1k", 102, false},
+		{"1e3", 1000, false},
+		{"1e", 0, true},
+		{"1x", 0, true},
+		{"1gibb", 0, true},
+		{"1kbit", 0, true},
+		{" 1g", 0, true},
+		{"1  g", 0, true},
+		{"1,000", 0, true},
+	}
+
+	p, err := NewParser(WithProfile(Docker))
+	require.NoError(t, err)
+
+	for _, test := range tests {
+		out, err := p.Parse(test.in)
+		if testing.Verbose() {
+			fmt.Printf("%q --> %v, %v\n", test.in, out, err)
+		}
+		if test.err {
+			require.Error(t, err, test.in)
+		} else {
+			require.NoError(t, err, test.in)
+			require.Equal(t, Size(test.out), out, test.in)
+		}
+	}
+}
+
+func TestSystemdProfile(t *testing.T) {
+	var tests = []struct {
+		in  string
+		out uint64
+		err bool
+	}{
+		{"512M", 512 * Mebibyte, false},
+		{"4G", 4 * Gibibyte, false},
+		{"1K", Kibibyte, false},
+		{"1E", Exbibyte, false},
+		{"100B", 100, false},
+		{"100", 100, false},
+		{"1.5G", 3 * Gibibyte / 2, false},
+		{"1.5", 1, false},
+		{"0.1K", 102, false},
+		{"1.", 1, false},
+		{"4 G", 4 * Gibibyte, false},
+		{" 4G", 4 * Gibibyte, false},
+		{"1G 512M", Gibibyte + 512*Mebibyte, false},
+		{"1G512M", Gibibyte + 512*Mebibyte, false},
+		{"1G 512M 100", Gibibyte + 512*Mebibyte + 100, false},
+		{"16E", 0, true},
+		{"15E 1024P", 0, true},
+		{"512M 1G", 0, true},
+		{"1G 1G", 0, true},
+		{"4g", 0, true},
+		{"4GiB", 0, true},
+		{"4KB", 0, true},
+		{"1,024", 0, true},
+		{"1_024", 0, true},
+		{"1e3", 0, true},
+		{".5G", 0, true},
+		{"", 0, true},
+	}
+
+	p, err := NewParser(WithProfile(Systemd))
+	require.NoError(t, err)
+
+	for _, test := range tests {
+		out, err := p.Parse(test.in)
+		if testing.Verbose() {
+			fmt.Printf("%q --> %v, %v\n", test.in, out, err)
+		}
+		if test.err {
+			require.Error(t, err, test.in)
+		} else {
+			require.NoError(t, err, test.in)
+			require.Equal(t, Size(test.out), out, test.in)
+		}
+	}
+
+	size, err := ParseSize("512", WithProfile(Systemd), WithDefaultUnit("K"))
+	require.NoError(t, err)
+	require.Equal(t, Size(512*Kibibyte), size)
+
+	_, err = ParseSize("2G", WithProfile(Systemd), WithMax(Size(Gibibyte)))
+	require.Error(t, err)
+}
+
+func TestJEDECProfile(t *testing.T) {
+	var tests = []struct {
+		in  string
+		out uint64
+		err bool
+	}{
+		{"1KB", Kibibyte, false},
+		{"512 MB", 512 * Mebibyte, false},
+		{"1.5GB", 3 * Gibibyte / 2, false},
+		{"2TB", 2 * Tebibyte, false},
+		{"16EB", 0, true},
+		{"100B", 100, false},
+		{"100", 100, false},
+		{"1kB", 0, true},
+		{"1K", 0, true},
+		{"1KiB", 0, true},
+		{"1kb", 0, true},
+	}
+
+	p, err := NewParser(WithProfile(JEDEC))
+	require.NoError(t, err)
+
+	for _, test := range tests {
+		out, err := p.Parse(test.in)
+		if testing.Verbose() {
+			fmt.Printf("%q --> %v, %v\n", test.in, out, err)
+		}
+		if test.err {
+			require.Error(t, err, test.in)
+		} else {
+			require.NoError(t, err, test.in)
+			require.Equal(t, Size(test.out), out, test.in)
+		}
+	}
+}
+
+func TestIEC80000Profile(t *testing.T) {
+	var tests = []struct {
+		in  string
+		out uint64
+		err bool
+	}{
+		{"1kB", Kilobyte, false},
+		{"512 MB", 512 * Megabyte, false},
+		{"1.5 GiB", 3 * Gibibyte / 2, false},
+		{"2EB", 2 * Exabyte, false},
+		{"100 B", 100, false},
+		{"100", 100, false},
+		{"1KB", 0, true},
+		{"1kb", 0, true},
+		{"1K", 0, true},
+		{"1Ki", 0, true},
+		{"1 kiB", 0, true},
+		{"1 kilobyte", 0, true},
+		{"1Mbit", 0, true},
+		{"1  MB", 0, true},
+		{" 1MB", 0, true},
+		{"1,000 MB", 0, true},
+	}
+
+	p, err := NewParser(WithProfile(IEC80000))
+	require.NoError(t, err)
+	require.Equal(t, "iec80000", IEC80000.String())
+
+	for _, test := range tests {
+		out, err := p.Parse(test.in)
+		if testing.Verbose() {
+			fmt.Printf("%q --> %v, %v\n", test.in, out, err)
+		}
+		if test.err {
+			require.Error(t, err, test.in)
+		} else {
+			require.NoError(t, err, test.in)
+			require.Equal(t, Size(test.out), out, test.in)
+		}
+	}
+
+	_, err = p.Parse("4 KB")
+	require.EqualError(t, err, `invalid units: did you mean "KiB"?`)
+}
+
+func TestNginxProfile(t *testing.T) {
+	var tests = []struct {
+		in  string
+		out uint64
+		err bool
+	}{
+		{"10m", 10 * Mebibyte, false},
+		{"10M", 10 * Mebibyte, false},
+		{"1g", Gibibyte, false},
+		{"8k", 8 * Kibibyte, false},
+		{"8K", 8 * Kibibyte, false},
+		{"1024", 1024, false},
+		{"17179869184g", 0, true},
+		{"1.5m", 0, true},
+		{"10 m", 0, true},
+		{"10mb", 0, true},
+		{"1t", 0, true},
+		{"1_024", 0, true},
+		{"1,024", 0, true},
+		{"1e3", 0, true},
+		{"m", 0, true},
+		{"", 0, true},
+	}
+
+	p, err := NewParser(WithProfile(Nginx))
+	require.NoError(t, err)
+
+	for _, test := range tests {
+		out, err := p.Parse(test.in)
+		if testing.Verbose() {
+			fmt.Printf("%q --> %v, %v\n", test.in, out, err)
+		}
+		if test.err {
+			require.Error(t, err, test.in)
+		} else {
+			require.NoError(t, err, test.in)
+			require.Equal(t, Size(test.out), out, test.in)
+		}
+	}
+}
+
+func TestFormatProfile(t *testing.T) {
+	var tests = []struct {
+		opts FormatOptions
+		in   uint64
+		out  string
+	}{
+		{FormatOptions{Profile: JEDEC}, 1536, "1.5KB"},
+		{FormatOptions{Profile: JEDEC}, 1000, "1000"},
+		{FormatOptions{Profile: JEDEC, Base: Base10}, 4 * Gibibyte, "4GB"},
+		{FormatOptions{Profile: JEDEC, Space: true}, 512 * Mebibyte, "512 MB"},
+		{FormatOptions{Profile: JEDEC, Precision: 2}, 1280000, "1.22MB"},
+		{FormatOptions{Profile: Network}, 1536, "1.5KiB"},
+		{FormatOptions{Profile: Network}, 1000, "1000"},
+		{FormatOptions{Profile: Kubernetes}, 3 * Gibibyte / 2, "1.5Gi"},
+		{FormatOptions{Profile: Docker}, 512 * Mebibyte, "512MiB"},
+		{FormatOptions{Profile: Systemd}, 512 * Mebibyte, "512M"},
+		{FormatOptions{Profile: Systemd}, Exbibyte, "1E"},
+		{FormatOptions{Profile: Nginx}, 10 * Mebibyte, "10m"},
+		{FormatOptions{Profile: Nginx}, 1536 * Kibibyte, "1536k"},
+		{FormatOptions{Profile: Nginx}, Tebibyte, "1024g"},
+		{FormatOptions{Profile: Nginx}, 1000, "1000"},
+		{FormatOptions{Profile: Nginx}, 0, "0"},
+		{FormatOptions{Profile: Nginx, Precision: 1}, 1536 * Kibibyte, "1.5m"},
+		{FormatOptions{Profile: IEC80000, Space: true}, 1536, "1.5 KiB"},
+		{FormatOptions{Profile: IEC80000, ByteSuffix: true}, 1000, "1000B"},
+	}
+
+	for _, test := range tests {
+		out := NewFormatter(test.opts).Format(Size(test.in))
+		if testing.Verbose() {
+			fmt.Printf("%v %v --> %v\n", test.opts.Profile, test.in, out)
+		}
+		require.Equal(t, test.out, out)
+
+		// Sizes formatted with a profile parse back with it.
+		if test.opts.Precision == 0 {
+			size, err := ParseSize(out, WithProfile(test.opts.Profile))
+			require.NoError(t, err, out)
+			require.Equal(t, Size(test.in), size, out)
+		}
+	}
+}
+
+func TestProfiles(t *testing.T) {
+	var tests = []struct {
+		name string
+		in   string
+		out  uint64
+		err  bool
+	}{
+		{"default", "4KB", 4 * Kibibyte, false},
+		{"default", "4kb", 4 * Kilobyte, false},
+		{"Default", "2 gigabytes", 2 * Gigabyte, false},
+		{"si-strict", "4kB", 4 * Kilobyte, false},
+		{"si-strict", "4KiB", 4 * Kibibyte, false},
+		{"si-strict", "4KB", 0, true},
+		{"docker", "4gb", 4 * Gibibyte, false},
+		{"k8s", "1.5Gi", 3 * Gibibyte / 2, false},
+		{"kubernetes", "1G", Gigabyte, false},
+		{"jedec", "1MB", Mebibyte, false},
+		{"systemd", "1G", Gibibyte, false},
+		{"coreutils", "4.0K", 4 * Kibibyte, false},
+	}
+
+	for _, test := range tests {
+		profile, err := Profiles.Get(test.name)
+		require.NoError(t, err, test.name)
+		p, err := NewParser(WithProfile(profile))
+		require.NoError(t, err, test.name)
+		out, err := p.Parse(test.in)
+		if testing.Verbose() {
+			fmt.Printf("%s %q --> %v, %v\n", test.name, test.in, out, err)
+		}
+		if test.err {
+			require.Error(t, err, test.in)
+		} else {
+			require.NoError(t, err, test.in)
+			require.Equal(t, test.out, uint64(out), test.in)
+		}
+	}
+
+	_, err := Profiles.Get("bogus")
+	require.EqualError(t, err, `unknown profile "bogus"`)
+
+	var r ProfileRegistry
+	require.NoError(t, r.Register("Containers", Docker))
+	profile, err := r.Get("containers")
+	require.NoError(t, err)
+	require.Same(t, Docker, profile)
+	require.Error(t, r.Register("containers", Kubernetes))
+	require.Error(t, r.Register("", Docker))
+	require.Error(t, r.Register("none", nil))
+	require.Equal(t, []string{"containers"}, r.Names())
+
+	require.Contains(t, Profiles.Names(), "k8s")
+	require.Equal(t, "1.5MiB", NewFormatter(FormatOptions{Profile: Default}).Format(Size(3*Mebibyte/2)))
+}
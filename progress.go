@@ -0,0 +1,149 @@
+//go:build !bytez_minimal
+
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package bytez
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Progress tracks the progress of a transfer, like a download or a copy, and reports the amount
+// transferred, the average rate, and the estimated time remaining when the total size is known.
+//
+// Progress is an io.Writer that counts the bytes written to it, so it can be combined with
+// io.TeeReader or io.MultiWriter to follow a transfer. It is safe for concurrent use, so one
+// goroutine can transfer data while another reports the progress.
+//
+// The zero value is ready to use, for a transfer of unknown size unless Total is set, and starts
+// when it is first used rather than when it is created, as with NewProgress.
+type Progress struct {
+	// Total is the size of the transfer, or zero if it is not known.
+	Total Size
+
+	once  sync.Once
+	start time.Time
+	done  atomic.Uint64
+	now   func() time.Time
+}
+
+// NewProgress returns a Progress for a transfer of total bytes, or of unknown size if total is
+// zero, starting now.
+func NewProgress(total Size) *Progress {
+	return newProgress(total, time.Now)
+}
+
+func newProgress(total Size, now func() time.Time) *Progress {
+	return &Progress{Total: total, start: now(), now: now}
+}
+
+// clock returns the current time.
+func (p *Progress) clock() time.Time {
+	if p.now == nil {
+		return time.Now()
+	}
+	return p.now()
+}
+
+// begin returns the time the transfer started, which is the first use of the Progress if it was
+// not created by NewProgress.
+func (p *Progress) begin() time.Time {
+	p.once.Do(func() {
+		if p.start.IsZero() {
+			p.start = p.clock()
+		}
+	})
+	return p.start
+}
+
+// Add records that n more bytes have been transferred.
+func (p *Progress) Add(n Size) {
+	p.begin()
+	p.done.Add(uint64(n))
+}
+
+// Write implements io.Writer, recording that len(b) bytes have been transferred. It never fails.
+func (p *Progress) Write(b []byte) (int, error) {
+	p.begin()
+	p.done.Add(uint64(len(b)))
+	return len(b), nil
+}
+
+// Done returns the number of bytes transferred so far.
+func (p *Progress) Done() Size {
+	return Size(p.done.Load())
+}
+
+// Elapsed returns the time since the transfer started.
+func (p *Progress) Elapsed() time.Duration {
+	start := p.begin()
+	return p.clock().Sub(start)
+}
+
+// Rate returns the average rate of the transfer since it started.
+func (p *Progress) Rate() Rate {
+	return RateOf(p.Done(), p.Elapsed())
+}
+
+// Fraction returns the fraction of the transfer that is done, between 0 and 1, or -1 if the
+// total size is not known.
+func (p *Progress) Fraction() float64 {
+	if p.Total == 0 {
+		return -1
+	}
+	done := p.Done()
+	if done >= p.Total {
+		return 1
+	}
+	return float64(done) / float64(p.Total)
+}
+
+// ETA returns the estimated time until the transfer is done at the average rate so far. It
+// returns false if the total size is not known or nothing has been transferred yet.
+func (p *Progress) ETA() (time.Duration, bool) {
+	done := p.Done()
+	if p.Total == 0 {
+		return 0, false
+	} else if done >= p.Total {
+		return 0, true
+	}
+	rate := p.Rate()
+	if rate == 0 {
+		return 0, false
+	}
+	return rate.TransferTime(p.Total - done), true
+}
+
+// progressFormatter formats the sizes of progress reports, which change constantly and are
+// rarely whole or half units.
+var progressFormatter = NewFormatter(FormatOptions{Base: Base2, Precision: 1})
+
+// String returns a one-line report of the progress, like "12.5MiB of 100MiB (12%) at 4.2MiB/s,
+// ETA 21s", or "12.5MiB at 4.2MiB/s" if the total size is not known.
+func (p *Progress) String() string {
+	var b strings.Builder
+	b.WriteString(progressFormatter.Format(p.Done()))
+	if p.Total != 0 {
+		b.WriteString(" of ")
+		b.WriteString(progressFormatter.Format(p.Total))
+		b.WriteString(" (")
+		b.WriteString(strconv.Itoa(int(p.Fraction() * 100)))
+		b.WriteString("%)")
+	}
+	b.WriteString(" at ")
+	b.WriteString(progressFormatter.Format(Size(p.Rate())))
+	b.WriteString("/s")
+	if eta, ok := p.ETA(); ok {
+		b.WriteString(", ETA ")
+		b.WriteString(eta.Round(time.Second).String())
+	}
+	return b.String()
+}
@@ -0,0 +1,88 @@
+//go:build !bytez_minimal
+
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package bytez
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProgress(t *testing.T) {
+	now := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	p := newProgress(Size(100*Mebibyte), func() time.Time { return now })
+
+	require.Zero(t, p.Done())
+	require.Zero(t, p.Rate())
+	require.Equal(t, 0.0, p.Fraction())
+	_, ok := p.ETA()
+	require.False(t, ok)
+	require.Equal(t, "0 of 100MiB (0%) at 0/s", p.String())
+
+	n, err := io.Copy(p, strings.NewReader(strings.Repeat("x", 10*int(Mebibyte))))
+	require.NoError(t, err)
+	require.Equal(t, int64(10*Mebibyte), n)
+	p.Add(Size(15 * Mebibyte / 10))
+	now = now.Add(5 * time.Second)
+
+	require.Equal(t, Size(23*Mebibyte/2), p.Done())
+	require.Equal(t, 5*time.Second, p.Elapsed())
+	require.Equal(t, Rate(2411724), p.Rate())
+	require.Equal(t, 0.115, p.Fraction())
+	eta, ok := p.ETA()
+	require.True(t, ok)
+	require.Equal(t, 38*time.Second, eta.Round(time.Second))
+	if testing.Verbose() {
+		fmt.Println(p)
+	}
+	require.Equal(t, "11.5MiB of 100MiB (11%) at 2.3MiB/s, ETA 38s", p.String())
+
+	p.Add(Size(100 * Mebibyte))
+	require.Equal(t, 1.0, p.Fraction())
+	eta, ok = p.ETA()
+	require.True(t, ok)
+	require.Zero(t, eta)
+
+	p = newProgress(0, func() time.Time { return now })
+	p.Add(1536)
+	now = now.Add(time.Second)
+	require.Equal(t, -1.0, p.Fraction())
+	_, ok = p.ETA()
+	require.False(t, ok)
+	require.Equal(t, "1.5KiB at 1.5KiB/s", p.String())
+}
+
+func TestProgressZero(t *testing.T) {
+	now := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	p := &Progress{Total: Size(100 * Mebibyte), now: func() time.Time { return now }}
+
+	// The transfer starts when the Progress is first used.
+	now = now.Add(time.Hour)
+	p.Add(Size(10 * Mebibyte))
+	now = now.Add(5 * time.Second)
+	require.Equal(t, 5*time.Second, p.Elapsed())
+	require.Equal(t, Rate(2*Mebibyte), p.Rate())
+	require.Equal(t, "10MiB of 100MiB (10%) at 2MiB/s, ETA 45s", p.String())
+
+	// Without a clock, it uses the time of day.
+	var zero Progress
+	require.Zero(t, zero.Done())
+	require.Equal(t, -1.0, zero.Fraction())
+	_, ok := zero.ETA()
+	require.False(t, ok)
+	n, err := zero.Write(make([]byte, 1536))
+	require.NoError(t, err)
+	require.Equal(t, 1536, n)
+	require.True(t, zero.Elapsed() >= 0 && zero.Elapsed() < time.Minute)
+	require.True(t, strings.HasPrefix(zero.String(), "1.5KiB at "), zero.String())
+}
@@ -0,0 +1,144 @@
+//go:build !bytez_minimal
+
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package bytez
+
+import (
+	"errors"
+	"math"
+	"math/bits"
+	"time"
+)
+
+// BitSize is a number of bits, as used for network bandwidth and memory chip capacities. It is
+// a distinct type from Size, which counts bytes, so the two cannot be mixed accidentally; use
+// Size.Bits and BitSize.Bytes to convert between them.
+type BitSize uint64
+
+// Rate is a data transfer rate in bytes per second. Use RateOf, Rate.Transfer, and
+// Rate.TransferTime to relate it to sizes and durations.
+type Rate uint64
+
+// BitRate is a data transfer rate in bits per second, as used for network links and interfaces.
+// Use Rate.Bits and BitRate.Bytes to convert between it and Rate.
+type BitRate uint64
+
+// Quantity is the constraint satisfied by the dimensions of this package: Size, BitSize, Rate,
+// and BitRate. Generic functions over it operate on values of a single dimension, so that, for
+// example, sizes cannot be added to rates, or bits to bytes, without an explicit conversion.
+// Plain integers do not satisfy it either, so a count of bits cannot be passed as a count of
+// bytes by mistake.
+type Quantity interface {
+	Size | BitSize | Rate | BitRate
+}
+
+var errQuantityOverflow = errors.New("quantity overflows 64 bits")
+
+// Sum returns the sum of quantities of the same dimension, or an error if it overflows.
+func Sum[Q Quantity](quantities ...Q) (Q, error) {
+	var sum uint64
+	for _, q := range quantities {
+		var carry uint64
+		if sum, carry = bits.Add64(sum, uint64(q), 0); carry != 0 {
+			return 0, errQuantityOverflow
+		}
+	}
+	return Q(sum), nil
+}
+
+// Scale returns q multiplied by factor, rounded to the nearest whole unit and saturating at zero
+// and at the largest value of its type.
+func Scale[Q Quantity](q Q, factor float64) Q {
+	val := math.Round(float64(q) * factor)
+	if val <= 0 || math.IsNaN(val) {
+		return 0
+	} else if val >= math.MaxUint64 {
+		return Q(uint64(math.MaxUint64))
+	}
+	return Q(val)
+}
+
+// Ratio returns a divided by b. Since both are of the same dimension, the result is a plain
+// number. It is +Inf, or NaN if a is also zero, if b is zero.
+func Ratio[Q Quantity](a, b Q) float64 {
+	return float64(a) / float64(b)
+}
+
+// Bits returns the size in bits, saturating at the largest BitSize for sizes of 2EiB or more.
+func (sz Size) Bits() BitSize {
+	if sz > math.MaxUint64/8 {
+		return math.MaxUint64
+	}
+	return BitSize(sz * 8)
+}
+
+// Bytes returns the number of bytes needed to hold b bits, rounding up to whole bytes.
+func (b BitSize) Bytes() Size {
+	return Size(b/8 + (b%8+7)/8)
+}
+
+// Bits returns the rate in bits per second, saturating at the largest BitRate.
+func (r Rate) Bits() BitRate {
+	return BitRate(Size(r).Bits())
+}
+
+// Bytes returns the rate in bytes per second, rounding up to whole bytes.
+func (b BitRate) Bytes() Rate {
+	return Rate(BitSize(b).Bytes())
+}
+
+// RateOf returns the rate at which size bytes are transferred in d, rounded down to whole bytes
+// per second. It returns 0 if d is not positive.
+func RateOf(size Size, d time.Duration) Rate {
+	if d <= 0 {
+		return 0
+	}
+	hi, lo := bits.Mul64(uint64(size), uint64(time.Second))
+	if hi >= uint64(d) {
+		return math.MaxUint64
+	}
+	rate, _ := bits.Div64(hi, lo, uint64(d))
+	return Rate(rate)
+}
+
+// Transfer returns the number of bytes transferred at rate r in d, saturating at the largest
+// Size. It returns 0 if d is not positive.
+func (r Rate) Transfer(d time.Duration) Size {
+	if d <= 0 {
+		return 0
+	}
+	hi, lo := bits.Mul64(uint64(r), uint64(d))
+	if hi >= uint64(time.Second) {
+		return math.MaxUint64
+	}
+	size, _ := bits.Div64(hi, lo, uint64(time.Second))
+	return Size(size)
+}
+
+// TransferTime returns how long it takes to transfer size bytes at rate r, rounded up to the
+// nanosecond and saturating at the longest Duration. It returns the longest Duration if r is
+// zero and size is not.
+func (r Rate) TransferTime(size Size) time.Duration {
+	if size == 0 {
+		return 0
+	} else if r == 0 {
+		return math.MaxInt64
+	}
+	hi, lo := bits.Mul64(uint64(size), uint64(time.Second))
+	if hi >= uint64(r) {
+		return math.MaxInt64
+	}
+	ns, rem := bits.Div64(hi, lo, uint64(r))
+	if rem != 0 {
+		ns++
+	}
+	if ns > math.MaxInt64 {
+		return math.MaxInt64
+	}
+	return time.Duration(ns)
+}
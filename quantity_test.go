@@ -0,0 +1,78 @@
+//go:build !bytez_minimal
+
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package bytez
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuantity(t *testing.T) {
+	sum, err := Sum(Size(Mebibyte), Size(Kibibyte))
+	require.NoError(t, err)
+	require.Equal(t, Size(Mebibyte+Kibibyte), sum)
+
+	rate, err := Sum[Rate]()
+	require.NoError(t, err)
+	require.Zero(t, rate)
+
+	_, err = Sum(BitSize(math.MaxUint64), BitSize(1))
+	require.Error(t, err)
+
+	bitRate, err := Sum(BitRate(100*Megabyte), BitRate(Gigabyte))
+	require.NoError(t, err)
+	require.Equal(t, BitRate(1100*Megabyte), bitRate)
+
+	require.Equal(t, Size(1536), Scale(Size(Kibibyte), 1.5))
+	require.Equal(t, Rate(0), Scale(Rate(100), -2))
+	require.Equal(t, Size(math.MaxUint64), Scale(Size(Exbibyte), 100))
+	require.Equal(t, 0.25, Ratio(Size(Kibibyte), Size(4*Kibibyte)))
+	require.True(t, math.IsInf(Ratio(Rate(1), Rate(0)), 1))
+}
+
+func TestConversions(t *testing.T) {
+	tests := []struct {
+		size Size
+		bits BitSize
+	}{
+		{0, 0},
+		{1, 8},
+		{Size(Kibibyte), 8192},
+		{math.MaxUint64 / 8, math.MaxUint64 / 8 * 8},
+		{math.MaxUint64/8 + 1, math.MaxUint64},
+	}
+	for _, test := range tests {
+		require.Equal(t, test.bits, test.size.Bits(), test.size)
+	}
+
+	require.Equal(t, Size(0), BitSize(0).Bytes())
+	require.Equal(t, Size(1), BitSize(1).Bytes())
+	require.Equal(t, Size(1), BitSize(8).Bytes())
+	require.Equal(t, Size(2), BitSize(9).Bytes())
+	require.Equal(t, Size(math.MaxUint64/8+1), BitSize(math.MaxUint64).Bytes())
+
+	require.Equal(t, Rate(Mebibyte), RateOf(Size(10*Mebibyte), 10*time.Second))
+	require.Equal(t, Rate(2000), RateOf(Size(Kilobyte), 500*time.Millisecond))
+	require.Equal(t, Rate(0), RateOf(Size(Kilobyte), 0))
+	require.Equal(t, Rate(math.MaxUint64), RateOf(Size(math.MaxUint64), time.Nanosecond))
+
+	require.Equal(t, Size(5*Megabyte), Rate(Megabyte).Transfer(5*time.Second))
+	require.Equal(t, Size(500), Rate(Kilobyte).Transfer(500*time.Millisecond))
+	require.Equal(t, Size(0), Rate(Kilobyte).Transfer(-time.Second))
+	require.Equal(t, Size(math.MaxUint64), Rate(math.MaxUint64).Transfer(time.Minute))
+
+	require.Equal(t, 4*time.Second, Rate(Mebibyte).TransferTime(Size(4*Mebibyte)))
+	require.Equal(t, time.Duration(333333334), Rate(3).TransferTime(1))
+	require.Equal(t, time.Duration(0), Rate(0).TransferTime(0))
+	require.Equal(t, time.Duration(math.MaxInt64), Rate(0).TransferTime(1))
+	require.Equal(t, time.Duration(math.MaxInt64), Rate(1).TransferTime(Size(Exabyte)))
+}
@@ -0,0 +1,122 @@
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+// Package randsize generates random sizes for load testing and simulation, so tools can produce
+// realistic object-size workloads, like "mostly small files with a long tail of large ones", in
+// terms of bytez sizes.
+//
+// Generators draw from a *rand.Rand of package math/rand/v2, so a workload can be reproduced by
+// seeding it the same way, like rand.New(rand.NewPCG(1, 2)). Like the *rand.Rand they use,
+// generators are not safe for concurrent use.
+package randsize
+
+import (
+	"errors"
+	"math"
+	"math/rand/v2"
+
+	"github.com/nexvium/bytez"
+)
+
+// Generator is implemented by the generators of random sizes in this package.
+type Generator interface {
+	// Next returns the next random size.
+	Next() bytez.Size
+}
+
+// Uniform generates sizes uniformly distributed in a range.
+type Uniform struct {
+	r        *rand.Rand
+	min, max bytez.Size
+}
+
+// NewUniform returns a generator of sizes uniformly distributed between min and max, inclusive.
+func NewUniform(r *rand.Rand, min, max bytez.Size) (*Uniform, error) {
+	if err := checkRange(r, min, max); err != nil {
+		return nil, err
+	}
+	return &Uniform{r: r, min: min, max: max}, nil
+}
+
+// Next returns the next random size.
+func (u *Uniform) Next() bytez.Size {
+	n := uint64(u.max - u.min)
+	if n == math.MaxUint64 {
+		return bytez.Size(u.r.Uint64())
+	}
+	return u.min + bytez.Size(u.r.Uint64N(n+1))
+}
+
+// Zipf generates sizes following a Zipf distribution, in which small sizes are much more frequent
+// than large ones, as is typical of files and cached objects.
+type Zipf struct {
+	z   *rand.Zipf
+	min bytez.Size
+}
+
+// NewZipf returns a generator of sizes between min and max, inclusive, in which the probability of
+// min+k is proportional to (k+1)^-s. The exponent s must be greater than 1; the larger it is, the
+// more the sizes concentrate near min.
+func NewZipf(r *rand.Rand, min, max bytez.Size, s float64) (*Zipf, error) {
+	if err := checkRange(r, min, max); err != nil {
+		return nil, err
+	} else if !(s > 1) || math.IsInf(s, 1) {
+		return nil, errors.New("zipf exponent must be greater than 1")
+	}
+	return &Zipf{z: rand.NewZipf(r, s, 1, uint64(max-min)), min: min}, nil
+}
+
+// Next returns the next random size.
+func (z *Zipf) Next() bytez.Size {
+	return z.min + bytez.Size(z.z.Uint64())
+}
+
+// LogNormal generates sizes following a log-normal distribution, which models the sizes of many
+// real-world objects, like web pages, images, and messages.
+type LogNormal struct {
+	r        *rand.Rand
+	mu       float64
+	sigma    float64
+	min, max bytez.Size
+}
+
+// NewLogNormal returns a generator of sizes whose logarithm is normally distributed, with the
+// given median and standard deviation sigma of the logarithm. Half of the sizes are below the
+// median, and about two thirds are within a factor of e^sigma of it. Sizes outside the range from
+// min to max, inclusive, are clamped to it, so the range should be wide enough for clamping to be
+// rare.
+func NewLogNormal(r *rand.Rand, min, max, median bytez.Size, sigma float64) (*LogNormal, error) {
+	if err := checkRange(r, min, max); err != nil {
+		return nil, err
+	} else if median == 0 {
+		return nil, errors.New("median must be greater than zero")
+	} else if !(sigma >= 0) || math.IsInf(sigma, 1) {
+		return nil, errors.New("sigma must be a non-negative number")
+	}
+	return &LogNormal{r: r, mu: math.Log(float64(median)), sigma: sigma, min: min, max: max}, nil
+}
+
+// Next returns the next random size.
+func (l *LogNormal) Next() bytez.Size {
+	// Compare as floats first since the value may not fit in a Size, and again after converting
+	// since bounds near the top of the range are not exact as floats.
+	val := math.Exp(l.mu + l.sigma*l.r.NormFloat64())
+	if val <= float64(l.min) {
+		return l.min
+	} else if val >= float64(l.max) {
+		return l.max
+	}
+	return min(max(bytez.Size(math.Round(val)), l.min), l.max)
+}
+
+func checkRange(r *rand.Rand, min, max bytez.Size) error {
+	if r == nil {
+		return errors.New("nil random number generator")
+	} else if min > max {
+		return errors.New("minimum size is greater than maximum")
+	}
+	return nil
+}
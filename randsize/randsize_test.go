@@ -0,0 +1,129 @@
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package randsize
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"sort"
+	"testing"
+
+	"github.com/nexvium/bytez"
+	"github.com/stretchr/testify/require"
+)
+
+const samples = 10000
+
+func newRand() *rand.Rand {
+	return rand.New(rand.NewPCG(1, 2))
+}
+
+// draw returns n sizes from g, sorted.
+func draw(g Generator, n int) []bytez.Size {
+	sizes := make([]bytez.Size, n)
+	for i := range sizes {
+		sizes[i] = g.Next()
+	}
+	sort.Slice(sizes, func(i, j int) bool { return sizes[i] < sizes[j] })
+	return sizes
+}
+
+func TestUniform(t *testing.T) {
+	g, err := NewUniform(newRand(), 1*bytez.Size(bytez.Kibibyte), 4*bytez.Size(bytez.Kibibyte))
+	require.NoError(t, err)
+
+	sizes := draw(g, samples)
+	median := sizes[samples/2]
+	if testing.Verbose() {
+		fmt.Printf("uniform: min %v, median %v, max %v\n", sizes[0], median, sizes[samples-1])
+	}
+	require.GreaterOrEqual(t, uint64(sizes[0]), bytez.Kibibyte)
+	require.LessOrEqual(t, uint64(sizes[samples-1]), 4*bytez.Kibibyte)
+	require.InDelta(t, 2560, float64(median), 100)
+
+	// The whole range, and a range of a single size, are allowed.
+	g, err = NewUniform(newRand(), 0, math.MaxUint64)
+	require.NoError(t, err)
+	require.NotEqual(t, g.Next(), g.Next())
+
+	g, err = NewUniform(newRand(), 42, 42)
+	require.NoError(t, err)
+	require.Equal(t, bytez.Size(42), g.Next())
+
+	// The same seed produces the same sizes.
+	g1, _ := NewUniform(newRand(), 0, 1<<40)
+	g2, _ := NewUniform(newRand(), 0, 1<<40)
+	require.Equal(t, draw(g1, 100), draw(g2, 100))
+}
+
+func TestZipf(t *testing.T) {
+	g, err := NewZipf(newRand(), 512, bytez.Size(bytez.Gibibyte), 1.5)
+	require.NoError(t, err)
+
+	sizes := draw(g, samples)
+	median := sizes[samples/2]
+	if testing.Verbose() {
+		fmt.Printf("zipf: min %v, median %v, max %v\n", sizes[0], median, sizes[samples-1])
+	}
+	require.Equal(t, bytez.Size(512), sizes[0])
+	require.LessOrEqual(t, uint64(sizes[samples-1]), bytez.Gibibyte)
+	require.Less(t, uint64(median), 2*bytez.Kibibyte)
+	require.Greater(t, uint64(sizes[samples-1]), bytez.Mebibyte)
+
+	for _, s := range []float64{1, 0.5, -1, math.NaN(), math.Inf(1)} {
+		_, err := NewZipf(newRand(), 0, 100, s)
+		require.Error(t, err, "%v", s)
+	}
+}
+
+func TestLogNormal(t *testing.T) {
+	median := bytez.Size(64 * bytez.Kibibyte)
+	g, err := NewLogNormal(newRand(), 0, math.MaxUint64, median, 1)
+	require.NoError(t, err)
+
+	sizes := draw(g, samples)
+	if testing.Verbose() {
+		fmt.Printf("lognormal: min %v, median %v, max %v\n", sizes[0], sizes[samples/2], sizes[samples-1])
+	}
+	require.InEpsilon(t, float64(median), float64(sizes[samples/2]), 0.05)
+
+	// About 68% of the sizes are within a factor of e of the median.
+	lo := sort.Search(samples, func(i int) bool { return float64(sizes[i]) >= float64(median)/math.E })
+	hi := sort.Search(samples, func(i int) bool { return float64(sizes[i]) > float64(median)*math.E })
+	require.InDelta(t, 0.68, float64(hi-lo)/samples, 0.02)
+
+	// Sizes are clamped to the range.
+	g, err = NewLogNormal(newRand(), 32*bytez.Size(bytez.Kibibyte), 128*bytez.Size(bytez.Kibibyte), median, 2)
+	require.NoError(t, err)
+	sizes = draw(g, samples)
+	require.Equal(t, 32*bytez.Size(bytez.Kibibyte), sizes[0])
+	require.Equal(t, 128*bytez.Size(bytez.Kibibyte), sizes[samples-1])
+
+	// With sigma 0 all sizes are the median.
+	g, err = NewLogNormal(newRand(), 0, math.MaxUint64, median, 0)
+	require.NoError(t, err)
+	require.Equal(t, median, g.Next())
+
+	_, err = NewLogNormal(newRand(), 0, 100, 0, 1)
+	require.Error(t, err)
+	_, err = NewLogNormal(newRand(), 0, 100, 10, -1)
+	require.Error(t, err)
+	_, err = NewLogNormal(newRand(), 0, 100, 10, math.NaN())
+	require.Error(t, err)
+}
+
+func TestInvalidRange(t *testing.T) {
+	_, err := NewUniform(nil, 0, 100)
+	require.Error(t, err)
+	_, err = NewUniform(newRand(), 100, 10)
+	require.Error(t, err)
+	_, err = NewZipf(newRand(), 100, 10, 2)
+	require.Error(t, err)
+	_, err = NewLogNormal(newRand(), 100, 10, 50, 1)
+	require.Error(t, err)
+}
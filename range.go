@@ -0,0 +1,101 @@
+//go:build !bytez_minimal
+
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package bytez
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SizeRange is an inclusive range of sizes, like 1GiB to 4GiB, for validating configurable
+// limits that must fall within a band. It is marshaled to and from text like "1GiB..4GiB".
+type SizeRange struct {
+	Min, Max Size
+}
+
+// ParseRange parses a range of sizes written as two sizes separated by ".." or "-", like
+// "512MiB..2GiB" or "1GiB-4GiB", optionally surrounded by spaces. Both sizes have the syntax
+// accepted by AsInt, and the first must not be greater than the second.
+func ParseRange(str string) (SizeRange, error) {
+	var minStr, maxStr string
+	if idx := strings.Index(str, ".."); idx >= 0 {
+		minStr, maxStr = str[:idx], str[idx+2:]
+	} else {
+		// Only negative exponents contain "-", and ranges using them must be written with "..".
+		var ok bool
+		if minStr, maxStr, ok = strings.Cut(str, "-"); !ok {
+			return SizeRange{}, fmt.Errorf("invalid range %q: missing \"..\" or \"-\"", str)
+		}
+	}
+
+	min, err := AsInt(minStr)
+	if err != nil {
+		return SizeRange{}, fmt.Errorf("invalid range %q: %v", str, err)
+	}
+	max, err := AsInt(maxStr)
+	if err != nil {
+		return SizeRange{}, fmt.Errorf("invalid range %q: %v", str, err)
+	} else if min > max {
+		return SizeRange{}, fmt.Errorf("invalid range %q: minimum is greater than maximum", str)
+	}
+	return SizeRange{Min: Size(min), Max: Size(max)}, nil
+}
+
+// Contains reports whether size is within the range.
+func (r SizeRange) Contains(size Size) bool {
+	return size >= r.Min && size <= r.Max
+}
+
+// Clamp returns size limited to the range: Min if it is smaller, Max if it is larger, and size
+// itself otherwise.
+func (r SizeRange) Clamp(size Size) Size {
+	if size < r.Min {
+		return r.Min
+	} else if size > r.Max {
+		return r.Max
+	}
+	return size
+}
+
+// Intersect returns the sizes that are in both r and other, and false if there are none.
+func (r SizeRange) Intersect(other SizeRange) (SizeRange, bool) {
+	both := SizeRange{Min: r.Min, Max: r.Max}
+	if other.Min > both.Min {
+		both.Min = other.Min
+	}
+	if other.Max < both.Max {
+		both.Max = other.Max
+	}
+	if both.Min > both.Max {
+		return SizeRange{}, false
+	}
+	return both, true
+}
+
+// String returns the range formatted like "1GiB..4GiB", using AsStr for the sizes.
+func (r SizeRange) String() string {
+	return r.Min.AsStr() + ".." + r.Max.AsStr()
+}
+
+// MarshalText implements the encoding.TextMarshaler interface, formatting the sizes like AsStr
+// does by default, whatever the default format is. Returned error is always nil.
+func (r SizeRange) MarshalText() ([]byte, error) {
+	dst := appendStr(nil, uint64(r.Min))
+	return appendStr(append(dst, ".."...), uint64(r.Max)), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface using ParseRange.
+func (r *SizeRange) UnmarshalText(text []byte) error {
+	val, err := ParseRange(string(text))
+	if err != nil {
+		return err
+	}
+	*r = val
+	return nil
+}
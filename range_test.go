@@ -0,0 +1,86 @@
+//go:build !bytez_minimal
+
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package bytez
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRange(t *testing.T) {
+	var positive = []struct {
+		in       string
+		min, max uint64
+	}{
+		{"1GiB-4GiB", Gibibyte, 4 * Gibibyte},
+		{"512MiB..2GiB", 512 * Mebibyte, 2 * Gibibyte},
+		{" 1.5 GiB - 4 GiB ", 3 * Gibibyte / 2, 4 * Gibibyte},
+		{"0..0", 0, 0},
+		{"4096-4096", 4096, 4096},
+	}
+
+	for _, test := range positive {
+		r, err := ParseRange(test.in)
+		if testing.Verbose() {
+			fmt.Printf("%q --> %v\n", test.in, r)
+		}
+		require.NoError(t, err, test.in)
+		require.Equal(t, SizeRange{Size(test.min), Size(test.max)}, r, test.in)
+	}
+
+	for _, in := range []string{"", "1GiB", "1GiB-", "-4GiB", "1GiB..", "4GiB-1GiB", "1GiB...4GiB",
+		"1GiB to 4GiB", "1GiB-4GiBs"} {
+		_, err := ParseRange(in)
+		if testing.Verbose() {
+			fmt.Printf("%q ==> %v\n", in, err)
+		}
+		require.Error(t, err, in)
+	}
+}
+
+func TestSizeRange(t *testing.T) {
+	r := SizeRange{Min: Size(Gibibyte), Max: Size(4 * Gibibyte)}
+
+	require.True(t, r.Contains(Size(Gibibyte)))
+	require.True(t, r.Contains(Size(4*Gibibyte)))
+	require.False(t, r.Contains(Size(Gibibyte-1)))
+	require.False(t, r.Contains(Size(4*Gibibyte+1)))
+
+	require.Equal(t, Size(Gibibyte), r.Clamp(0))
+	require.Equal(t, Size(2*Gibibyte), r.Clamp(Size(2*Gibibyte)))
+	require.Equal(t, Size(4*Gibibyte), r.Clamp(Size(8*Gibibyte)))
+
+	both, ok := r.Intersect(SizeRange{Min: Size(2 * Gibibyte), Max: Size(8 * Gibibyte)})
+	require.True(t, ok)
+	require.Equal(t, SizeRange{Min: Size(2 * Gibibyte), Max: Size(4 * Gibibyte)}, both)
+
+	_, ok = r.Intersect(SizeRange{Min: Size(5 * Gibibyte), Max: Size(8 * Gibibyte)})
+	require.False(t, ok)
+
+	require.Equal(t, "1GiB..4GiB", r.String())
+}
+
+func TestSizeRangeJSON(t *testing.T) {
+	type Conf struct {
+		Heap SizeRange `json:"heap"`
+	}
+
+	bytes, err := json.Marshal(Conf{Heap: SizeRange{Min: Size(512 * Mebibyte), Max: Size(2 * Gibibyte)}})
+	require.NoError(t, err)
+	require.Equal(t, `{"heap":"512MiB..2GiB"}`, string(bytes))
+
+	var conf Conf
+	require.NoError(t, json.Unmarshal([]byte(`{"heap":"1GiB-4GiB"}`), &conf))
+	require.Equal(t, SizeRange{Min: Size(Gibibyte), Max: Size(4 * Gibibyte)}, conf.Heap)
+
+	require.Error(t, json.Unmarshal([]byte(`{"heap":"4GiB-1GiB"}`), &conf))
+}
@@ -0,0 +1,153 @@
+//go:build !bytez_minimal
+
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package bytez
+
+import (
+	"fmt"
+	"strings"
+)
+
+// rateUnits are the time units of rates, with their number of seconds.
+var rateUnits = []struct {
+	suffix  string
+	seconds uint64
+}{
+	{"/s", 1}, {"/sec", 1}, {"/min", 60}, {"/h", 3600},
+}
+
+// ParseRate parses a rate written as a size per unit of time, like "10MiB/s", "1.5gb/min", or
+// "100 GiB/h", as used for throttle settings. The size has the syntax accepted by AsInt, and
+// errors in it are of type *ParseError. Rates are rounded to the nearest whole number of bytes
+// per second, with halves rounded to even.
+//
+// Bit rates, like "100Mbps", are also accepted, as parsed by ParseBitRate and converted to bytes
+// by BitRate.Bytes.
+func ParseRate(str string) (Rate, error) {
+	trimmed := strings.TrimRight(str, " \t\r\n")
+	if strings.HasSuffix(trimmed, "bps") {
+		rate, err := ParseBitRate(str)
+		return rate.Bytes(), err
+	}
+	for _, u := range rateUnits {
+		if !strings.HasSuffix(trimmed, u.suffix) {
+			continue
+		}
+		size, err := AsInt(trimmed[:len(trimmed)-len(u.suffix)])
+		if err != nil {
+			err.(*ParseError).Input = str
+			return 0, err
+		}
+
+		rate, rem := size/u.seconds, size%u.seconds
+		if 2*rem > u.seconds || (2*rem == u.seconds && rate%2 == 1) {
+			rate++
+		}
+		return Rate(rate), nil
+	}
+	return 0, fmt.Errorf("invalid rate %q: missing \"/s\", \"/min\", or \"/h\"", str)
+}
+
+// ParseBitRate parses a bit rate, like "100Mbps", "2.5 Gbps", or "64kbit/s", as used for network
+// links and interfaces. The number has the syntax accepted by AsInt, and the optional prefix is
+// decimal in either case, like "k" or "K" for 1000 bits, or binary, like "Ki" for 1024 bits.
+// Rates are rounded to the nearest whole number of bits per second, with halves rounded to even.
+// Errors are of type *ParseError.
+func ParseBitRate(str string) (BitRate, error) {
+	trimmed := strings.TrimLeft(str, " \t\r\n")
+	lead := len(str) - len(trimmed)
+	trimmed = strings.TrimRight(trimmed, " \t\r\n")
+	num, idx, err := scanNumber(trimmed, ',', '.')
+	if err != nil {
+		return 0, &ParseError{Input: str, Offset: lead + idx, Err: err}
+	}
+
+	if idx < len(trimmed) && trimmed[idx] == ' ' {
+		idx++
+	}
+	prefix, ok := strings.CutSuffix(trimmed[idx:], "bps")
+	if !ok {
+		prefix, ok = strings.CutSuffix(trimmed[idx:], "bit/s")
+	}
+	unit, known := bitRatePrefix(prefix)
+	if !ok || !known {
+		return 0, &ParseError{Input: str, Offset: lead + idx, Err: ErrInvalidUnits}
+	}
+
+	val, err := num.bytes(unit)
+	if err != nil {
+		return 0, &ParseError{Input: str, Offset: lead, Err: err}
+	}
+	return BitRate(val), nil
+}
+
+// bitRatePrefix returns the number of bits per second in a unit of bit rates with the given
+// prefix.
+func bitRatePrefix(prefix string) (uint64, bool) {
+	if prefix == "" {
+		return 1, true
+	}
+	for i := 0; i < len(bitPrefixes); i++ {
+		if strings.EqualFold(prefix, bitPrefixes[i:i+1]) {
+			return valuesBase10[i+1], true
+		} else if strings.EqualFold(prefix, bitPrefixes[i:i+1]+"i") {
+			return valuesBase2[i+1], true
+		}
+	}
+	return 0, false
+}
+
+// AsStr returns the rate formatted like "10MiB/s", with the size per second formatted by AsStr.
+func (r Rate) AsStr() string {
+	return AsStr(uint64(r)) + "/s"
+}
+
+// MarshalText implements the encoding.TextMarshaler interface, formatting the rate like AsStr
+// does by default, whatever the default format is. Returned error is always nil.
+func (r Rate) MarshalText() ([]byte, error) {
+	return append(appendStr(nil, uint64(r)), "/s"...), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface using ParseRate.
+func (r *Rate) UnmarshalText(text []byte) error {
+	val, err := ParseRate(string(text))
+	if err != nil {
+		return err
+	}
+
+	*r = val
+	return nil
+}
+
+// AsStr returns the bit rate formatted like "100Mbps" or "2.5Gbps", using the largest decimal
+// prefix in which it is a whole or half number.
+func (b BitRate) AsStr() string {
+	var buf [32]byte
+	dst := appendExact(buf[:0], uint64(b), 10, "", bitRateNames)
+	return string(append(dst, "bps"...))
+}
+
+// bitRateNames are the prefixes used to format bit rates.
+var bitRateNames = []string{"", "k", "M", "G", "T", "P", "E"}
+
+// MarshalText implements the encoding.TextMarshaler interface, formatting the bit rate like
+// AsStr. Returned error is always nil.
+func (b BitRate) MarshalText() ([]byte, error) {
+	return []byte(b.AsStr()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface using ParseBitRate.
+func (b *BitRate) UnmarshalText(text []byte) error {
+	val, err := ParseBitRate(string(text))
+	if err != nil {
+		return err
+	}
+
+	*b = val
+	return nil
+}
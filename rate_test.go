@@ -0,0 +1,158 @@
+//go:build !bytez_minimal
+
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package bytez
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRate(t *testing.T) {
+	var positive = []struct {
+		in  string
+		out uint64
+	}{
+		{"10MiB/s", 10 * Mebibyte},
+		{"1.5gb/s", 3 * Gigabyte / 2},
+		{"512 KiB/sec", 512 * Kibibyte},
+		{"1.5gb/min", 25 * Megabyte},
+		{"100 GiB/h ", 29826162},
+		{"90/min", 2},
+		{"30/min", 0},
+		{"0/s", 0},
+		{"4096/s", 4096},
+		{"100Mbps", 12500000},
+		{"2.5 Gbps", 312500000},
+		{"100Mbit/s", 12500000},
+		{"12bps", 2},
+	}
+
+	for _, test := range positive {
+		out, err := ParseRate(test.in)
+		if testing.Verbose() {
+			fmt.Printf("%q --> %v\n", test.in, out)
+		}
+		require.NoError(t, err, test.in)
+		require.Equal(t, Rate(test.out), out, test.in)
+	}
+
+	for _, in := range []string{"", "10MiB", "10MiB/", "10MiB/d", "10MiB/S", "/s",
+		"10MiBs/s", "10MiB/s/s"} {
+		_, err := ParseRate(in)
+		if testing.Verbose() {
+			fmt.Printf("%q ==> %v\n", in, err)
+		}
+		require.Error(t, err, in)
+	}
+
+	_, err := ParseRate("10 MiBs/s")
+	var perr *ParseError
+	require.True(t, errors.As(err, &perr))
+	require.Equal(t, "10 MiBs/s", perr.Input)
+	require.Equal(t, 3, perr.Offset)
+	require.Equal(t, "MiB", perr.Suggestion)
+}
+
+func TestParseBitRate(t *testing.T) {
+	var positive = []struct {
+		in  string
+		out uint64
+	}{
+		{"100Mbps", 100 * Megabyte},
+		{"2.5Gbps", 5 * Gigabyte / 2},
+		{"2.5 Gbps", 5 * Gigabyte / 2},
+		{"56kbps", 56000},
+		{"56Kbps", 56000},
+		{"10 gbps", 10 * Gigabyte},
+		{"64kbit/s", 64000},
+		{"1Kibps", 1024},
+		{"1,200bps", 1200},
+		{"1.5bps", 2},
+		{" 1Ebps ", Exabyte},
+	}
+
+	for _, test := range positive {
+		out, err := ParseBitRate(test.in)
+		if testing.Verbose() {
+			fmt.Printf("%q --> %v\n", test.in, out)
+		}
+		require.NoError(t, err, test.in)
+		require.Equal(t, BitRate(test.out), out, test.in)
+	}
+
+	var negative = []struct {
+		in     string
+		offset int
+	}{
+		{"", 0},
+		{"Mbps", 0},
+		{"100", 3},
+		{"100Mb", 3},
+		{"100MBps", 3},
+		{"100 Mibit", 4},
+		{"100xbps", 3},
+		{"100  Mbps", 4},
+		{"20Ebps", 0},
+	}
+
+	for _, test := range negative {
+		_, err := ParseBitRate(test.in)
+		if testing.Verbose() {
+			fmt.Printf("%q ==> %v\n", test.in, err)
+		}
+		var perr *ParseError
+		require.True(t, errors.As(err, &perr), test.in)
+		require.Equal(t, test.offset, perr.Offset, test.in)
+	}
+
+	for _, b := range []uint64{0, 999, 1000, 1500, 100 * Megabyte, 5 * Gigabyte / 2, 1<<64 - 1} {
+		str := BitRate(b).AsStr()
+		out, err := ParseBitRate(str)
+		if testing.Verbose() {
+			fmt.Printf("%v --> %v\n", b, str)
+		}
+		require.NoError(t, err, str)
+		require.Equal(t, BitRate(b), out, str)
+	}
+	require.Equal(t, "2.5Gbps", BitRate(5*Gigabyte/2).AsStr())
+
+	require.Equal(t, BitRate(800), Rate(100).Bits())
+	require.Equal(t, Rate(13), BitRate(100).Bytes())
+}
+
+func TestMarshalRate(t *testing.T) {
+	type throttle struct {
+		Limit Rate `json:"limit"`
+	}
+
+	bytes, err := json.Marshal(throttle{Limit: Rate(10 * Mebibyte)})
+	require.NoError(t, err)
+	require.Equal(t, `{"limit":"10MiB/s"}`, string(bytes))
+
+	var th throttle
+	require.NoError(t, json.Unmarshal([]byte(`{"limit":"1.5gb/min"}`), &th))
+	require.Equal(t, Rate(25*Megabyte), th.Limit)
+	require.Error(t, json.Unmarshal([]byte(`{"limit":"10MiB"}`), &th))
+
+	type link struct {
+		Speed BitRate `json:"speed"`
+	}
+
+	bytes, err = json.Marshal(link{Speed: BitRate(10 * Gigabyte)})
+	require.NoError(t, err)
+	require.Equal(t, `{"speed":"10Gbps"}`, string(bytes))
+
+	var l link
+	require.NoError(t, json.Unmarshal([]byte(`{"speed":"2.5Gbps"}`), &l))
+	require.Equal(t, BitRate(5*Gigabyte/2), l.Speed)
+}
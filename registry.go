@@ -0,0 +1,69 @@
+//go:build !bytez_minimal
+
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package bytez
+
+import (
+	"errors"
+	"fmt"
+)
+
+// A Registry holds custom units, like "blk" for 512-byte blocks or "pg" for 4KiB pages, which
+// WithRegistry adds to the units accepted by a Parser. The zero value is an empty registry ready
+// to use. A Registry is not safe for concurrent use, but parsers created from it are, and they are
+// not affected by units registered later.
+type Registry struct {
+	units map[string]uint64
+}
+
+// RegisterUnit adds units named name of multiplier bytes each. The name must start with a letter
+// and consist of ASCII letters, digits, and underscores, and cannot be a unit of this package or
+// already registered. Plurals are not implied, so "sector" and "sectors" must both be registered
+// to accept both.
+func (r *Registry) RegisterUnit(name string, multiplier uint64) error {
+	if name == "" || !isLetter(name[0]) {
+		return fmt.Errorf("invalid unit name %q", name)
+	}
+	for i := 0; i < len(name); i++ {
+		if b := name[i]; b > '~' || !(isLetter(b) || isDigit(b) || b == '_') {
+			return fmt.Errorf("invalid unit name %q", name)
+		}
+	}
+	if multiplier == 0 {
+		return errors.New("unit multiplier must not be zero")
+	} else if _, ok := lookupUnit(name); ok {
+		return fmt.Errorf("units %q already defined", name)
+	} else if _, ok := r.units[name]; ok {
+		return fmt.Errorf("units %q already registered", name)
+	}
+
+	if r.units == nil {
+		r.units = make(map[string]uint64)
+	}
+	r.units[name] = multiplier
+	return nil
+}
+
+// WithRegistry accepts the custom units registered in r, in addition to the units of this
+// package or of the profile of the parser. Custom units have exact values, which WithBase and
+// WithIgnoreCase do not change, and they are accepted by WithStrictUnits. They cannot be used
+// with profiles that have their own syntax, like Systemd and Nginx.
+func WithRegistry(r *Registry) Option {
+	return func(p *Parser) error {
+		if r == nil {
+			return errors.New("nil registry")
+		}
+		if p.custom == nil {
+			p.custom = make(map[string]uint64, len(r.units))
+		}
+		for name, val := range r.units {
+			p.custom[name] = val
+		}
+		return nil
+	}
+}
@@ -0,0 +1,105 @@
+//go:build !bytez_minimal
+
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package bytez
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry(t *testing.T) {
+	var r Registry
+	require.NoError(t, r.RegisterUnit("blk", 512))
+	require.NoError(t, r.RegisterUnit("pg", 4096))
+	require.NoError(t, r.RegisterUnit("sector", 512))
+	require.NoError(t, r.RegisterUnit("sectors", 512))
+	require.NoError(t, r.RegisterUnit("huge", 1<<63))
+	require.NoError(t, r.RegisterUnit("whole", 1<<64-1))
+
+	var tests = []struct {
+		opts []Option
+		in   string
+		out  uint64
+		err  bool
+	}{
+		{nil, "8blk", 4096, false},
+		{nil, "16 pg", 64 * Kibibyte, false},
+		{nil, "2 sectors", 1024, false},
+		{nil, "1.5blk", 768, false},
+		{nil, "4MiB", 4 * Mebibyte, false},
+		{nil, "4blks", 0, true},
+		{nil, "4BLK", 0, true},
+		{[]Option{WithBase(Base10)}, "1blk", 512, false},
+		{[]Option{WithIgnoreCase(Base2)}, "1blk", 512, false},
+		{[]Option{WithStrictUnits()}, "1pg", 4096, false},
+		{[]Option{WithStrict()}, "0.1blk", 0, true},
+		{[]Option{WithUnits("pg")}, "2pg", 8192, false},
+		{[]Option{WithUnits("pg")}, "2blk", 0, true},
+		{[]Option{WithDefaultUnit("blk")}, "100", 51200, false},
+		{[]Option{WithProfile(Kubernetes)}, "2pg", 8192, false},
+		{[]Option{WithProfile(Systemd)}, "2", 0, true},
+		{nil, "0.9 huge", 8301034833169298227, false},
+		{nil, "1.5 huge", 13835058055282163712, false},
+		{nil, "1.999 huge", 18437520701672696840, false},
+		{nil, "2 huge", 0, true},
+		{nil, "1 whole", 1<<64 - 1, false},
+		{nil, "0.5 whole", 1 << 63, false},
+		{nil, "0.0000000000000000001 whole", 2, false},
+		{nil, "1.01 whole", 0, true},
+	}
+
+	for _, test := range tests {
+		out, err := ParseSize(test.in, append([]Option{WithRegistry(&r)}, test.opts...)...)
+		if testing.Verbose() {
+			fmt.Printf("%q --> %v, %v\n", test.in, out, err)
+		}
+		if test.err {
+			require.Error(t, err, test.in)
+		} else {
+			require.NoError(t, err, test.in)
+			require.Equal(t, Size(test.out), out, test.in)
+		}
+	}
+
+	// Parsers are not affected by units registered after they were created.
+	p, err := NewParser(WithRegistry(&r))
+	require.NoError(t, err)
+	require.NoError(t, r.RegisterUnit("track", 32*Kibibyte))
+	_, err = p.Parse("1track")
+	require.Error(t, err)
+
+	// Units of a registry are not accepted without it.
+	_, err = ParseSize("8blk")
+	require.Error(t, err)
+	_, err = ParseSize("8blk", WithUnits("blk"))
+	require.EqualError(t, err, `unknown units "blk"`)
+	_, err = NewParser(WithRegistry(nil))
+	require.Error(t, err)
+}
+
+func TestRegisterUnit(t *testing.T) {
+	var r Registry
+	require.NoError(t, r.RegisterUnit("blk", 512))
+
+	for _, test := range []struct {
+		name       string
+		multiplier uint64
+	}{
+		{"", 1}, {"4k", 1}, {"_blk", 1}, {"b lk", 1}, {"blk-1", 1}, {"blké", 1}, {"pg", 0},
+		{"MiB", 1}, {"bytes", 1}, {"blk", 1024},
+	} {
+		err := r.RegisterUnit(test.name, test.multiplier)
+		if testing.Verbose() {
+			fmt.Printf("%q, %v ==> %v\n", test.name, test.multiplier, err)
+		}
+		require.Error(t, err, test.name)
+	}
+}
@@ -0,0 +1,103 @@
+//go:build !bytez_minimal
+
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package bytez
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"strconv"
+)
+
+// RoundingMode selects how computations that produce fractions of a byte round their results to
+// whole bytes.
+type RoundingMode int
+
+const (
+	// RoundHalfEven rounds to the nearest whole number, and halfway values to the even one,
+	// which avoids the bias of always rounding halves up. It is the zero value.
+	RoundHalfEven RoundingMode = iota
+
+	// RoundHalfUp rounds to the nearest whole number, and halfway values up.
+	RoundHalfUp
+
+	// RoundFloor rounds down, as when sizing to fit within a budget.
+	RoundFloor
+
+	// RoundCeil rounds up, as when sizing to hold all of something.
+	RoundCeil
+)
+
+// String returns the name of the rounding mode, like "RoundHalfEven".
+func (m RoundingMode) String() string {
+	switch m {
+	case RoundHalfEven:
+		return "RoundHalfEven"
+	case RoundHalfUp:
+		return "RoundHalfUp"
+	case RoundFloor:
+		return "RoundFloor"
+	case RoundCeil:
+		return "RoundCeil"
+	}
+	return "RoundingMode(" + strconv.Itoa(int(m)) + ")"
+}
+
+var errRoundingMode = errors.New("invalid rounding mode")
+
+// roundRat returns the non-negative value r rounded to a whole number according to mode.
+func roundRat(r *big.Rat, mode RoundingMode) (*big.Int, error) {
+	quo, rem := new(big.Int).QuoRem(r.Num(), r.Denom(), new(big.Int))
+	if rem.Sign() == 0 {
+		return quo, nil
+	}
+
+	var up bool
+	switch mode {
+	case RoundFloor:
+	case RoundCeil:
+		up = true
+	case RoundHalfEven, RoundHalfUp:
+		// Compare twice the remainder with the denominator to find which half it is in.
+		cmp := new(big.Int).Lsh(rem, 1).Cmp(r.Denom())
+		up = cmp > 0 || (cmp == 0 && (mode == RoundHalfUp || quo.Bit(0) == 1))
+	default:
+		return nil, errRoundingMode
+	}
+	if up {
+		quo.Add(quo, big.NewInt(1))
+	}
+	return quo, nil
+}
+
+// ScaleBy returns the size multiplied by f, rounded to whole bytes according to mode, for
+// computations like giving a cache 1.5 times its current size or reserving 7.5% of overhead
+// (f = 0.075). The multiplication is exact, taking f as the shortest decimal that represents it,
+// like 0.075, rather than its binary approximation, so the only rounding is the one requested.
+// An error is returned if f is negative, infinite, or NaN, or if the result does not fit in a
+// Size.
+func (sz Size) ScaleBy(f float64, mode RoundingMode) (Size, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, errors.New("scale factor must be a finite number")
+	} else if f < 0 {
+		return 0, errors.New("scale factor must not be negative")
+	}
+
+	// Use the decimal the factor was most likely written as, since 0.075, for example, is
+	// slightly less than 0.075 in binary and would round down when exactly a whole number.
+	val, _ := new(big.Rat).SetString(strconv.FormatFloat(f, 'g', -1, 64))
+	val.Mul(val, new(big.Rat).SetUint64(uint64(sz)))
+	num, err := roundRat(val, mode)
+	if err != nil {
+		return 0, err
+	} else if !num.IsUint64() {
+		return 0, errors.New("scaled size is too large")
+	}
+	return Size(num.Uint64()), nil
+}
@@ -0,0 +1,72 @@
+//go:build !bytez_minimal
+
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package bytez
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScaleBy(t *testing.T) {
+	var positive = []struct {
+		size Size
+		f    float64
+		mode RoundingMode
+		out  Size
+	}{
+		{Size(Gibibyte), 1.5, RoundHalfEven, Size(3 * Gibibyte / 2)},
+		{Size(100 * Mebibyte), 0.075, RoundCeil, 7864320},
+		{Size(100*Mebibyte + 1), 0.075, RoundCeil, 7864321},
+		{Size(100*Mebibyte + 1), 0.075, RoundFloor, 7864320},
+		{Size(100 * Mebibyte), 0.075, RoundFloor, 7864320},
+		{Size(100 * Mebibyte), 0.075, RoundHalfEven, 7864320},
+		{5, 0.5, RoundHalfEven, 2},
+		{7, 0.5, RoundHalfEven, 4},
+		{5, 0.5, RoundHalfUp, 3},
+		{5, 0.5, RoundFloor, 2},
+		{5, 0.5, RoundCeil, 3},
+		{10, 0.26, RoundHalfEven, 3},
+		{10, 0.24, RoundHalfUp, 2},
+		{0, 1e300, RoundCeil, 0},
+		{1000, 0, RoundCeil, 0},
+		{math.MaxUint64, 1, RoundHalfEven, math.MaxUint64},
+	}
+
+	for _, test := range positive {
+		out, err := test.size.ScaleBy(test.f, test.mode)
+		if testing.Verbose() {
+			fmt.Printf("%d * %v (%v) --> %d\n", test.size, test.f, test.mode, out)
+		}
+		require.NoError(t, err)
+		require.Equal(t, test.out, out, "%d * %v (%v)", test.size, test.f, test.mode)
+	}
+
+	var negative = []struct {
+		size Size
+		f    float64
+		mode RoundingMode
+	}{
+		{1, -1, RoundHalfEven},
+		{1, math.NaN(), RoundHalfEven},
+		{1, math.Inf(1), RoundHalfEven},
+		{math.MaxUint64, 1.5, RoundHalfEven},
+		{1, 0.5, RoundingMode(42)},
+	}
+
+	for _, test := range negative {
+		_, err := test.size.ScaleBy(test.f, test.mode)
+		if testing.Verbose() {
+			fmt.Printf("%d * %v (%v) ==> %v\n", test.size, test.f, test.mode, err)
+		}
+		require.Error(t, err)
+	}
+}
@@ -0,0 +1,89 @@
+//go:build !bytez_minimal
+
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package bytez
+
+// A Match is a size found in free text, with the byte offsets of its start and end in the text.
+type Match struct {
+	Size       Size
+	Start, End int
+}
+
+// ScanSize finds the first size in free text, like "4GiB" in "the limit is 4GiB per user", and
+// returns it along with its position, or false if the text has no size. It is meant for mining
+// logs and error messages for sizes.
+//
+// A size is a number with units, in the syntax accepted by AsInt, that is not part of a larger
+// word or number: numbers without units, like "3 users", and sizes preceded or followed by letters
+// or digits, like "x4GiB" or "4GiBx", are skipped.
+func ScanSize(text string) (Match, bool) {
+	return scanSize(text, 0)
+}
+
+// FindAllSizes returns all the sizes in free text, like a build log or a report, in the order in
+// which they appear, as found by ScanSize. It returns nil if the text has no sizes.
+func FindAllSizes(text string) []Match {
+	var matches []Match
+	for m, ok := scanSize(text, 0); ok; m, ok = scanSize(text, m.End) {
+		matches = append(matches, m)
+	}
+	return matches
+}
+
+// scanSize returns the first size in text that starts at or after offset from.
+func scanSize(text string, from int) (Match, bool) {
+	for i := from; i < len(text); i++ {
+		if !isDigit(text[i]) || continuesWord(text, i) {
+			continue
+		}
+
+		num, end, err := scanNumber(text[i:], ',', '.')
+		if err != nil {
+			continue
+		}
+		end += i
+
+		start := end
+		if start < len(text) && text[start] == ' ' {
+			start++
+		}
+		stop := start
+		for stop < len(text) && isWordByte(text[stop]) && !isDigit(text[stop]) {
+			stop++
+		}
+		if stop < len(text) && isDigit(text[stop]) {
+			stop = start
+		}
+
+		if unit, ok := lookupUnit(text[start:stop]); ok && stop > start {
+			if val, err := num.bytes(unit); err == nil {
+				return Match{Size: Size(val), Start: i, End: stop}, true
+			}
+		}
+		i = end - 1
+	}
+	return Match{}, false
+}
+
+// continuesWord reports whether the digit at text[i] continues a word or a number, as in "x4"
+// or "1.5" at the 4 or the 5.
+func continuesWord(text string, i int) bool {
+	if i == 0 {
+		return false
+	} else if isWordByte(text[i-1]) {
+		return true
+	}
+	sep := text[i-1]
+	return (sep == '.' || sep == ',' || sep == '_') && i > 1 && isDigit(text[i-2])
+}
+
+// isWordByte reports whether b is an ASCII letter or digit, or a byte of a multibyte character,
+// which sizes cannot be next to in free text.
+func isWordByte(b byte) bool {
+	return isDigit(b) || (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z') || b >= 0x80
+}
@@ -0,0 +1,73 @@
+//go:build !bytez_minimal
+
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package bytez
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanSize(t *testing.T) {
+	var tests = []struct {
+		in    string
+		size  uint64
+		match string
+	}{
+		{"the limit is 4GiB per user", 4 * Gibibyte, "4GiB"},
+		{"4GiB", 4 * Gibibyte, "4GiB"},
+		{"wrote 1.5 MiB in 3 seconds", 3 * Mebibyte / 2, "1.5 MiB"},
+		{"3 users, 12 files, 1,024 KiB total", Mebibyte, "1,024 KiB"},
+		{"heap: 512MiB.", 512 * Mebibyte, "512MiB"},
+		{"(100Mbit)", 12500000, "100Mbit"},
+		{"got 4 megabytes", 4 * Megabyte, "4 megabytes"},
+		{"v1.5GiB 2GiB", 2 * Gibibyte, "2GiB"},
+		{"x4GiB 4GiBx 4GiB2 4GiB", 4 * Gibibyte, "4GiB"},
+		{"1,5GiB or 3GiB", 3 * Gibibyte, "3GiB"},
+		{"64EiB then 1k", Kilobyte, "1k"},
+		{"1.2.3 MiB, 7kb", 7 * Kilobyte, "7kb"},
+		{"é4GiB 4GiBé 5 GiB", 5 * Gibibyte, "5 GiB"},
+		{"", 0, ""},
+		{"no sizes here, only 42 numbers", 0, ""},
+		{"4GiBs of 12GiBit", 0, ""},
+	}
+
+	for _, test := range tests {
+		m, ok := ScanSize(test.in)
+		if testing.Verbose() {
+			fmt.Printf("%q --> %+v %v\n", test.in, m, ok)
+		}
+		require.Equal(t, test.match != "", ok, test.in)
+		if ok {
+			require.Equal(t, Size(test.size), m.Size, test.in)
+			require.Equal(t, test.match, test.in[m.Start:m.End], test.in)
+		}
+	}
+}
+
+func TestFindAllSizes(t *testing.T) {
+	text := "compiled 12 packages: 1.5MiB binary, 640KiB of debug info.\n" +
+		"cache hit 2GiB/4GiB, 3 users, v2GiB, 100Mbit link"
+
+	var found []string
+	var total Size
+	for _, m := range FindAllSizes(text) {
+		found = append(found, text[m.Start:m.End])
+		total += m.Size
+	}
+	if testing.Verbose() {
+		fmt.Printf("%q --> %q\n", text, found)
+	}
+	require.Equal(t, []string{"1.5MiB", "640KiB", "2GiB", "4GiB", "100Mbit"}, found)
+	require.Equal(t, Size(3*Mebibyte/2+640*Kibibyte+6*Gibibyte+12500000), total)
+
+	require.Nil(t, FindAllSizes(""))
+	require.Nil(t, FindAllSizes("3 users"))
+}
@@ -0,0 +1,95 @@
+//go:build !bytez_minimal
+
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package bytez
+
+import (
+	"math"
+	"strings"
+)
+
+// SignedSize is a number of bytes that may be negative, like the difference between two sizes or
+// the growth or shrinkage of a quota. It is formatted with an explicit sign, like "+1.5GiB" or
+// "-500MiB", and marshals to and from text like Size.
+type SignedSize int64
+
+// Delta returns the change from one size to another, like "-512MiB" from 2GiB to 1.5GiB,
+// saturating at the smallest or largest SignedSize if it does not fit.
+func Delta(from, to Size) SignedSize {
+	if to >= from {
+		return SignedSize(min(uint64(to-from), math.MaxInt64))
+	}
+	return SignedSize(-min(uint64(from-to), 1<<63))
+}
+
+// ParseSigned parses a size with an optional sign, like "-500MiB" or "+1.5GiB". The sign must be
+// followed directly by the size, which has the syntax accepted by AsInt. Sizes that do not fit in
+// an int64 are rejected. Errors are of type *ParseError.
+func ParseSigned(str string) (SignedSize, error) {
+	trimmed := strings.TrimLeft(str, " \t\r\n")
+	lead := len(str) - len(trimmed)
+	negative := strings.HasPrefix(trimmed, "-")
+	if negative || strings.HasPrefix(trimmed, "+") {
+		lead++
+		if spaces(str[lead:]) > 0 {
+			return 0, newParseError(str, lead, ErrNoNumber)
+		}
+	}
+
+	val, offset, err := asInt(str[lead:])
+	if err != nil {
+		return 0, newParseError(str, lead+offset, err)
+	} else if negative && val <= 1<<63 {
+		return SignedSize(-val), nil
+	} else if !negative && val <= math.MaxInt64 {
+		return SignedSize(val), nil
+	}
+	return 0, newParseError(str, lead, ErrOverflow)
+}
+
+// Abs returns the magnitude of the size.
+func (s SignedSize) Abs() Size {
+	if s < 0 {
+		return Size(-uint64(s))
+	}
+	return Size(s)
+}
+
+// AsStr returns the size formatted like AsStr with an explicit sign, like "+1.5GiB" or "-500MiB",
+// or "0" for zero.
+func (s SignedSize) AsStr() string {
+	var buf [32]byte
+	return string(s.appendStr(buf[:0], appendDefault))
+}
+
+// appendStr appends the formatted size to dst, with the magnitude formatted by format.
+func (s SignedSize) appendStr(dst []byte, format func([]byte, uint64) []byte) []byte {
+	if s < 0 {
+		dst = append(dst, '-')
+	} else if s > 0 {
+		dst = append(dst, '+')
+	}
+	return format(dst, uint64(s.Abs()))
+}
+
+// MarshalText implements the encoding.TextMarshaler interface, formatting the size like AsStr
+// does by default, whatever the default format is. Returned error is always nil.
+func (s SignedSize) MarshalText() ([]byte, error) {
+	return s.appendStr(nil, appendStr), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface using ParseSigned.
+func (s *SignedSize) UnmarshalText(text []byte) error {
+	val, err := ParseSigned(string(text))
+	if err != nil {
+		return err
+	}
+
+	*s = val
+	return nil
+}
@@ -0,0 +1,122 @@
+//go:build !bytez_minimal
+
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package bytez
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSigned(t *testing.T) {
+	var positive = []struct {
+		in  string
+		out SignedSize
+	}{
+		{"0", 0},
+		{"-0", 0},
+		{"4096", 4096},
+		{"+1.5GiB", SignedSize(3 * Gibibyte / 2)},
+		{"-500MiB", -SignedSize(500 * Mebibyte)},
+		{" -4 KiB ", -SignedSize(4 * Kibibyte)},
+		{"-1,024", -1024},
+		{"+7.5EiB", SignedSize(15 * Exbibyte / 2)},
+		{"-8EiB", math.MinInt64},
+		{"9223372036854775807", math.MaxInt64},
+	}
+
+	for _, test := range positive {
+		out, err := ParseSigned(test.in)
+		if testing.Verbose() {
+			fmt.Printf("%q --> %v\n", test.in, out)
+		}
+		require.NoError(t, err, test.in)
+		require.Equal(t, test.out, out, test.in)
+	}
+
+	var negative = []struct {
+		in     string
+		offset int
+	}{
+		{"", 0},
+		{"-", 1},
+		{"- 4KiB", 1},
+		{"--4KiB", 1},
+		{"+-4KiB", 1},
+		{"4KiB-", 1},
+		{"-4 KiBs", 3},
+		{"8EiB", 0},
+		{" +8EiB", 2},
+		{"-9223372036854775809", 1},
+	}
+
+	for _, test := range negative {
+		_, err := ParseSigned(test.in)
+		if testing.Verbose() {
+			fmt.Printf("%q ==> %v\n", test.in, err)
+		}
+		var perr *ParseError
+		require.True(t, errors.As(err, &perr), test.in)
+		require.Equal(t, test.offset, perr.Offset, test.in)
+	}
+}
+
+func TestSignedSize(t *testing.T) {
+	var tests = []struct {
+		in  SignedSize
+		out string
+	}{
+		{0, "0"},
+		{1536, "+1.5KiB"},
+		{-SignedSize(512 * Mebibyte), "-512MiB"},
+		{-1000, "-1kb"},
+		{-1, "-1"},
+		{math.MinInt64, "-8EiB"},
+		{math.MaxInt64, "+9223372036854775807"},
+	}
+
+	for _, test := range tests {
+		s := test.in
+		if testing.Verbose() {
+			fmt.Printf("%v --> %v\n", test.in, s.AsStr())
+		}
+		require.Equal(t, test.out, s.AsStr())
+
+		parsed, err := ParseSigned(s.AsStr())
+		require.NoError(t, err, test.out)
+		require.Equal(t, s, parsed, test.out)
+	}
+
+	require.Equal(t, Size(1<<63), SignedSize(math.MinInt64).Abs())
+	require.Equal(t, Size(Kibibyte), (-SignedSize(Kibibyte)).Abs())
+
+	require.Equal(t, -SignedSize(512*Mebibyte), Delta(Size(2*Gibibyte), Size(3*Gibibyte/2)))
+	require.Equal(t, SignedSize(512*Mebibyte), Delta(Size(3*Gibibyte/2), Size(2*Gibibyte)))
+	require.Equal(t, SignedSize(math.MaxInt64), Delta(0, Size(1<<64-1)))
+	require.Equal(t, SignedSize(math.MinInt64), Delta(Size(1<<64-1), 0))
+}
+
+func TestMarshalSigned(t *testing.T) {
+	type report struct {
+		Growth SignedSize `json:"growth"`
+	}
+
+	bytes, err := json.Marshal(report{Growth: -SignedSize(3 * Gibibyte / 2)})
+	require.NoError(t, err)
+	require.Equal(t, `{"growth":"-1.5GiB"}`, string(bytes))
+
+	var r report
+	require.NoError(t, json.Unmarshal([]byte(`{"growth":"+50mb"}`), &r))
+	require.Equal(t, SignedSize(50*Megabyte), r.Growth)
+	require.Error(t, json.Unmarshal([]byte(`{"growth":"50 mbs"}`), &r))
+}
@@ -0,0 +1,118 @@
+//go:build !bytez_minimal
+
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package bytez
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Value implements the database/sql/driver.Valuer interface. The size is stored as the exact
+// number of bytes in an integer column, so it can be compared and summed by the database. An
+// error is returned if the size does not fit in an int64, which is what drivers support.
+func (sz Size) Value() (driver.Value, error) {
+	if uint64(sz) > math.MaxInt64 {
+		return nil, fmt.Errorf("size %d is too large to store", uint64(sz))
+	}
+	return int64(sz), nil
+}
+
+// Scan implements the database/sql.Scanner interface. In addition to integers, it accepts text
+// in any format accepted by AsInt, so sizes can also be stored in text columns or assigned from
+// human-friendly strings, like "64MiB", by ORMs that set fields through Scan. Use *Size for
+// nullable columns.
+func (sz *Size) Scan(src interface{}) error {
+	switch val := src.(type) {
+	case int64:
+		if val < 0 {
+			return fmt.Errorf("negative value %d is not a valid size", val)
+		}
+		*sz = Size(val)
+	case uint64:
+		*sz = Size(val)
+	case float64:
+		if val < 0 || val >= math.MaxUint64 || val != math.Trunc(val) {
+			return fmt.Errorf("value %v is not a valid size", val)
+		}
+		*sz = Size(val)
+	case []byte:
+		return sz.scanText(string(val))
+	case string:
+		return sz.scanText(val)
+	case nil:
+		return errors.New("cannot scan NULL into Size")
+	default:
+		return fmt.Errorf("cannot scan %T into Size", src)
+	}
+	return nil
+}
+
+// GormDataType returns the general data type used by GORM (gorm.io/gorm) for Size fields. It
+// implements GORM's schema.GormDataTypeInterface so that migrations create 64-bit integer columns
+// (BIGINT on most databases) for Size fields without needing a "type" tag. GORM passes the values
+// of create and update maps to the driver as they are, so use ParseMap to convert human-friendly
+// strings in them.
+func (Size) GormDataType() string {
+	return "int"
+}
+
+// ParseMap replaces the string and []byte values of the given keys of m, in any format accepted
+// by AsInt, like "64MiB", with the Sizes they specify, so that maps of column values, like those
+// passed to GORM's Create and Updates, store the exact number of bytes in integer columns:
+//
+//	updates := map[string]interface{}{"name": "uploads", "quota": "64MiB"}
+//	if err := bytez.ParseMap(updates, "quota"); err != nil {
+//		return err
+//	}
+//	db.Model(&bucket).Updates(updates)
+//
+// Missing keys and values of other types are left as they are. If a value cannot be parsed, an
+// error naming its key and wrapping the error of AsInt is returned, and m may have been partly
+// converted.
+func ParseMap(m map[string]interface{}, keys ...string) error {
+	for _, key := range keys {
+		var str string
+		switch val := m[key].(type) {
+		case string:
+			str = val
+		case []byte:
+			str = string(val)
+		default:
+			continue
+		}
+
+		val, err := AsInt(str)
+		if err != nil {
+			return fmt.Errorf("%s=%q: %w", key, str, err)
+		}
+		m[key] = Size(val)
+	}
+	return nil
+}
+
+// scanText parses str as a size or as a whole number with a fractional part of zeros, like
+// "1024.000", which is how many drivers return NUMERIC and DECIMAL columns.
+func (sz *Size) scanText(str string) error {
+	err := sz.UnmarshalText([]byte(str))
+	if err == nil {
+		return nil
+	}
+
+	if idx := strings.IndexByte(str, '.'); idx > 0 && strings.Trim(str[idx+1:], "0") == "" {
+		if val, perr := strconv.ParseUint(str[:idx], 10, 64); perr == nil {
+			*sz = Size(val)
+			return nil
+		}
+	}
+	return err
+}
@@ -0,0 +1,106 @@
+//go:build !bytez_minimal
+
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package bytez
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValue(t *testing.T) {
+	val, err := Size(64 * Mebibyte).Value()
+	require.NoError(t, err)
+	require.Equal(t, driver.Value(int64(64*Mebibyte)), val)
+
+	_, err = Size(1<<64 - 1).Value()
+	require.Error(t, err)
+}
+
+func TestScan(t *testing.T) {
+	var negative = []struct {
+		in interface{}
+	}{
+		{nil},
+		{int64(-1)},
+		{1.5},
+		{"64 MiBs"},
+		{"1024.5"},
+		{"-1024.000"},
+		{true},
+	}
+
+	for _, test := range negative {
+		var sz Size
+		err := sz.Scan(test.in)
+		if testing.Verbose() {
+			fmt.Printf("%#v ==> %v\n", test.in, err)
+		}
+		require.Error(t, err)
+	}
+
+	var positive = []struct {
+		in  interface{}
+		out uint64
+	}{
+		{int64(4096), 4 * Kibibyte},
+		{uint64(4096), 4 * Kibibyte},
+		{float64(4096), 4 * Kibibyte},
+		{[]byte("4KiB"), 4 * Kibibyte},
+		{"4.5 GiB", 4*Gibibyte + Gibibyte/2},
+		{[]byte("1024.000"), Kibibyte},
+		{"18446744073709551615.0", 1<<64 - 1},
+	}
+
+	for _, test := range positive {
+		var sz Size
+		err := sz.Scan(test.in)
+		if testing.Verbose() {
+			fmt.Printf("%#v --> %v\n", test.in, sz)
+		}
+		require.NoError(t, err)
+		require.Equal(t, test.out, uint64(sz))
+	}
+
+	require.Equal(t, "int", Size(0).GormDataType())
+}
+
+func TestParseMap(t *testing.T) {
+	m := map[string]interface{}{
+		"name":   "uploads",
+		"quota":  "64MiB",
+		"buffer": []byte("4 KiB"),
+		"limit":  int64(4096),
+		"size":   Size(512),
+	}
+	require.NoError(t, ParseMap(m, "quota", "buffer", "limit", "size", "missing"))
+	if testing.Verbose() {
+		fmt.Printf("%v\n", m)
+	}
+	require.Equal(t, map[string]interface{}{
+		"name":   "uploads",
+		"quota":  Size(64 * Mebibyte),
+		"buffer": Size(4 * Kibibyte),
+		"limit":  int64(4096),
+		"size":   Size(512),
+	}, m)
+
+	// The converted values are stored as integers.
+	val, err := driver.DefaultParameterConverter.ConvertValue(m["quota"])
+	require.NoError(t, err)
+	require.Equal(t, int64(64*Mebibyte), val)
+
+	err = ParseMap(map[string]interface{}{"quota": "64MiBs"}, "quota")
+	require.EqualError(t, err, `quota="64MiBs": invalid units: did you mean "MiB"?`)
+	var perr *ParseError
+	require.True(t, errors.As(err, &perr))
+}
@@ -0,0 +1,17 @@
+module github.com/nexvium/bytez/textsize
+
+go 1.24.0
+
+require (
+	github.com/nexvium/bytez v0.0.0-00010101000000-000000000000
+	github.com/stretchr/testify v1.4.0
+	golang.org/x/text v0.29.0
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	gopkg.in/yaml.v2 v2.2.2 // indirect
+)
+
+replace github.com/nexvium/bytez => ../
@@ -0,0 +1,88 @@
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+// Package textsize formats sizes for the users of a locale with golang.org/x/text, with the
+// decimal separator, digit grouping, and unit symbols of their language, like "1,5 Gio" in French
+// or "1.234,5 MB" in German:
+//
+//	textsize.FormatLocalized(size, language.French)
+//
+// This package is a separate module so that package bytez itself does not depend on x/text.
+package textsize
+
+import (
+	"math"
+
+	"github.com/nexvium/bytez"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/number"
+)
+
+// units are the symbols of the units of a language, in binary and decimal units, from bytes to
+// exbibytes and exabytes.
+type units struct {
+	binary, decimal [7]string
+}
+
+var (
+	byteUnits = units{
+		binary:  [7]string{"B", "KiB", "MiB", "GiB", "TiB", "PiB", "EiB"},
+		decimal: [7]string{"B", "kB", "MB", "GB", "TB", "PB", "EB"},
+	}
+	octetUnits = units{
+		binary:  [7]string{"o", "Kio", "Mio", "Gio", "Tio", "Pio", "Eio"},
+		decimal: [7]string{"o", "ko", "Mo", "Go", "To", "Po", "Eo"},
+	}
+	russianUnits = units{
+		binary:  [7]string{"Б", "КиБ", "МиБ", "ГиБ", "ТиБ", "ПиБ", "ЭиБ"},
+		decimal: [7]string{"Б", "кБ", "МБ", "ГБ", "ТБ", "ПБ", "ЭБ"},
+	}
+)
+
+// localUnits are the unit symbols of languages that do not use those of English.
+var localUnits = map[language.Base]units{
+	language.MustParseBase("fr"): octetUnits,
+	language.MustParseBase("ro"): octetUnits,
+	language.MustParseBase("ru"): russianUnits,
+	language.MustParseBase("uk"): russianUnits,
+}
+
+var valuesBase2 = [7]uint64{1, bytez.Kibibyte, bytez.Mebibyte, bytez.Gibibyte, bytez.Tebibyte,
+	bytez.Pebibyte, bytez.Exbibyte}
+
+var valuesBase10 = [7]uint64{1, bytez.Kilobyte, bytez.Megabyte, bytez.Gigabyte, bytez.Terabyte,
+	bytez.Petabyte, bytez.Exabyte}
+
+// FormatLocalized returns size for the users of the given locale, in the largest units that fit,
+// rounded to one decimal like bytez.AsApproxStr, with the number formatted by x/text for the
+// locale and followed by a space and the unit symbol of its language, like "1,5 Gio" in French.
+// Multiples of 500 use decimal units and other sizes use binary units.
+func FormatLocalized(size bytez.Size, tag language.Tag) string {
+	base, _ := tag.Base()
+	symbols, ok := localUnits[base]
+	if !ok {
+		symbols = byteUnits
+	}
+	values, names := valuesBase2, symbols.binary
+	if size%500 == 0 {
+		values, names = valuesBase10, symbols.decimal
+	}
+
+	idx := len(values) - 1
+	for idx > 0 && uint64(size) < values[idx] {
+		idx--
+	}
+	val := float64(size) / float64(values[idx])
+	if idx > 0 && idx < len(values)-1 && math.Round(val*10)/10 >= float64(values[1]) {
+		// Rounding reached the next unit, as in 1023.99KiB rounding to "1024 KiB".
+		idx++
+		val = float64(size) / float64(values[idx])
+	}
+
+	p := message.NewPrinter(tag)
+	return p.Sprint(number.Decimal(val, number.MaxFractionDigits(1))) + " " + names[idx]
+}
@@ -0,0 +1,47 @@
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package textsize
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/nexvium/bytez"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+func TestFormatLocalized(t *testing.T) {
+	var tests = []struct {
+		tag language.Tag
+		in  uint64
+		out string
+	}{
+		{language.English, 3 * bytez.Gibibyte / 2, "1.5 GiB"},
+		{language.English, 1000*bytez.Kibibyte + 1, "1,000 KiB"},
+		{language.English, 999, "999 B"},
+		{language.English, 0, "0 B"},
+		{language.English, 1048575, "1 MiB"},
+		{language.French, 3 * bytez.Gibibyte / 2, "1,5 Gio"},
+		{language.French, 4 * bytez.Megabyte, "4 Mo"},
+		{language.French, 1000, "1 ko"},
+		{language.German, 1000*bytez.Kibibyte + 1, "1.000 KiB"},
+		{language.German, 3 * bytez.Mebibyte / 2, "1,5 MiB"},
+		{language.MustParse("fr-CA"), 2 * bytez.Tebibyte, "2 Tio"},
+		{language.Russian, 3 * bytez.Gibibyte / 2, "1,5 ГиБ"},
+		{language.Japanese, 3 * bytez.Gibibyte / 2, "1.5 GiB"},
+		{language.English, 1<<64 - 1, "16 EiB"},
+	}
+
+	for _, test := range tests {
+		out := FormatLocalized(bytez.Size(test.in), test.tag)
+		if testing.Verbose() {
+			fmt.Printf("%v %v --> %q\n", test.tag, test.in, out)
+		}
+		require.Equal(t, test.out, out, test.tag.String())
+	}
+}
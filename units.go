@@ -0,0 +1,119 @@
+//go:build !bytez_minimal
+
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package bytez
+
+import (
+	"strings"
+	"unicode"
+)
+
+var unitMap = func() map[string]uint64 {
+	units := map[string]uint64{
+		"k": Kilobyte, "kb": Kilobyte, "kB": Kilobyte,
+		"m": Megabyte, "mb": Megabyte, "mB": Megabyte,
+		"g": Gigabyte, "gb": Gigabyte, "gB": Gigabyte,
+		"t": Terabyte, "tb": Terabyte, "tB": Terabyte,
+		"p": Petabyte, "pb": Petabyte, "pB": Petabyte,
+		"e": Exabyte, "eb": Exabyte, "eB": Exabyte,
+
+		"K": Kibibyte, "KB": Kibibyte, "Kb": Kibibyte, "Ki": Kibibyte, "KiB": Kibibyte,
+		"M": Mebibyte, "MB": Mebibyte, "Mb": Mebibyte, "Mi": Mebibyte, "MiB": Mebibyte,
+		"G": Gibibyte, "GB": Gibibyte, "Gb": Gibibyte, "Gi": Gibibyte, "GiB": Gibibyte,
+		"T": Tebibyte, "TB": Tebibyte, "Tb": Tebibyte, "Ti": Tebibyte, "TiB": Tebibyte,
+		"P": Pebibyte, "PB": Pebibyte, "Pb": Pebibyte, "Pi": Pebibyte, "PiB": Pebibyte,
+		"E": Exbibyte, "EB": Exbibyte, "Eb": Exbibyte, "Ei": Exbibyte, "EiB": Exbibyte,
+	}
+
+	// Full words, like "megabyte" and "megabytes", are also accepted, as is "B" for bytes.
+	units["B"] = 1
+	for i := range wordsBase10 {
+		units[wordsBase10[i]], units[wordsBase10[i]+"s"] = valuesBase10[i], valuesBase10[i]
+		units[wordsBase2[i]], units[wordsBase2[i]+"s"] = valuesBase2[i], valuesBase2[i]
+	}
+
+	// So are bit units, like "Mbit" and "Mibit", which are always a whole number of bytes.
+	for i := 0; i < len(bitPrefixes); i++ {
+		units[bitPrefixes[i:i+1]+"bit"] = valuesBase10[i+1] / 8
+		units[strings.ToUpper(bitPrefixes[i:i+1])+"ibit"] = valuesBase2[i+1] / 8
+	}
+	return units
+}()
+
+// lookupUnit returns the number of bytes in the given units.
+func lookupUnit(units string) (uint64, bool) {
+	val, ok := unitMap[units]
+	return val, ok
+}
+
+// isLetter reports whether b, the first byte of the units, is a letter.
+func isLetter(b byte) bool {
+	return unicode.IsLetter(rune(b))
+}
+
+// suggestedUnits are the units suggested for invalid units: the units used by AsStr and full
+// words.
+var suggestedUnits = func() []string {
+	units := append(append([]string{}, unitsBase2[1:]...), unitsBase10[1:]...)
+	for i := range wordsBase10 {
+		units = append(units, wordsBase2[i]+"s", wordsBase2[i], wordsBase10[i]+"s", wordsBase10[i])
+	}
+	return units
+}()
+
+// suggestUnits returns the candidate most similar to the invalid units, or "" if none is similar
+// enough: a candidate differing only in case, or else one at most one edit away from units of two
+// to four letters, or two edits from longer ones. Ties go to the earliest candidate.
+func suggestUnits(units string, candidates []string) string {
+	for _, c := range candidates {
+		if strings.EqualFold(units, c) {
+			return c
+		}
+	}
+
+	best, bestDist := "", 2
+	if len(units) < 2 {
+		return ""
+	} else if len(units) > 4 {
+		bestDist = 3
+	}
+	for _, c := range candidates {
+		if d := editDistance(units, c); d < bestDist {
+			best, bestDist = c, d
+		}
+	}
+	return best
+}
+
+// editDistance returns the number of insertions, deletions, substitutions, and transpositions of
+// adjacent bytes needed to turn a into b.
+func editDistance(a, b string) int {
+	if len(a) > 16 || len(b) > 16 {
+		return len(a) + len(b)
+	}
+	var rows [3][17]int
+	prev2, prev, cur := &rows[0], &rows[1], &rows[2]
+	for j := 0; j <= len(b); j++ {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				cur[j] = min(cur[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+	return prev[len(b)]
+}
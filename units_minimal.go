@@ -0,0 +1,82 @@
+//go:build bytez_minimal
+
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+package bytez
+
+const (
+	prefixesBase10 = "kmgtpe"
+	prefixesBase2  = "KMGTPE"
+)
+
+// lookupUnit returns the number of bytes in the given units. It accepts the same units as the
+// default build but decodes them directly instead of using a map.
+func lookupUnit(units string) (uint64, bool) {
+	if units == "" {
+		return 0, false
+	} else if units == "B" {
+		return 1, true
+	} else if len(units) > len("KiB") {
+		if val, ok := lookupBits(units); ok {
+			return val, true
+		}
+		return lookupWord(units)
+	}
+
+	prefix, suffix := units[0], units[1:]
+	for i := 0; i < len(prefixesBase10); i++ {
+		if prefix == prefixesBase10[i] {
+			return valuesBase10[i+1], suffix == "" || suffix == "b" || suffix == "B"
+		} else if prefix == prefixesBase2[i] {
+			return valuesBase2[i+1], suffix == "" || suffix == "B" || suffix == "b" ||
+				suffix == "i" || suffix == "iB"
+		}
+	}
+	return 0, false
+}
+
+// lookupBits returns the number of bytes in bit units, like "Mbit" and "Mibit".
+func lookupBits(units string) (uint64, bool) {
+	prefix, suffix := units[0], units[1:]
+	for i := 0; i < len(bitPrefixes); i++ {
+		if prefix == bitPrefixes[i] && suffix == "bit" {
+			return valuesBase10[i+1] / 8, true
+		} else if prefix == prefixesBase2[i] && suffix == "ibit" {
+			return valuesBase2[i+1] / 8, true
+		}
+	}
+	return 0, false
+}
+
+// lookupWord returns the number of bytes in the units named by a full word, like "megabytes".
+func lookupWord(word string) (uint64, bool) {
+	if word[len(word)-1] == 's' {
+		word = word[:len(word)-1]
+	}
+	for i := range wordsBase10 {
+		if word == wordsBase10[i] {
+			return valuesBase10[i], true
+		} else if word == wordsBase2[i] {
+			return valuesBase2[i], true
+		}
+	}
+	return 0, false
+}
+
+// isLetter reports whether b, the first byte of the units, is a letter. Only ASCII letters are
+// recognized, which is sufficient since all units are ASCII.
+func isLetter(b byte) bool {
+	return (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z')
+}
+
+// suggestedUnits is empty in the minimal build, which does not suggest units.
+var suggestedUnits []string
+
+// suggestUnits returns "", since the minimal build does not suggest units.
+func suggestUnits(units string, candidates []string) string {
+	return ""
+}
@@ -0,0 +1,43 @@
+// MIT License
+//
+// Copyright (c) 2019 Javier Alvarado
+
+// bytez.js loads bytez.wasm, built from this directory, and exposes its functions with JavaScript
+// types. Go's wasm_exec.js, found in $(go env GOROOT)/lib/wasm, must be loaded first.
+
+// load instantiates the WebAssembly module from source, which can be a URL or the bytes of the
+// module, and resolves to an object with parse and format functions.
+export async function load(source) {
+	const go = new Go();
+	const { instance } = typeof source === "string" || source instanceof URL
+		? await WebAssembly.instantiateStreaming(fetch(source), go.importObject)
+		: await WebAssembly.instantiate(source, go.importObject);
+	go.run(instance);
+
+	const impl = globalThis.bytezGo;
+	const unwrap = ([value, err]) => {
+		if (err !== null) {
+			throw new Error(err);
+		}
+		return value;
+	};
+
+	return {
+		// parse returns the number of bytes in a size string, like "4MiB", as a BigInt.
+		parse: (str) => BigInt(unwrap(impl.parse(String(str)))),
+
+		// parseNumber is like parse but returns a Number, throwing a RangeError if the size
+		// cannot be represented exactly.
+		parseNumber(str) {
+			const n = this.parse(str);
+			if (n > BigInt(Number.MAX_SAFE_INTEGER)) {
+				throw new RangeError(`size ${str} exceeds Number.MAX_SAFE_INTEGER`);
+			}
+			return Number(n);
+		},
+
+		// format returns a number of bytes, given as a Number, BigInt, or string, as a size
+		// string, like "4MiB".
+		format: (bytes) => unwrap(impl.format(typeof bytes === "string" ? bytes : BigInt(bytes).toString())),
+	};
+}
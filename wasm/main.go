@@ -0,0 +1,72 @@
+//go:build js && wasm
+
+/*
+	MIT License
+
+	Copyright (c) 2019 Javier Alvarado
+*/
+
+// Command wasm exposes the size parsing and formatting of package bytez to JavaScript, so web
+// frontends and Go backends share exactly the same semantics. Build it with:
+//
+//	GOOS=js GOARCH=wasm go build -tags bytez_minimal -o bytez.wasm ./wasm
+//
+// and load it with the bytez.js module in this directory, which needs Go's wasm_exec.js to be
+// loaded first:
+//
+//	import { load } from "./bytez.js";
+//
+//	const bytez = await load("bytez.wasm");
+//	bytez.parse("1.5 GiB");     // 1610612736n
+//	bytez.format(4194304);      // "4MiB"
+//
+// The program registers a global bytezGo object with parse and format functions that take and
+// return sizes as decimal strings, since JavaScript numbers cannot represent all 64-bit sizes, and
+// report errors as the second element of the returned pair. The module converts the values and
+// throws the errors.
+package main
+
+import (
+	"strconv"
+	"syscall/js"
+
+	"github.com/nexvium/bytez"
+)
+
+func main() {
+	js.Global().Set("bytezGo", js.ValueOf(map[string]interface{}{
+		"parse":  js.FuncOf(parse),
+		"format": js.FuncOf(format),
+	}))
+
+	// Keep the functions available for as long as the page is.
+	select {}
+}
+
+// parse returns [bytes, null] for a size string, like "4MiB", or [null, error].
+func parse(this js.Value, args []js.Value) interface{} {
+	if len(args) != 1 || args[0].Type() != js.TypeString {
+		return result(nil, "parse: expected a string")
+	}
+	n, err := bytez.AsInt(args[0].String())
+	if err != nil {
+		return result(nil, "parse: "+err.Error())
+	}
+	return result(strconv.FormatUint(n, 10), nil)
+}
+
+// format returns [size, null] for a number of bytes given as a decimal string, or [null, error].
+func format(this js.Value, args []js.Value) interface{} {
+	if len(args) != 1 || args[0].Type() != js.TypeString {
+		return result(nil, "format: expected a string")
+	}
+	n, err := strconv.ParseUint(args[0].String(), 10, 64)
+	if err != nil {
+		return result(nil, "format: invalid number of bytes "+strconv.Quote(args[0].String()))
+	}
+	return result(bytez.AsStr(n), nil)
+}
+
+func result(val, err interface{}) interface{} {
+	return []interface{}{val, err}
+}